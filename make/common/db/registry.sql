@@ -23,6 +23,7 @@ create table role (
  role_mask int DEFAULT 0 NOT NULL,
  role_code varchar(20),
  name varchar (20),
+ permissions varchar(1024),
  primary key (role_id)
 );
 /*
@@ -30,10 +31,10 @@ role mask is used for future enhancement when a project member can have multi-ro
 currently set to 0
 */
 
-insert into role (role_code, name) values 
-('MDRWS', 'projectAdmin'),
-('RWS', 'developer'),
-('RS', 'guest');
+insert into role (role_code, name, permissions) values 
+('MDRWS', 'projectAdmin', 'project.read,project.delete,repository.pull,repository.push,repository.delete,tag.delete,scan,member.manage,policy.manage,log.read'),
+('RWS', 'developer', 'project.read,repository.pull,repository.push,log.read'),
+('RS', 'guest', 'project.read,repository.pull,log.read');
 
 
 create table user (
@@ -46,12 +47,13 @@ create table user (
  username varchar(32),
 # 11 bytes is reserved for marking the deleted users.
  email varchar(255),
- password varchar(40) NOT NULL,
+ password varchar(128) NOT NULL,
  realname varchar (255) NOT NULL,
  comment varchar (30),
  deleted tinyint (1) DEFAULT 0 NOT NULL,
  reset_uuid varchar(40) DEFAULT NULL,
  salt varchar(40) DEFAULT NULL,
+ password_version varchar(16) DEFAULT 'sha1',
  sysadmin_flag tinyint (1),
  creation_time timestamp,
  update_time timestamp,
@@ -64,6 +66,75 @@ insert into user (username, email, password, realname, comment, deleted, sysadmi
 ('admin', 'admin@example.com', '', 'system admin', 'admin user',0, 1, NOW(), NOW()),
 ('anonymous', 'anonymous@example.com', '', 'anonymous user', 'anonymous user', 1, 0, NOW(), NOW());
                                                                           
+create table oidc_user (
+ id int NOT NULL AUTO_INCREMENT,
+ user_id int NOT NULL,
+ subiss varchar(255) NOT NULL,
+ secret varchar(255) NOT NULL,
+ /* the json array of the groups in the last ID token of the user */
+ group_names text,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_subiss UNIQUE (subiss),
+ FOREIGN KEY (user_id) REFERENCES user(user_id)
+);
+
+create table user_totp (
+ id int NOT NULL AUTO_INCREMENT,
+ user_id int NOT NULL,
+ secret varchar(255) NOT NULL,
+ enabled tinyint(1) NOT NULL DEFAULT 0,
+ last_step bigint NOT NULL DEFAULT 0,
+ recovery_codes text,
+ cli_secret varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_user_totp UNIQUE (user_id),
+ FOREIGN KEY (user_id) REFERENCES user(user_id)
+);
+
+create table access_token (
+ id int NOT NULL AUTO_INCREMENT,
+ user_id int NOT NULL,
+ name varchar(64) NOT NULL,
+ secret varchar(40) NOT NULL,
+ salt varchar(40) NOT NULL,
+ read_only tinyint(1) NOT NULL DEFAULT 0,
+ expires_at bigint NOT NULL DEFAULT 0,
+ last_used_at bigint NOT NULL DEFAULT 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_access_token_name UNIQUE (user_id, name),
+ FOREIGN KEY (user_id) REFERENCES user(user_id)
+);
+
+create table signing_key (
+ id int NOT NULL AUTO_INCREMENT,
+ key_id varchar(128) NOT NULL,
+ private_key text NOT NULL,
+ certificate text,
+ active tinyint(1) NOT NULL DEFAULT 0,
+ expires_at bigint NOT NULL DEFAULT 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_signing_key_id UNIQUE (key_id)
+);
+
+create table user_session (
+ id int NOT NULL AUTO_INCREMENT,
+ session_id varchar(64) NOT NULL,
+ user_id int NOT NULL,
+ ip varchar(64),
+ user_agent varchar(255),
+ last_active_at bigint NOT NULL DEFAULT 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_session_id UNIQUE (session_id),
+ FOREIGN KEY (user_id) REFERENCES user(user_id)
+);
+
 create table project (
  project_id int NOT NULL AUTO_INCREMENT,
  owner_id int NOT NULL,
@@ -82,6 +153,36 @@ create table project (
 insert into project (owner_id, name, creation_time, update_time, public) values 
 (1, 'library', NOW(), NOW(), 1);
 
+create table project_metadata (
+ id int NOT NULL AUTO_INCREMENT,
+ project_id int NOT NULL,
+ name varchar(255) NOT NULL,
+ value varchar(255),
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_project_id_and_name UNIQUE (project_id,name),
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
+
+create table robot (
+ id int NOT NULL AUTO_INCREMENT,
+ name varchar(64) NOT NULL,
+ description varchar(1024),
+ project_id int NOT NULL,
+ secret varchar(40) NOT NULL,
+ salt varchar(40) NOT NULL,
+ actions varchar(64) NOT NULL,
+ repositories text,
+ expires_at bigint NOT NULL DEFAULT 0,
+ disabled tinyint(1) NOT NULL DEFAULT 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_robot_name UNIQUE (project_id, name),
+ FOREIGN KEY (project_id) REFERENCES project(project_id)
+);
+
 create table project_member (
  project_id int NOT NULL,
  user_id int NOT NULL,
@@ -97,6 +198,42 @@ create table project_member (
 insert into project_member (project_id, user_id, role, creation_time, update_time) values
 (1, 1, 1, NOW(), NOW());
 
+create table user_group (
+ id int NOT NULL AUTO_INCREMENT,
+ group_name varchar(255) NOT NULL,
+ group_type int NOT NULL,
+ ldap_group_dn varchar(512) NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (id)
+);
+
+create table project_group_member (
+ project_id int NOT NULL,
+ group_id int NOT NULL,
+ role int NOT NULL,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (project_id, group_id),
+ FOREIGN KEY (role) REFERENCES role(role_id),
+ FOREIGN KEY (project_id) REFERENCES project(project_id),
+ FOREIGN KEY (group_id) REFERENCES user_group(id)
+);
+
+create table login_failure (
+ id int NOT NULL AUTO_INCREMENT,
+ type varchar(16) NOT NULL,
+ name varchar(255) NOT NULL,
+ failures int NOT NULL DEFAULT 0,
+ last_failure bigint NOT NULL DEFAULT 0,
+ lockouts int NOT NULL DEFAULT 0,
+ locked_until bigint NOT NULL DEFAULT 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_login_failure UNIQUE (type, name)
+);
+
 create table access_log (
  log_id int NOT NULL AUTO_INCREMENT,
  username varchar (32) NOT NULL,
@@ -128,11 +265,20 @@ create table replication_policy (
  name varchar(256),
  project_id int NOT NULL,
  target_id int NOT NULL,
+ direction varchar(10) NOT NULL DEFAULT 'push',
  enabled tinyint(1) NOT NULL DEFAULT 1,
  description text,
  deleted tinyint (1) DEFAULT 0 NOT NULL,
  cron_str varchar(256),
  start_time timestamp NULL,
+ last_run_time timestamp NULL,
+ next_run_time timestamp NULL,
+ schedule_error varchar(1024),
+ retry_max_attempts int NOT NULL DEFAULT 0,
+ retry_initial_delay int NOT NULL DEFAULT 0,
+ retry_multiplier double NOT NULL DEFAULT 0,
+ retry_jitter double NOT NULL DEFAULT 0,
+ filters text,
  creation_time timestamp default CURRENT_TIMESTAMP,
  update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
  PRIMARY KEY (id)
@@ -162,6 +308,8 @@ create table replication_job (
  repository varchar(256) NOT NULL,
  operation  varchar(64) NOT NULL,
  tags   varchar(16384),
+ attempts int NOT NULL DEFAULT 0,
+ retry_time timestamp NULL,
  creation_time timestamp default CURRENT_TIMESTAMP,
  update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
  PRIMARY KEY (id),
@@ -175,11 +323,24 @@ create table img_scan_job (
  repository varchar(256) NOT NULL,
  tag   varchar(128) NOT NULL,
  digest varchar(128),
+ retry_time timestamp NULL,
  creation_time timestamp default CURRENT_TIMESTAMP,
  update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
  PRIMARY KEY (id)
  );
 
+create table scan_all_job (
+ id int NOT NULL AUTO_INCREMENT,
+ status varchar(64) NOT NULL,
+ triggered_by varchar(64) NOT NULL,
+ total int NOT NULL DEFAULT 0,
+ scheduled int NOT NULL DEFAULT 0,
+ error varchar(1024),
+ start_time timestamp NULL,
+ end_time timestamp NULL,
+ PRIMARY KEY (id)
+ );
+
 create table img_scan_overview (
  image_digest varchar(128) NOT NULL,
  scan_job_id int NOT NULL,
@@ -189,11 +350,26 @@ create table img_scan_overview (
  components_overview varchar(2048),
  /* primary key for querying details, in clair it should be the name of the "top layer" */
  details_key varchar(128),
+ /* the kind of the scanner the details key belongs to, it is empty if the image was scanned by clair before the scanner is pluggable */
+ scanner varchar(64),
+ /* the json string to store the severity of each CVE found in the image, it is used to exclude the allowlisted CVEs */
+ vulnerabilities mediumtext,
  creation_time timestamp default CURRENT_TIMESTAMP,
  update_time timestamp default CURRENT_TIMESTAMP on update CURRENT_TIMESTAMP,
  PRIMARY KEY(image_digest)
  );
 
+create table cve_allowlist (
+ id int NOT NULL AUTO_INCREMENT,
+ /* 0 indicates the system level allowlist */
+ project_id int NOT NULL DEFAULT 0,
+ cve_id varchar(64) NOT NULL,
+ expires_at bigint NOT NULL DEFAULT 0,
+ creation_time timestamp default CURRENT_TIMESTAMP,
+ PRIMARY KEY (id),
+ CONSTRAINT unique_cve_allowlist UNIQUE (project_id, cve_id)
+ );
+
 create table properties (
  k varchar(64) NOT NULL,
  v varchar(128) NOT NULL,
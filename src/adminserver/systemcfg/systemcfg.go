@@ -45,6 +45,7 @@ var (
 		common.LDAPSearchPwd,
 		common.MySQLPassword,
 		common.AdminInitialPassword,
+		common.OIDCClientSecret,
 	}
 
 	// all configurations need read from environment variables
@@ -79,7 +80,15 @@ var (
 			env:   "LDAP_TIMEOUT",
 			parse: parseStringToInt,
 		},
-		common.EmailHost: "EMAIL_HOST",
+		common.LDAPGroupBaseDN:        "LDAP_GROUP_BASE_DN",
+		common.LDAPGroupFilter:        "LDAP_GROUP_FILTER",
+		common.LDAPGroupNameAttribute: "LDAP_GROUP_NAME_ATTRIBUTE",
+		common.LDAPGroupScope: &parser{
+			env:   "LDAP_GROUP_SCOPE",
+			parse: parseStringToInt,
+		},
+		common.LDAPGroupMembershipAttr: "LDAP_GROUP_MEMBERSHIP_ATTRIBUTE",
+		common.EmailHost:               "EMAIL_HOST",
 		common.EmailPort: &parser{
 			env:   "EMAIL_PORT",
 			parse: parseStringToInt,
@@ -120,6 +129,74 @@ var (
 			env:   "WITH_CLAIR",
 			parse: parseStringToBool,
 		},
+		common.RepRetryMaxAttempts: &parser{
+			env:   "REP_RETRY_MAX_ATTEMPTS",
+			parse: parseStringToInt,
+		},
+		common.RepRetryInitialDelay: &parser{
+			env:   "REP_RETRY_INITIAL_DELAY",
+			parse: parseStringToInt,
+		},
+		common.RepRetryMultiplier: &parser{
+			env:   "REP_RETRY_MULTIPLIER",
+			parse: parseStringToFloat,
+		},
+		common.RepRetryJitter: &parser{
+			env:   "REP_RETRY_JITTER",
+			parse: parseStringToFloat,
+		},
+		common.RepChunkSize: &parser{
+			env:   "REP_CHUNK_SIZE",
+			parse: parseStringToInt,
+		},
+		common.LoginMaxFailures: &parser{
+			env:   "LOGIN_MAX_FAILURES",
+			parse: parseStringToInt,
+		},
+		common.LoginMaxFailuresPerIP: &parser{
+			env:   "LOGIN_MAX_FAILURES_PER_IP",
+			parse: parseStringToInt,
+		},
+		common.LoginLockDuration: &parser{
+			env:   "LOGIN_LOCK_DURATION",
+			parse: parseStringToInt,
+		},
+		common.PasswordMinLength: &parser{
+			env:   "PASSWORD_MIN_LENGTH",
+			parse: parseStringToInt,
+		},
+		common.PasswordRequireUppercase: &parser{
+			env:   "PASSWORD_REQUIRE_UPPERCASE",
+			parse: parseStringToBool,
+		},
+		common.PasswordRequireLowercase: &parser{
+			env:   "PASSWORD_REQUIRE_LOWERCASE",
+			parse: parseStringToBool,
+		},
+		common.PasswordRequireNumber: &parser{
+			env:   "PASSWORD_REQUIRE_NUMBER",
+			parse: parseStringToBool,
+		},
+		common.PasswordRequireSpecial: &parser{
+			env:   "PASSWORD_REQUIRE_SPECIAL",
+			parse: parseStringToBool,
+		},
+		common.TOTPRequiredForAdmin: &parser{
+			env:   "TOTP_REQUIRED_FOR_ADMIN",
+			parse: parseStringToBool,
+		},
+		common.ScanAllPolicy:    "SCAN_ALL_POLICY",
+		common.Scanner:          "SCANNER",
+		common.ScannerEndpoint:  "SCANNER_ENDPOINT",
+		common.OIDCEndpoint:     "OIDC_ENDPOINT",
+		common.OIDCClientID:     "OIDC_CLIENT_ID",
+		common.OIDCClientSecret: "OIDC_CLIENT_SECRET",
+		common.OIDCScope:        "OIDC_SCOPE",
+		common.OIDCGroupsClaim:  "OIDC_GROUPS_CLAIM",
+		common.OIDCVerifyCert: &parser{
+			env:   "OIDC_VERIFY_CERT",
+			parse: parseStringToBool,
+		},
 	}
 
 	// configurations need read from environment variables
@@ -162,6 +239,13 @@ func parseStringToInt(str string) (interface{}, error) {
 	return strconv.Atoi(str)
 }
 
+func parseStringToFloat(str string) (interface{}, error) {
+	if len(str) == 0 {
+		return 0.0, nil
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
 func parseStringToBool(str string) (interface{}, error) {
 	return strings.ToLower(str) == "true" ||
 		strings.ToLower(str) == "on", nil
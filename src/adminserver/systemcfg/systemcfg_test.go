@@ -40,6 +40,26 @@ func TestParseStringToInt(t *testing.T) {
 	}
 }
 
+func TestParseStringToFloat(t *testing.T) {
+	cases := []struct {
+		input  string
+		result float64
+	}{
+		{"1", 1},
+		{"0.2", 0.2},
+		{"", 0},
+	}
+
+	for _, c := range cases {
+		f, err := parseStringToFloat(c.input)
+		assert.Nil(t, err)
+		assert.Equal(t, c.result, f)
+	}
+
+	_, err := parseStringToFloat("a")
+	assert.NotNil(t, err)
+}
+
 func TestParseStringToBool(t *testing.T) {
 	cases := []struct {
 		input  string
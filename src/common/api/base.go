@@ -152,7 +152,7 @@ func (b *BaseAPI) GetUserIDForRequest() (int, bool, bool) {
 	username, password, ok := b.Ctx.Request.BasicAuth()
 	if ok {
 		log.Infof("Requst with Basic Authentication header, username: %s", username)
-		user, err := auth.Login(models.AuthModel{
+		user, err := auth.LoginCLI(models.AuthModel{
 			Principal: username,
 			Password:  password,
 		})
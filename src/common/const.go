@@ -18,12 +18,20 @@ package common
 const (
 	DBAuth              = "db_auth"
 	LDAPAuth            = "ldap_auth"
+	OIDCAuth            = "oidc_auth"
 	ProCrtRestrEveryone = "everyone"
 	ProCrtRestrAdmOnly  = "adminonly"
 	LDAPScopeBase       = "1"
 	LDAPScopeOnelevel   = "2"
 	LDAPScopeSubtree    = "3"
 
+	ScanAllPolicyNone   = "none"
+	ScanAllPolicyDaily  = "daily"
+	ScanAllPolicyWeekly = "weekly"
+
+	ScannerClair = "clair"
+	ScannerHTTP  = "http"
+
 	RoleProjectAdmin = 1
 	RoleDeveloper    = 2
 	RoleGuest        = 3
@@ -46,6 +54,11 @@ const (
 	LDAPFilter                 = "ldap_filter"
 	LDAPScope                  = "ldap_scope"
 	LDAPTimeout                = "ldap_timeout"
+	LDAPGroupBaseDN            = "ldap_group_base_dn"
+	LDAPGroupFilter            = "ldap_group_filter"
+	LDAPGroupNameAttribute     = "ldap_group_name_attribute"
+	LDAPGroupScope             = "ldap_group_scope"
+	LDAPGroupMembershipAttr    = "ldap_group_membership_attribute"
 	TokenServiceURL            = "token_service_url"
 	RegistryURL                = "registry_url"
 	EmailHost                  = "email_host"
@@ -65,4 +78,27 @@ const (
 	AdmiralEndpoint            = "admiral_url"
 	WithNotary                 = "with_notary"
 	WithClair                  = "with_clair"
+	RepRetryMaxAttempts        = "rep_retry_max_attempts"
+	RepRetryInitialDelay       = "rep_retry_initial_delay"
+	RepRetryMultiplier         = "rep_retry_multiplier"
+	RepRetryJitter             = "rep_retry_jitter"
+	RepChunkSize               = "rep_chunk_size"
+	OIDCEndpoint               = "oidc_endpoint"
+	OIDCClientID               = "oidc_client_id"
+	OIDCClientSecret           = "oidc_client_secret"
+	OIDCScope                  = "oidc_scope"
+	OIDCGroupsClaim            = "oidc_groups_claim"
+	OIDCVerifyCert             = "oidc_verify_cert"
+	LoginMaxFailures           = "login_max_failures"
+	LoginMaxFailuresPerIP      = "login_max_failures_per_ip"
+	LoginLockDuration          = "login_lock_duration"
+	PasswordMinLength          = "password_min_length"
+	PasswordRequireUppercase   = "password_require_uppercase"
+	PasswordRequireLowercase   = "password_require_lowercase"
+	PasswordRequireNumber      = "password_require_number"
+	PasswordRequireSpecial     = "password_require_special"
+	TOTPRequiredForAdmin       = "totp_required_for_admin"
+	ScanAllPolicy              = "scan_all_policy"
+	Scanner                    = "scanner"
+	ScannerEndpoint            = "scanner_endpoint"
 )
@@ -0,0 +1,88 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddAccessToken adds a personal access token, the secret of the token
+// must have been hashed with the salt
+func AddAccessToken(token *models.AccessToken) (int64, error) {
+	token.CreationTime = time.Now()
+	return GetOrmer().Insert(token)
+}
+
+// GetAccessToken returns the token specified by ID, nil is returned if the
+// token does not exist
+func GetAccessToken(id int64) (*models.AccessToken, error) {
+	token := &models.AccessToken{
+		ID: id,
+	}
+	if err := GetOrmer().Read(token); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetAccessTokenByName returns the token of the user specified by name, nil
+// is returned if the token does not exist
+func GetAccessTokenByName(userID int, name string) (*models.AccessToken, error) {
+	token := &models.AccessToken{
+		UserID: userID,
+		Name:   name,
+	}
+	if err := GetOrmer().Read(token, "UserID", "Name"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// ListAccessTokens returns the tokens of the user
+func ListAccessTokens(userID int) ([]*models.AccessToken, error) {
+	tokens := []*models.AccessToken{}
+	_, err := GetOrmer().QueryTable(models.AccessTokenTable).
+		Filter("user_id", userID).
+		OrderBy("name").
+		All(&tokens)
+	return tokens, err
+}
+
+// UpdateAccessTokenLastUsed records the time when the token is used
+func UpdateAccessTokenLastUsed(id int64, t time.Time) error {
+	_, err := GetOrmer().Update(&models.AccessToken{
+		ID:         id,
+		LastUsedAt: t.Unix(),
+	}, "LastUsedAt")
+	return err
+}
+
+// DeleteAccessToken deletes the token specified by ID, the token is revoked
+// immediately
+func DeleteAccessToken(id int64) error {
+	_, err := GetOrmer().Delete(&models.AccessToken{
+		ID: id,
+	})
+	return err
+}
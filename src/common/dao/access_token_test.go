@@ -0,0 +1,65 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestAccessToken(t *testing.T) {
+	token := &models.AccessToken{
+		UserID:   currentUser.UserID,
+		Name:     "token_for_test_dao",
+		Secret:   "secret",
+		Salt:     "salt",
+		ReadOnly: true,
+	}
+	id, err := AddAccessToken(token)
+	require.Nil(t, err)
+	defer func() {
+		if err := DeleteAccessToken(id); err != nil {
+			t.Errorf("failed to clear up token %d: %v", id, err)
+		}
+	}()
+
+	tk, err := GetAccessTokenByName(currentUser.UserID, token.Name)
+	require.Nil(t, err)
+	require.NotNil(t, tk)
+	assert.Equal(t, id, tk.ID)
+	assert.True(t, tk.ReadOnly)
+	assert.Equal(t, int64(0), tk.LastUsedAt)
+
+	now := time.Now()
+	require.Nil(t, UpdateAccessTokenLastUsed(id, now))
+	tk, err = GetAccessToken(id)
+	require.Nil(t, err)
+	require.NotNil(t, tk)
+	assert.Equal(t, now.Unix(), tk.LastUsedAt)
+	assert.Equal(t, "secret", tk.Secret)
+
+	tokens, err := ListAccessTokens(currentUser.UserID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(tokens))
+	assert.Equal(t, token.Name, tokens[0].Name)
+
+	tk, err = GetAccessTokenByName(currentUser.UserID, "non_exist_token")
+	require.Nil(t, err)
+	assert.Nil(t, tk)
+}
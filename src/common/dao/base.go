@@ -97,3 +97,13 @@ func escape(str string) string {
 	str = strings.Replace(str, `_`, `\_`, -1)
 	return str
 }
+
+// paramPlaceholder returns a string like "?,?,?" which contains n
+// placeholders for the "in" clause of raw SQL
+func paramPlaceholder(n int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = "?"
+	}
+	return strings.Join(placeholders, ",")
+}
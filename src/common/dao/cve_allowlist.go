@@ -0,0 +1,86 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// GetCVEAllowlist returns the CVE allowlist of the project ordered by CVE ID,
+// the system level allowlist is returned if the project ID is 0
+func GetCVEAllowlist(projectID int64) ([]*models.CVEAllowlistItem, error) {
+	items := []*models.CVEAllowlistItem{}
+	_, err := GetOrmer().QueryTable(models.CVEAllowlistTable).
+		Filter("project_id", projectID).
+		OrderBy("cve_id").
+		All(&items)
+	return items, err
+}
+
+// SetCVEAllowlist replaces the CVE allowlist of the project with the items,
+// the project ID is 0 for the system level allowlist
+func SetCVEAllowlist(projectID int64, items []*models.CVEAllowlistItem) (err error) {
+	o := orm.NewOrm()
+	if err = o.Begin(); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			o.Rollback()
+			return
+		}
+		err = o.Commit()
+	}()
+
+	if _, err = o.QueryTable(models.CVEAllowlistTable).
+		Filter("project_id", projectID).
+		Delete(); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, item := range items {
+		item.ID = 0
+		item.ProjectID = projectID
+		item.CreationTime = now
+		if _, err = o.Insert(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAllowlistedCVEs returns the IDs of the CVEs which are allowlisted by the
+// system or the project and have not expired
+func GetAllowlistedCVEs(projectID int64) (map[string]bool, error) {
+	items := []*models.CVEAllowlistItem{}
+	cond := orm.NewCondition()
+	cond = cond.And("project_id__in", 0, projectID).
+		AndCond(orm.NewCondition().
+			Or("expires_at", 0).
+			Or("expires_at__gt", time.Now().Unix()))
+	if _, err := GetOrmer().QueryTable(models.CVEAllowlistTable).
+		SetCond(cond).
+		All(&items, "cve_id"); err != nil {
+		return nil, err
+	}
+	cves := map[string]bool{}
+	for _, item := range items {
+		cves[item.CVEID] = true
+	}
+	return cves, nil
+}
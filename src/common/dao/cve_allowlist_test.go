@@ -0,0 +1,63 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestCVEAllowlist(t *testing.T) {
+	defer func() {
+		if err := ClearTable(models.CVEAllowlistTable); err != nil {
+			t.Errorf("failed to clear up CVE allowlist: %v", err)
+		}
+	}()
+
+	require.Nil(t, SetCVEAllowlist(0, []*models.CVEAllowlistItem{
+		{CVEID: "CVE-2016-2177"},
+		{CVEID: "CVE-2015-8776", ExpiresAt: time.Now().Add(-time.Hour).Unix()},
+	}))
+	require.Nil(t, SetCVEAllowlist(1, []*models.CVEAllowlistItem{
+		{CVEID: "CVE-2016-2178", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	}))
+
+	items, err := GetCVEAllowlist(0)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(items))
+	assert.Equal(t, "CVE-2015-8776", items[0].CVEID)
+	assert.Equal(t, "CVE-2016-2177", items[1].CVEID)
+
+	// the expired items are excluded
+	cves, err := GetAllowlistedCVEs(1)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]bool{
+		"CVE-2016-2177": true,
+		"CVE-2016-2178": true,
+	}, cves)
+	cves, err = GetAllowlistedCVEs(2)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]bool{"CVE-2016-2177": true}, cves)
+
+	// the allowlist is replaced
+	require.Nil(t, SetCVEAllowlist(1, []*models.CVEAllowlistItem{}))
+	items, err = GetCVEAllowlist(1)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(items))
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/astaxie/beego/orm"
 	//"github.com/vmware/harbor/src/common/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/utils"
 	"github.com/vmware/harbor/src/common/utils/log"
@@ -383,6 +384,25 @@ func TestLoginByEmail(t *testing.T) {
 	}
 }
 
+func TestLoginUpgradesLegacyPassword(t *testing.T) {
+	salt := utils.GenerateRandomString()
+	_, err := GetOrmer().Raw(`update user set password=?, salt=?, password_version=? where username=?`,
+		utils.Encrypt("Abc12345", salt), salt, utils.PasswordVersionSHA1, username).Exec()
+	require.Nil(t, err)
+
+	loginUser, err := LoginByDb(models.AuthModel{
+		Principal: username,
+		Password:  "Abc12345",
+	})
+	require.Nil(t, err)
+	require.NotNil(t, loginUser)
+
+	user, err := getUserWithPassword(loginUser.UserID)
+	require.Nil(t, err)
+	assert.Equal(t, utils.CurrentPasswordVersion, user.PasswordVersion)
+	assert.True(t, utils.MatchPassword("Abc12345", user.Salt, user.PasswordVersion, user.Password))
+}
+
 var currentUser *models.User
 
 func TestGetUser(t *testing.T) {
@@ -1544,6 +1564,89 @@ func TestGetJobByStatus(t *testing.T) {
 	}
 }
 
+func TestScheduleRepJobRetry(t *testing.T) {
+	assert := assert.New(t)
+	id1, err := AddRepJob(models.RepJob{
+		Repository: "library/ubuntuc",
+		PolicyID:   policyID,
+		Operation:  "transfer",
+	})
+	assert.Nil(err)
+	defer DeleteRepJob(id1)
+	id2, err := AddRepJob(models.RepJob{
+		Repository: "library/ubuntud",
+		PolicyID:   policyID,
+		Operation:  "transfer",
+	})
+	assert.Nil(err)
+	defer DeleteRepJob(id2)
+
+	now := time.Now()
+	assert.Nil(ScheduleRepJobRetry(id1, now.Add(-time.Minute)))
+	assert.Nil(ScheduleRepJobRetry(id2, now.Add(time.Hour)))
+	assert.NotNil(ScheduleRepJobRetry(id2+100, now))
+
+	j, err := GetRepJob(id2)
+	assert.Nil(err)
+	assert.Equal(models.JobRetrying, j.Status)
+
+	jobs, err := GetRepJobsToRetry(now)
+	assert.Nil(err)
+	if assert.Equal(1, len(jobs)) {
+		assert.Equal(id1, jobs[0].ID)
+	}
+
+	jobs, err = GetRepJobsToRetry(now.Add(2 * time.Hour))
+	assert.Nil(err)
+	assert.Equal(2, len(jobs))
+}
+
+func TestIncreaseRepJobAttempts(t *testing.T) {
+	assert := assert.New(t)
+	id, err := AddRepJob(models.RepJob{
+		Repository: "library/ubuntue",
+		PolicyID:   policyID,
+		Operation:  "transfer",
+	})
+	assert.Nil(err)
+	defer DeleteRepJob(id)
+
+	assert.Nil(IncreaseRepJobAttempts(id))
+	assert.Nil(IncreaseRepJobAttempts(id))
+	j, err := GetRepJob(id)
+	assert.Nil(err)
+	assert.Equal(2, j.Attempts)
+
+	assert.NotNil(IncreaseRepJobAttempts(id + 100))
+}
+
+func TestRepPolicyFilters(t *testing.T) {
+	assert := assert.New(t)
+	policy, err := GetRepPolicy(policyID)
+	assert.Nil(err)
+	assert.Equal(0, len(policy.FilterList))
+
+	policy.FilterList = []*models.RepFilter{
+		&models.RepFilter{
+			Kind:    models.RepFilterKindTag,
+			Type:    models.RepFilterTypeInclude,
+			Pattern: "release-*",
+		},
+	}
+	assert.Nil(UpdateRepPolicy(policy))
+
+	policy, err = GetRepPolicy(policyID)
+	assert.Nil(err)
+	assert.Equal(1, len(policy.FilterList))
+	assert.Equal("release-*", policy.FilterList[0].Pattern)
+
+	policy.FilterList = nil
+	assert.Nil(UpdateRepPolicy(policy))
+	policy, err = GetRepPolicy(policyID)
+	assert.Nil(err)
+	assert.Equal(0, len(policy.FilterList))
+}
+
 func TestGetOrmer(t *testing.T) {
 	o := GetOrmer()
 	if o == nil {
@@ -1694,6 +1797,34 @@ func TestUpdateScanJobStatus(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestScheduleScanJobRetry(t *testing.T) {
+	assert := assert.New(t)
+	id1, err := AddScanJob(sj1)
+	assert.Nil(err)
+	id2, err := AddScanJob(sj2)
+	assert.Nil(err)
+
+	now := time.Now()
+	assert.Nil(ScheduleScanJobRetry(id1, now.Add(-time.Minute)))
+	assert.Nil(ScheduleScanJobRetry(id2, now.Add(time.Hour)))
+	jobs, err := GetScanJobsToRetry(now)
+	assert.Nil(err)
+	if assert.Equal(1, len(jobs)) {
+		assert.Equal(id1, jobs[0].ID)
+	}
+
+	assert.Nil(UpdateScanJobStatus(id2, models.JobRunning))
+	assert.Nil(ResetRunningScanJobs())
+	jobs, err = GetScanJobsByStatus(models.JobPending)
+	assert.Nil(err)
+	if assert.Equal(1, len(jobs)) {
+		assert.Equal(id2, jobs[0].ID)
+	}
+
+	err = ClearTable(models.ScanJobTable)
+	assert.Nil(err)
+}
+
 func TestImgScanOverview(t *testing.T) {
 	assert := assert.New(t)
 	err := ClearTable(models.ScanOverviewTable)
@@ -1722,11 +1853,16 @@ func TestImgScanOverview(t *testing.T) {
 			},
 		},
 	}
-	err = UpdateImgScanOverview(digest, pk, models.SevMedium, comp)
+	vulns := map[string]models.Severity{
+		"CVE-2016-2177": models.SevMedium,
+	}
+	err = UpdateImgScanOverview(digest, "http", pk, models.SevMedium, comp, vulns)
 	assert.Nil(err)
 	res, err = GetImgScanOverview(digest)
 	assert.Nil(err)
 	assert.Equal(pk, res.DetailsKey)
+	assert.Equal("http", res.Scanner)
 	assert.Equal(int(models.SevMedium), res.Sev)
 	assert.Equal(2, res.CompOverview.Summary[0].Count)
+	assert.Equal(vulns, res.Vulns)
 }
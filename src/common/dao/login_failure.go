@@ -0,0 +1,141 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// GetLoginFailure returns the failures of the username or source IP, nil is
+// returned if there is no failure
+func GetLoginFailure(typ, name string) (*models.LoginFailure, error) {
+	lf := &models.LoginFailure{
+		Type: typ,
+		Name: name,
+	}
+	if err := GetOrmer().Read(lf, "Type", "Name"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return lf, nil
+}
+
+// IncreaseLoginFailure increases the failure count of the username or source
+// IP and returns the updated record, the count restarts from 1 if the last
+// failure happened before the resetBefore. The lockout count restarts from 0
+// if neither the last failure nor the end of the last lockout happened after
+// the forgiveBefore. The counts are updated in one statement so that the
+// failures from different UI instances are all counted
+func IncreaseLoginFailure(typ, name string, now, resetBefore, forgiveBefore time.Time) (*models.LoginFailure, error) {
+	o := GetOrmer()
+	// the lockouts must be updated before last_failure as MySQL uses the
+	// updated values in the later assignments
+	sql := `update login_failure
+		set failures = case when last_failure < ? then 1 else failures + 1 end,
+			lockouts = case when last_failure < ? and locked_until < ? then 0 else lockouts end,
+			last_failure = ?, update_time = ?
+		where type = ? and name = ?`
+	increase := func() (int64, error) {
+		result, err := o.Raw(sql, resetBefore.Unix(), forgiveBefore.Unix(), forgiveBefore.Unix(),
+			now.Unix(), now, typ, name).Exec()
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	n, err := increase()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		_, err = o.Insert(&models.LoginFailure{
+			Type:         typ,
+			Name:         name,
+			Failures:     1,
+			LastFailure:  now.Unix(),
+			CreationTime: now,
+			UpdateTime:   now,
+		})
+		// the record may have been inserted by another instance
+		if err != nil {
+			if n, e := increase(); e != nil || n == 0 {
+				return nil, err
+			}
+		}
+	}
+
+	return GetLoginFailure(typ, name)
+}
+
+// LockLoginFailure locks the username or source IP until the time and clears
+// the failure count. It returns false if the record has been changed by
+// others since it was read, which means the lockout is done by others
+func LockLoginFailure(lf *models.LoginFailure, lockedUntil time.Time) (bool, error) {
+	sql := `update login_failure
+		set failures = 0, lockouts = lockouts + 1, locked_until = ?, update_time = ?
+		where id = ? and failures = ? and last_failure = ?`
+	result, err := GetOrmer().Raw(sql, lockedUntil.Unix(), time.Now(),
+		lf.ID, lf.Failures, lf.LastFailure).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetLockedLoginFailures returns the usernames and source IPs which are
+// locked at the time
+func GetLockedLoginFailures(now time.Time) ([]*models.LoginFailure, error) {
+	lfs := []*models.LoginFailure{}
+	_, err := GetOrmer().QueryTable(models.LoginFailureTable).
+		Filter("locked_until__gt", now.Unix()).
+		OrderBy("-locked_until").
+		All(&lfs)
+	return lfs, err
+}
+
+// GetLoginFailureByID returns the record specified by ID, nil is returned if
+// it does not exist
+func GetLoginFailureByID(id int64) (*models.LoginFailure, error) {
+	lf := &models.LoginFailure{
+		ID: id,
+	}
+	if err := GetOrmer().Read(lf); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return lf, nil
+}
+
+// DeleteLoginFailure deletes the record of the username or source IP, which
+// unlocks it and resets the duration of the next lockout
+func DeleteLoginFailure(typ, name string) error {
+	_, err := GetOrmer().QueryTable(models.LoginFailureTable).
+		Filter("type", typ).
+		Filter("name", name).
+		Delete()
+	return err
+}
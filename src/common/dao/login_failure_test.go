@@ -0,0 +1,94 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestLoginFailure(t *testing.T) {
+	typ, name := models.LoginFailureTypeIP, "10.0.0.1"
+	defer func() {
+		if err := DeleteLoginFailure(typ, name); err != nil {
+			t.Errorf("failed to clear up login failures of %s: %v", name, err)
+		}
+	}()
+
+	now := time.Now()
+
+	// the first failure
+	lf, err := IncreaseLoginFailure(typ, name, now, now.Add(-time.Minute), now.Add(-time.Hour))
+	require.Nil(t, err)
+	require.NotNil(t, lf)
+	assert.Equal(t, 1, lf.Failures)
+
+	// the second failure
+	lf, err = IncreaseLoginFailure(typ, name, now.Add(time.Second), now.Add(-time.Minute), now.Add(-time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 2, lf.Failures)
+
+	// the failures before the window are not counted
+	lf, err = IncreaseLoginFailure(typ, name, now.Add(2*time.Minute), now.Add(time.Minute), now.Add(-time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 1, lf.Failures)
+
+	// lock
+	locked, err := LockLoginFailure(lf, now.Add(time.Hour))
+	require.Nil(t, err)
+	assert.True(t, locked)
+
+	// lock again with the stale record
+	locked, err = LockLoginFailure(lf, now.Add(time.Hour))
+	require.Nil(t, err)
+	assert.False(t, locked)
+
+	lf, err = GetLoginFailure(typ, name)
+	require.Nil(t, err)
+	require.NotNil(t, lf)
+	assert.Equal(t, 0, lf.Failures)
+	assert.Equal(t, 1, lf.Lockouts)
+	assert.True(t, lf.IsLocked(now))
+
+	lfs, err := GetLockedLoginFailures(now)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(lfs))
+	assert.Equal(t, lf.ID, lfs[0].ID)
+
+	lf, err = GetLoginFailureByID(lf.ID)
+	require.Nil(t, err)
+	require.NotNil(t, lf)
+
+	// the lockouts are kept if it failed or was locked after the quiet period began
+	lf, err = IncreaseLoginFailure(typ, name, now.Add(3*time.Minute), now.Add(time.Minute), now)
+	require.Nil(t, err)
+	assert.Equal(t, 1, lf.Lockouts)
+
+	// the lockouts are forgiven after the quiet period
+	lf, err = IncreaseLoginFailure(typ, name, now.Add(48*time.Hour), now.Add(47*time.Hour), now.Add(24*time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 0, lf.Lockouts)
+	assert.Equal(t, 1, lf.Failures)
+
+	// unlock
+	require.Nil(t, DeleteLoginFailure(typ, name))
+	lf, err = GetLoginFailure(typ, name)
+	require.Nil(t, err)
+	assert.Nil(t, lf)
+}
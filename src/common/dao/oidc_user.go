@@ -0,0 +1,66 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddOIDCUser links the user with the subject in the OIDC provider
+func AddOIDCUser(ou *models.OIDCUser) (int64, error) {
+	now := time.Now()
+	ou.CreationTime = now
+	ou.UpdateTime = now
+	return GetOrmer().Insert(ou)
+}
+
+// GetOIDCUserBySubIss returns the OIDC user specified by the subject and
+// issuer, nil is returned if it does not exist
+func GetOIDCUserBySubIss(subIss string) (*models.OIDCUser, error) {
+	return getOIDCUser(&models.OIDCUser{SubIss: subIss}, "SubIss")
+}
+
+// GetOIDCUserByUserID returns the OIDC user linked with the user, nil is
+// returned if it does not exist
+func GetOIDCUserByUserID(userID int) (*models.OIDCUser, error) {
+	return getOIDCUser(&models.OIDCUser{UserID: userID}, "UserID")
+}
+
+func getOIDCUser(ou *models.OIDCUser, cols ...string) (*models.OIDCUser, error) {
+	if err := GetOrmer().Read(ou, cols...); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ou, nil
+}
+
+// UpdateOIDCUserSecret updates the CLI secret of the OIDC user
+func UpdateOIDCUserSecret(ou *models.OIDCUser) error {
+	ou.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(ou, "Secret", "UpdateTime")
+	return err
+}
+
+// UpdateOIDCUserGroups updates the groups of the OIDC user
+func UpdateOIDCUserGroups(ou *models.OIDCUser) error {
+	ou.UpdateTime = time.Now()
+	_, err := GetOrmer().Update(ou, "GroupNames", "UpdateTime")
+	return err
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestOIDCUser(t *testing.T) {
+	subIss := "subject_for_test_oidc_user" + "https://issuer.example.com"
+
+	// not exist
+	ou, err := GetOIDCUserBySubIss(subIss)
+	require.Nil(t, err)
+	assert.Nil(t, ou)
+
+	// add
+	id, err := AddOIDCUser(&models.OIDCUser{
+		UserID: currentUser.UserID,
+		SubIss: subIss,
+		Secret: "secret",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if _, err := GetOrmer().Delete(&models.OIDCUser{ID: id}); err != nil {
+			t.Errorf("failed to clear up OIDC user %d: %v", id, err)
+		}
+	}()
+
+	// get by subject and issuer
+	ou, err = GetOIDCUserBySubIss(subIss)
+	require.Nil(t, err)
+	require.NotNil(t, ou)
+	assert.Equal(t, currentUser.UserID, ou.UserID)
+	assert.Equal(t, "secret", ou.Secret)
+
+	// update the secret and get by user ID
+	ou.Secret = "new_secret"
+	require.Nil(t, UpdateOIDCUserSecret(ou))
+	ou, err = GetOIDCUserByUserID(currentUser.UserID)
+	require.Nil(t, err)
+	require.NotNil(t, ou)
+	assert.Equal(t, id, ou.ID)
+	assert.Equal(t, "new_secret", ou.Secret)
+
+	// update the groups
+	ou.GroupNames = `["developers"]`
+	require.Nil(t, UpdateOIDCUserGroups(ou))
+	ou, err = GetOIDCUserBySubIss(subIss)
+	require.Nil(t, err)
+	require.NotNil(t, ou)
+	assert.Equal(t, `["developers"]`, ou.GroupNames)
+	assert.Equal(t, "new_secret", ou.Secret)
+}
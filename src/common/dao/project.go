@@ -15,7 +15,6 @@
 package dao
 
 import (
-	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/models"
 
 	"fmt"
@@ -332,17 +331,7 @@ func projectQueryConditions(owner, name string, public *bool, member string,
 
 		if role > 0 {
 			sql += ` and pm.role = ?`
-			roleID := 0
-			switch role {
-			case common.RoleProjectAdmin:
-				roleID = 1
-			case common.RoleDeveloper:
-				roleID = 2
-			case common.RoleGuest:
-				roleID = 3
-
-			}
-			params = append(params, roleID)
+			params = append(params, role)
 		}
 	}
 
@@ -0,0 +1,70 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddProjectMetadata adds a piece of metadata to the project
+func AddProjectMetadata(meta *models.ProjectMetadata) error {
+	now := time.Now()
+	meta.CreationTime = now
+	meta.UpdateTime = now
+	_, err := GetOrmer().Insert(meta)
+	return err
+}
+
+// DeleteProjectMetadata deletes the metadata of the project whose name
+// is in the name list, all metadata of the project will be deleted if
+// the list is empty
+func DeleteProjectMetadata(projectID int64, name ...string) error {
+	qs := GetOrmer().QueryTable(models.ProjectMetadataTable).
+		Filter("project_id", projectID)
+	if len(name) > 0 {
+		qs = qs.Filter("name__in", name)
+	}
+	_, err := qs.Delete()
+	return err
+}
+
+// UpdateProjectMetadata updates the value of the metadata whose project ID
+// and name are same with the ones of meta
+func UpdateProjectMetadata(meta *models.ProjectMetadata) error {
+	_, err := GetOrmer().QueryTable(models.ProjectMetadataTable).
+		Filter("project_id", meta.ProjectID).
+		Filter("name", meta.Name).
+		Update(orm.Params{
+			"value":       meta.Value,
+			"update_time": time.Now(),
+		})
+	return err
+}
+
+// GetProjectMetadata returns the metadata of the project whose name is in
+// the name list, all metadata of the project will be returned if the list
+// is empty
+func GetProjectMetadata(projectID int64, name ...string) ([]*models.ProjectMetadata, error) {
+	metas := []*models.ProjectMetadata{}
+	qs := GetOrmer().QueryTable(models.ProjectMetadataTable).
+		Filter("project_id", projectID)
+	if len(name) > 0 {
+		qs = qs.Filter("name__in", name)
+	}
+	_, err := qs.All(&metas)
+	return metas, err
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestProjectMetadata(t *testing.T) {
+	id, err := AddProject(models.Project{
+		OwnerID: currentUser.UserID,
+		Name:    "project_for_test_metadata",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if err := delProjPermanent(id); err != nil {
+			t.Errorf("failed to clear up project %d: %v", id, err)
+		}
+	}()
+
+	// add
+	require.Nil(t, AddProjectMetadata(&models.ProjectMetadata{
+		ProjectID: id,
+		Name:      models.ProMetaEnableContentTrust,
+		Value:     "true",
+	}))
+	require.Nil(t, AddProjectMetadata(&models.ProjectMetadata{
+		ProjectID: id,
+		Name:      models.ProMetaSeverity,
+		Value:     "high",
+	}))
+
+	// get all
+	metas, err := GetProjectMetadata(id)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(metas))
+
+	// update and get by name
+	require.Nil(t, UpdateProjectMetadata(&models.ProjectMetadata{
+		ProjectID: id,
+		Name:      models.ProMetaSeverity,
+		Value:     "low",
+	}))
+	metas, err = GetProjectMetadata(id, models.ProMetaSeverity)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, "low", metas[0].Value)
+
+	// delete by name
+	require.Nil(t, DeleteProjectMetadata(id, models.ProMetaSeverity))
+	metas, err = GetProjectMetadata(id)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(metas))
+	assert.Equal(t, models.ProMetaEnableContentTrust, metas[0].Name)
+
+	// delete all
+	require.Nil(t, DeleteProjectMetadata(id))
+	metas, err = GetProjectMetadata(id)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(metas))
+}
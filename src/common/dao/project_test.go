@@ -60,7 +60,14 @@ func TestDeleteProject(t *testing.T) {
 }
 
 func delProjPermanent(id int64) error {
-	_, err := GetOrmer().QueryTable("access_log").
+	_, err := GetOrmer().QueryTable(models.ProjectMetadataTable).
+		Filter("ProjectID", id).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	_, err = GetOrmer().QueryTable("access_log").
 		Filter("ProjectID", id).
 		Delete()
 	if err != nil {
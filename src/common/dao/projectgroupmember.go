@@ -0,0 +1,109 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddProjectGroupMember inserts a record to table project_group_member
+func AddProjectGroupMember(projectID int64, groupID int, role int) error {
+	sql := `insert into project_group_member (project_id, group_id, role)
+		values (?, ?, ?)`
+	_, err := GetOrmer().Raw(sql, projectID, groupID, role).Exec()
+	return err
+}
+
+// UpdateProjectGroupMember updates the record in table project_group_member
+func UpdateProjectGroupMember(projectID int64, groupID int, role int) error {
+	sql := `update project_group_member set role = ?
+		where project_id = ? and group_id = ?`
+	_, err := GetOrmer().Raw(sql, role, projectID, groupID).Exec()
+	return err
+}
+
+// DeleteProjectGroupMember deletes the record from table project_group_member
+func DeleteProjectGroupMember(projectID int64, groupID int) error {
+	sql := `delete from project_group_member where project_id = ? and group_id = ?`
+	_, err := GetOrmer().Raw(sql, projectID, groupID).Exec()
+	return err
+}
+
+// DeleteProjectGroupMembers removes all groups from the project
+func DeleteProjectGroupMembers(projectID int64) error {
+	sql := `delete from project_group_member where project_id = ?`
+	_, err := GetOrmer().Raw(sql, projectID).Exec()
+	return err
+}
+
+const projectGroupMemberSQL = `select pgm.project_id, pgm.group_id, ug.group_name,
+		ug.ldap_group_dn, r.role_id as role, r.name as rolename
+	from project_group_member pgm
+	join user_group ug
+	on pgm.group_id = ug.id
+	join role r
+	on pgm.role = r.role_id
+	where pgm.project_id = ?`
+
+// GetProjectGroupMembers returns all groups which are members of the project
+func GetProjectGroupMembers(projectID int64) ([]*models.ProjectGroupMember, error) {
+	members := []*models.ProjectGroupMember{}
+	_, err := GetOrmer().Raw(projectGroupMemberSQL+` order by ug.group_name`,
+		projectID).QueryRows(&members)
+	return members, err
+}
+
+// GetProjectGroupMember returns the group member of the project, nil is
+// returned if the group is not a member of the project
+func GetProjectGroupMember(projectID int64, groupID int) (*models.ProjectGroupMember, error) {
+	member := &models.ProjectGroupMember{}
+	err := GetOrmer().Raw(projectGroupMemberSQL+` and pgm.group_id = ?`,
+		projectID, groupID).QueryRow(member)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// GetGroupProjectRoles returns the roles that the groups have according to
+// the project
+func GetGroupProjectRoles(groupIDs []int, projectID int64) ([]models.Role, error) {
+	roles := []models.Role{}
+	if len(groupIDs) == 0 {
+		return roles, nil
+	}
+
+	sql := `select *
+		from role
+		where role_id in
+			(
+				select role
+				from project_group_member
+				where project_id = ? and group_id in (` + paramPlaceholder(len(groupIDs)) + `)
+			)`
+
+	params := []interface{}{projectID}
+	for _, id := range groupIDs {
+		params = append(params, id)
+	}
+	if _, err := GetOrmer().Raw(sql, params...).QueryRows(&roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
@@ -25,16 +25,20 @@ import (
 // Register is used for user to register, the password is encrypted before the record is inserted into database.
 func Register(user models.User) (int64, error) {
 	o := GetOrmer()
-	p, err := o.Raw("insert into user (username, password, realname, email, comment, salt, sysadmin_flag, creation_time, update_time) values (?, ?, ?, ?, ?, ?, ?, ?, ?)").Prepare()
+	p, err := o.Raw("insert into user (username, password, realname, email, comment, salt, password_version, sysadmin_flag, creation_time, update_time) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").Prepare()
 	if err != nil {
 		return 0, err
 	}
 	defer p.Close()
 
 	salt := utils.GenerateRandomString()
+	password, err := utils.EncryptPassword(user.Password, salt, utils.CurrentPasswordVersion)
+	if err != nil {
+		return 0, err
+	}
 
 	now := time.Now()
-	r, err := p.Exec(user.Username, utils.Encrypt(user.Password, salt), user.Realname, user.Email, user.Comment, salt, user.HasAdminRole, now, now)
+	r, err := p.Exec(user.Username, password, user.Realname, user.Email, user.Comment, salt, utils.CurrentPasswordVersion, user.HasAdminRole, now, now)
 
 	if err != nil {
 		return 0, err
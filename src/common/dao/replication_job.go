@@ -15,6 +15,7 @@
 package dao
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -105,14 +106,24 @@ func FilterRepTargets(name string) ([]*models.RepTarget, error) {
 // AddRepPolicy ...
 func AddRepPolicy(policy models.RepPolicy) (int64, error) {
 	o := GetOrmer()
-	sql := `insert into replication_policy (name, project_id, target_id, enabled, description, cron_str, start_time, creation_time, update_time ) values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	sql := `insert into replication_policy (name, project_id, target_id, direction, enabled, description, cron_str,
+		retry_max_attempts, retry_initial_delay, retry_multiplier, retry_jitter, filters, start_time, creation_time, update_time )
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if err := genFiltersForPolicy(&policy); err != nil {
+		return 0, err
+	}
 	p, err := o.Raw(sql).Prepare()
 	if err != nil {
 		return 0, err
 	}
 
+	if len(policy.Direction) == 0 {
+		policy.Direction = models.RepDirectionPush
+	}
+
 	params := []interface{}{}
-	params = append(params, policy.Name, policy.ProjectID, policy.TargetID, policy.Enabled, policy.Description, policy.CronStr)
+	params = append(params, policy.Name, policy.ProjectID, policy.TargetID, policy.Direction, policy.Enabled, policy.Description, policy.CronStr,
+		policy.RetryMaxAttempts, policy.RetryInitialDelay, policy.RetryMultiplier, policy.RetryJitter, policy.Filters)
 	now := time.Now()
 	if policy.Enabled == 1 {
 		params = append(params, now)
@@ -143,6 +154,10 @@ func GetRepPolicy(id int64) (*models.RepPolicy, error) {
 		return nil, err
 	}
 
+	if err := genFilterListForPolicy(&policy); err != nil {
+		return nil, err
+	}
+
 	return &policy, nil
 }
 
@@ -153,8 +168,10 @@ func FilterRepPolicies(name string, projectID int64) ([]*models.RepPolicy, error
 	var args []interface{}
 
 	sql := `select rp.id, rp.project_id, rp.target_id, 
-				rt.name as target_name, rp.name, rp.enabled, rp.description,
-				rp.cron_str, rp.start_time, rp.creation_time, rp.update_time, 
+				rt.name as target_name, rp.name, rp.direction, rp.enabled, rp.description,
+				rp.cron_str, rp.start_time, rp.last_run_time, rp.next_run_time,
+				rp.schedule_error, rp.retry_max_attempts, rp.retry_initial_delay,
+				rp.retry_multiplier, rp.retry_jitter, rp.filters, rp.creation_time, rp.update_time, 
 				count(rj.status) as error_job_count 
 			from replication_policy rp 
 			left join replication_target rt on rp.target_id=rt.id 
@@ -180,6 +197,9 @@ func FilterRepPolicies(name string, projectID int64) ([]*models.RepPolicy, error
 	if _, err := o.Raw(sql, args).QueryRows(&policies); err != nil {
 		return nil, err
 	}
+	if err := genFilterListForPolicy(policies...); err != nil {
+		return nil, err
+	}
 	return policies, nil
 }
 
@@ -197,6 +217,10 @@ func GetRepPolicyByName(name string) (*models.RepPolicy, error) {
 		return nil, err
 	}
 
+	if err := genFilterListForPolicy(&policy); err != nil {
+		return nil, err
+	}
+
 	return &policy, nil
 }
 
@@ -211,6 +235,10 @@ func GetRepPolicyByProject(projectID int64) ([]*models.RepPolicy, error) {
 		return nil, err
 	}
 
+	if err := genFilterListForPolicy(policies...); err != nil {
+		return nil, err
+	}
+
 	return policies, nil
 }
 
@@ -225,6 +253,10 @@ func GetRepPolicyByTarget(targetID int64) ([]*models.RepPolicy, error) {
 		return nil, err
 	}
 
+	if err := genFilterListForPolicy(policies...); err != nil {
+		return nil, err
+	}
+
 	return policies, nil
 }
 
@@ -239,14 +271,54 @@ func GetRepPolicyByProjectAndTarget(projectID, targetID int64) ([]*models.RepPol
 		return nil, err
 	}
 
+	if err := genFilterListForPolicy(policies...); err != nil {
+		return nil, err
+	}
+
 	return policies, nil
 }
 
 // UpdateRepPolicy ...
 func UpdateRepPolicy(policy *models.RepPolicy) error {
 	o := GetOrmer()
+	if err := genFiltersForPolicy(policy); err != nil {
+		return err
+	}
 	policy.UpdateTime = time.Now()
-	_, err := o.Update(policy, "TargetID", "Name", "Enabled", "Description", "CronStr", "UpdateTime")
+	_, err := o.Update(policy, "TargetID", "Name", "Enabled", "Description", "CronStr",
+		"RetryMaxAttempts", "RetryInitialDelay", "RetryMultiplier", "RetryJitter", "Filters", "UpdateTime")
+	return err
+}
+
+// GetScheduledRepPolicies returns the enabled policies which have a cron string
+func GetScheduledRepPolicies() ([]*models.RepPolicy, error) {
+	o := GetOrmer()
+	sql := `select * from replication_policy where deleted = 0 and enabled = 1 and cron_str <> ''`
+
+	var policies []*models.RepPolicy
+
+	if _, err := o.Raw(sql).QueryRows(&policies); err != nil {
+		return nil, err
+	}
+
+	if err := genFilterListForPolicy(policies...); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// UpdateRepPolicySchedule records the last run time, the next run time and the
+// error of the last scheduling of the policy
+func UpdateRepPolicySchedule(id int64, lastRunTime, nextRunTime time.Time, scheduleErr string) error {
+	o := GetOrmer()
+	p := &models.RepPolicy{
+		ID:            id,
+		LastRunTime:   lastRunTime,
+		NextRunTime:   nextRunTime,
+		ScheduleError: scheduleErr,
+	}
+	_, err := o.Update(p, "LastRunTime", "NextRunTime", "ScheduleError")
 	return err
 }
 
@@ -412,6 +484,52 @@ func ResetRunningJobs() error {
 	return err
 }
 
+// ScheduleRepJobRetry marks the job as retrying and records the time when it
+// should be run again
+func ScheduleRepJobRetry(id int64, retryTime time.Time) error {
+	o := GetOrmer()
+	j := models.RepJob{
+		ID:         id,
+		Status:     models.JobRetrying,
+		RetryTime:  retryTime,
+		UpdateTime: time.Now(),
+	}
+	num, err := o.Update(&j, "Status", "RetryTime", "UpdateTime")
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return fmt.Errorf("Failed to update replication job with id: %d", id)
+	}
+	return nil
+}
+
+// GetRepJobsToRetry returns the retrying jobs whose retry time is not later than t,
+// the jobs without retry time are also returned
+func GetRepJobsToRetry(t time.Time) ([]*models.RepJob, error) {
+	var res []*models.RepJob
+	due := orm.NewCondition().And("retry_time__isnull", true).Or("retry_time__lte", t)
+	cond := orm.NewCondition().And("status", models.JobRetrying).AndCond(due)
+	_, err := repJobQs().SetCond(cond).All(&res)
+	genTagListForJob(res...)
+	return res, err
+}
+
+// IncreaseRepJobAttempts increases the attempt count of the job by 1
+func IncreaseRepJobAttempts(id int64) error {
+	o := GetOrmer()
+	num, err := o.QueryTable(models.RepJobTable).Filter("id", id).Update(orm.Params{
+		"attempts": orm.ColValue(orm.ColAdd, 1),
+	})
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return fmt.Errorf("Failed to update replication job with id: %d", id)
+	}
+	return nil
+}
+
 // GetRepJobByStatus get jobs of certain statuses
 func GetRepJobByStatus(status ...string) ([]*models.RepJob, error) {
 	var res []*models.RepJob
@@ -424,6 +542,33 @@ func GetRepJobByStatus(status ...string) ([]*models.RepJob, error) {
 	return res, err
 }
 
+// genFiltersForPolicy serializes the filter list of the policy into the
+// column filters
+func genFiltersForPolicy(policy *models.RepPolicy) error {
+	if len(policy.FilterList) == 0 {
+		policy.Filters = ""
+		return nil
+	}
+	data, err := json.Marshal(policy.FilterList)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filters of policy %s: %v", policy.Name, err)
+	}
+	policy.Filters = string(data)
+	return nil
+}
+
+func genFilterListForPolicy(policies ...*models.RepPolicy) error {
+	for _, p := range policies {
+		if len(p.Filters) == 0 {
+			continue
+		}
+		if err := json.Unmarshal([]byte(p.Filters), &p.FilterList); err != nil {
+			return fmt.Errorf("failed to unmarshal filters of policy %d: %v", p.ID, err)
+		}
+	}
+	return nil
+}
+
 func genTagListForJob(jobs ...*models.RepJob) {
 	for _, j := range jobs {
 		if len(j.Tags) > 0 {
@@ -0,0 +1,124 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddRobot adds a robot account, the secret of the robot must have been
+// hashed with the salt
+func AddRobot(robot *models.Robot) (int64, error) {
+	now := time.Now()
+	robot.CreationTime = now
+	robot.UpdateTime = now
+	genListFieldsForRobot(robot)
+	return GetOrmer().Insert(robot)
+}
+
+// GetRobot returns the robot account specified by ID, nil is returned
+// if the robot does not exist
+func GetRobot(id int64) (*models.Robot, error) {
+	robot := &models.Robot{
+		ID: id,
+	}
+	if err := GetOrmer().Read(robot); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	genListsForRobot(robot)
+	return robot, nil
+}
+
+// GetRobotByName returns the robot account of the project specified by
+// name, nil is returned if the robot does not exist
+func GetRobotByName(projectID int64, name string) (*models.Robot, error) {
+	robot := &models.Robot{
+		ProjectID: projectID,
+		Name:      name,
+	}
+	if err := GetOrmer().Read(robot, "ProjectID", "Name"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	genListsForRobot(robot)
+	return robot, nil
+}
+
+// GetRobots returns the robot accounts of the project
+func GetRobots(projectID int64) ([]*models.Robot, error) {
+	robots := []*models.Robot{}
+	_, err := GetOrmer().QueryTable(models.RobotTable).
+		Filter("project_id", projectID).
+		OrderBy("name").
+		All(&robots)
+	if err != nil {
+		return nil, err
+	}
+	for _, robot := range robots {
+		genListsForRobot(robot)
+	}
+	return robots, nil
+}
+
+// UpdateRobot updates the description, permissions, expiration and
+// status of the robot
+func UpdateRobot(robot *models.Robot) error {
+	robot.UpdateTime = time.Now()
+	genListFieldsForRobot(robot)
+	_, err := GetOrmer().Update(robot, "Description", "Actions",
+		"Repositories", "ExpiresAt", "Disabled", "UpdateTime")
+	return err
+}
+
+// DeleteRobot deletes the robot account specified by ID
+func DeleteRobot(id int64) error {
+	_, err := GetOrmer().Delete(&models.Robot{
+		ID: id,
+	})
+	return err
+}
+
+// DeleteRobots deletes all robot accounts of the project
+func DeleteRobots(projectID int64) error {
+	_, err := GetOrmer().QueryTable(models.RobotTable).
+		Filter("project_id", projectID).
+		Delete()
+	return err
+}
+
+func genListFieldsForRobot(robot *models.Robot) {
+	robot.Actions = strings.Join(robot.ActionList, ",")
+	robot.Repositories = strings.Join(robot.RepositoryList, ",")
+}
+
+func genListsForRobot(robot *models.Robot) {
+	robot.ActionList = []string{}
+	if len(robot.Actions) > 0 {
+		robot.ActionList = strings.Split(robot.Actions, ",")
+	}
+	robot.RepositoryList = []string{}
+	if len(robot.Repositories) > 0 {
+		robot.RepositoryList = strings.Split(robot.Repositories, ",")
+	}
+}
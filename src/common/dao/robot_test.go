@@ -0,0 +1,81 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestRobot(t *testing.T) {
+	pid, err := AddProject(models.Project{
+		OwnerID: currentUser.UserID,
+		Name:    "project_for_test_robot",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if err := DeleteRobots(pid); err != nil {
+			t.Errorf("failed to clear up robots of project %d: %v", pid, err)
+		}
+		if err := delProjPermanent(pid); err != nil {
+			t.Errorf("failed to clear up project %d: %v", pid, err)
+		}
+	}()
+
+	// add
+	id, err := AddRobot(&models.Robot{
+		Name:           "robot_for_test",
+		ProjectID:      pid,
+		Secret:         "secret",
+		Salt:           "salt",
+		ActionList:     []string{models.RobotActionPull, models.RobotActionPush},
+		RepositoryList: []string{"app"},
+	})
+	require.Nil(t, err)
+
+	// get by ID
+	robot, err := GetRobot(id)
+	require.Nil(t, err)
+	require.NotNil(t, robot)
+	assert.Equal(t, "robot_for_test", robot.Name)
+	assert.Equal(t, []string{models.RobotActionPull, models.RobotActionPush}, robot.ActionList)
+	assert.Equal(t, []string{"app"}, robot.RepositoryList)
+
+	// update and get by name
+	robot.ActionList = []string{models.RobotActionPull}
+	robot.RepositoryList = nil
+	robot.Disabled = true
+	require.Nil(t, UpdateRobot(robot))
+	robot, err = GetRobotByName(pid, "robot_for_test")
+	require.Nil(t, err)
+	require.NotNil(t, robot)
+	assert.Equal(t, []string{models.RobotActionPull}, robot.ActionList)
+	assert.Equal(t, 0, len(robot.RepositoryList))
+	assert.True(t, robot.Disabled)
+
+	// list
+	robots, err := GetRobots(pid)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(robots))
+
+	// delete
+	require.Nil(t, DeleteRobot(id))
+	robot, err = GetRobot(id)
+	require.Nil(t, err)
+	assert.Nil(t, robot)
+}
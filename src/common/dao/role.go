@@ -16,6 +16,7 @@ package dao
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/astaxie/beego/orm"
 	"github.com/vmware/harbor/src/common/models"
@@ -41,6 +42,9 @@ func GetUserProjectRoles(userID int, projectID int64) ([]models.Role, error) {
 	if err != nil {
 		return nil, err
 	}
+	for i := range roleList {
+		genPermissionListForRole(&roleList[i])
+	}
 	return roleList, nil
 }
 
@@ -88,5 +92,92 @@ func GetRoleByID(id int) (*models.Role, error) {
 		}
 		return nil, err
 	}
+	genPermissionListForRole(&role)
 	return &role, nil
 }
+
+// GetRoleByName returns the role specified by name, nil is returned if it
+// does not exist
+func GetRoleByName(name string) (*models.Role, error) {
+	role := &models.Role{
+		Name: name,
+	}
+	if err := GetOrmer().Read(role, "Name"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	genPermissionListForRole(role)
+	return role, nil
+}
+
+// ListRoles returns all the roles, including the builtin ones
+func ListRoles() ([]*models.Role, error) {
+	roles := []*models.Role{}
+	if _, err := GetOrmer().QueryTable(&models.Role{}).
+		OrderBy("role_id").All(&roles); err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		genPermissionListForRole(role)
+	}
+	return roles, nil
+}
+
+// AddRole adds a custom role
+func AddRole(role *models.Role) (int64, error) {
+	role.Permissions = strings.Join(role.PermissionList, ",")
+	return GetOrmer().Insert(role)
+}
+
+// UpdateRole updates the name and permissions of the role
+func UpdateRole(role *models.Role) error {
+	role.Permissions = strings.Join(role.PermissionList, ",")
+	_, err := GetOrmer().Update(role, "Name", "Permissions")
+	return err
+}
+
+// DeleteRole deletes the role specified by ID
+func DeleteRole(id int) error {
+	_, err := GetOrmer().Delete(&models.Role{
+		RoleID: id,
+	})
+	return err
+}
+
+// IsRoleInUse returns whether the role is assigned to any user or group
+func IsRoleInUse(id int) (bool, error) {
+	var count int
+	if err := GetOrmer().Raw(`select (select count(*) from project_member where role = ?)
+		+ (select count(*) from project_group_member where role = ?)`, id, id).
+		QueryRow(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetRolePermissions returns the permissions granted by the roles
+func GetRolePermissions(roleIDs []int) ([]string, error) {
+	if len(roleIDs) == 0 {
+		return []string{}, nil
+	}
+	roles := []*models.Role{}
+	if _, err := GetOrmer().QueryTable(&models.Role{}).
+		Filter("role_id__in", roleIDs).All(&roles); err != nil {
+		return nil, err
+	}
+	perms := []string{}
+	for _, role := range roles {
+		genPermissionListForRole(role)
+		perms = append(perms, role.PermissionList...)
+	}
+	return perms, nil
+}
+
+func genPermissionListForRole(role *models.Role) {
+	role.PermissionList = []string{}
+	if len(role.Permissions) > 0 {
+		role.PermissionList = strings.Split(role.Permissions, ",")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestCustomRole(t *testing.T) {
+	role := &models.Role{
+		Name:           "role_for_test_dao",
+		PermissionList: []string{models.PermProjectRead, models.PermScan},
+	}
+	id, err := AddRole(role)
+	require.Nil(t, err)
+	defer func() {
+		if err := DeleteRole(int(id)); err != nil {
+			t.Errorf("failed to clear up role %d: %v", id, err)
+		}
+	}()
+
+	r, err := GetRoleByName(role.Name)
+	require.Nil(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, int(id), r.RoleID)
+	assert.False(t, r.IsBuiltin())
+	assert.Equal(t, role.PermissionList, r.PermissionList)
+
+	r.PermissionList = []string{models.PermRepoPull}
+	require.Nil(t, UpdateRole(r))
+	perms, err := GetRolePermissions([]int{int(id), common.RoleGuest})
+	require.Nil(t, err)
+	assert.Contains(t, perms, models.PermRepoPull)
+	assert.Contains(t, perms, models.PermLogRead)
+	assert.NotContains(t, perms, models.PermScan)
+
+	roles, err := ListRoles()
+	require.Nil(t, err)
+	assert.Equal(t, 4, len(roles))
+
+	pid, err := AddProject(models.Project{
+		OwnerID: currentUser.UserID,
+		Name:    "project_for_test_custom_role",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if err := delProjPermanent(pid); err != nil {
+			t.Errorf("failed to clear up project %d: %v", pid, err)
+		}
+	}()
+
+	inUse, err := IsRoleInUse(int(id))
+	require.Nil(t, err)
+	assert.False(t, inUse)
+
+	require.Nil(t, AddProjectMember(pid, currentUser.UserID, int(id)))
+	inUse, err = IsRoleInUse(int(id))
+	require.Nil(t, err)
+	assert.True(t, inUse)
+	require.Nil(t, DeleteProjectMember(pid, currentUser.UserID))
+}
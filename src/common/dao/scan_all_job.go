@@ -0,0 +1,103 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddScanAllJob records a running job of scanning all images
+func AddScanAllJob(trigger string) (int64, error) {
+	return GetOrmer().Insert(&models.ScanAllJob{
+		Status:    models.JobRunning,
+		Trigger:   trigger,
+		StartTime: time.Now(),
+	})
+}
+
+// GetScanAllJob returns the job specified by ID, nil is returned if the job
+// does not exist
+func GetScanAllJob(id int64) (*models.ScanAllJob, error) {
+	job := &models.ScanAllJob{
+		ID: id,
+	}
+	if err := GetOrmer().Read(job); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetLatestScanAllJob returns the latest job of the status, or the latest
+// job of any status if the status is empty, nil is returned if no such job
+func GetLatestScanAllJob(status string) (*models.ScanAllJob, error) {
+	qs := GetOrmer().QueryTable(models.ScanAllJobTable)
+	if len(status) > 0 {
+		qs = qs.Filter("status", status)
+	}
+	job := &models.ScanAllJob{}
+	if err := qs.OrderBy("-id").Limit(1).One(job); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateScanAllJobProgress updates the number of images to scan and the
+// number of scan jobs created
+func UpdateScanAllJobProgress(id int64, total, scheduled int) error {
+	_, err := GetOrmer().Update(&models.ScanAllJob{
+		ID:        id,
+		Total:     total,
+		Scheduled: scheduled,
+	}, "Total", "Scheduled")
+	return err
+}
+
+// FinishScanAllJob updates the status of the job to finished, or error if
+// errMsg is not empty
+func FinishScanAllJob(id int64, errMsg string) error {
+	job := &models.ScanAllJob{
+		ID:      id,
+		Status:  models.JobFinished,
+		Error:   errMsg,
+		EndTime: time.Now(),
+	}
+	if len(errMsg) > 0 {
+		job.Status = models.JobError
+	}
+	_, err := GetOrmer().Update(job, "Status", "Error", "EndTime")
+	return err
+}
+
+// StopRunningScanAllJobs updates the status of the running jobs to error,
+// it is called when the job service starts as the jobs can not be resumed
+func StopRunningScanAllJobs() error {
+	_, err := GetOrmer().QueryTable(models.ScanAllJobTable).
+		Filter("status", models.JobRunning).
+		Update(orm.Params{
+			"status":   models.JobError,
+			"error":    "interrupted by the restart of job service",
+			"end_time": time.Now(),
+		})
+	return err
+}
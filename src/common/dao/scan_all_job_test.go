@@ -0,0 +1,86 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestScanAllJob(t *testing.T) {
+	id1, err := AddScanAllJob(models.ScanAllTriggerManual)
+	require.Nil(t, err)
+	id2, err := AddScanAllJob(models.ScanAllTriggerSchedule)
+	require.Nil(t, err)
+	defer func() {
+		if _, err := GetOrmer().QueryTable(models.ScanAllJobTable).
+			Filter("id__in", id1, id2).Delete(); err != nil {
+			t.Errorf("failed to clear up scan all jobs: %v", err)
+		}
+	}()
+
+	require.Nil(t, UpdateScanAllJobProgress(id1, 10, 3))
+	require.Nil(t, FinishScanAllJob(id1, ""))
+	job, err := GetScanAllJob(id1)
+	require.Nil(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, models.JobFinished, job.Status)
+	assert.Equal(t, models.ScanAllTriggerManual, job.Trigger)
+	assert.Equal(t, 10, job.Total)
+	assert.Equal(t, 3, job.Scheduled)
+	assert.False(t, job.EndTime.IsZero())
+
+	job, err = GetLatestScanAllJob("")
+	require.Nil(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, id2, job.ID)
+	assert.Equal(t, models.JobRunning, job.Status)
+
+	job, err = GetLatestScanAllJob(models.JobFinished)
+	require.Nil(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, id1, job.ID)
+
+	require.Nil(t, StopRunningScanAllJobs())
+	job, err = GetScanAllJob(id2)
+	require.Nil(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, models.JobError, job.Status)
+	assert.True(t, len(job.Error) > 0)
+
+}
+
+func TestCountScanJobsByStatus(t *testing.T) {
+	before, err := CountScanJobsByStatus(models.JobPending, models.JobRunning)
+	require.Nil(t, err)
+
+	id, err := AddScanJob(models.ScanJob{
+		Repository: "library/count_scan_jobs",
+		Tag:        "latest",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if _, err := GetOrmer().Delete(&models.ScanJob{ID: id}); err != nil {
+			t.Errorf("failed to clear up scan job %d: %v", id, err)
+		}
+	}()
+
+	after, err := CountScanJobsByStatus(models.JobPending, models.JobRunning)
+	require.Nil(t, err)
+	assert.Equal(t, before+1, after)
+}
@@ -72,6 +72,66 @@ func UpdateScanJobStatus(id int64, status string) error {
 	return err
 }
 
+// ScheduleScanJobRetry marks the scan job as retrying and records the time when it
+// should be run again
+func ScheduleScanJobRetry(id int64, retryTime time.Time) error {
+	o := GetOrmer()
+	sj := models.ScanJob{
+		ID:         id,
+		Status:     models.JobRetrying,
+		RetryTime:  retryTime,
+		UpdateTime: time.Now(),
+	}
+	n, err := o.Update(&sj, "Status", "RetryTime", "UpdateTime")
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("Failed to update scan job with id: %d", id)
+	}
+	return nil
+}
+
+// GetScanJobsToRetry returns the retrying scan jobs whose retry time is not later than t,
+// the jobs without retry time are also returned
+func GetScanJobsToRetry(t time.Time) ([]*models.ScanJob, error) {
+	var res []*models.ScanJob
+	due := orm.NewCondition().And("retry_time__isnull", true).Or("retry_time__lte", t)
+	cond := orm.NewCondition().And("status", models.JobRetrying).AndCond(due)
+	_, err := scanJobQs().SetCond(cond).All(&res)
+	return res, err
+}
+
+// GetScanJobsByStatus returns the scan jobs of certain statuses
+func GetScanJobsByStatus(status ...string) ([]*models.ScanJob, error) {
+	var res []*models.ScanJob
+	var t []interface{}
+	for _, s := range status {
+		t = append(t, interface{}(s))
+	}
+	_, err := scanJobQs().Filter("status__in", t...).All(&res)
+	return res, err
+}
+
+// CountScanJobsByStatus returns the number of the scan jobs of certain statuses
+func CountScanJobsByStatus(status ...string) (int64, error) {
+	var t []interface{}
+	for _, s := range status {
+		t = append(t, interface{}(s))
+	}
+	return scanJobQs().Filter("status__in", t...).Count()
+}
+
+// ResetRunningScanJobs updates the status of all running scan jobs to pending
+func ResetRunningScanJobs() error {
+	o := GetOrmer()
+	_, err := o.QueryTable(models.ScanJobTable).Filter("status", models.JobRunning).Update(orm.Params{
+		"status":      models.JobPending,
+		"update_time": time.Now(),
+	})
+	return err
+}
+
 func scanJobQs(limit ...int) orm.QuerySeter {
 	o := GetOrmer()
 	l := -1
@@ -123,24 +183,38 @@ func GetImgScanOverview(digest string) (*models.ImgScanOverview, error) {
 		}
 		rec.CompOverview = co
 	}
+	if len(rec.VulnsStr) > 0 {
+		vulns := map[string]models.Severity{}
+		if err := json.Unmarshal([]byte(rec.VulnsStr), &vulns); err != nil {
+			return nil, err
+		}
+		rec.Vulns = vulns
+	}
 	return rec, nil
 }
 
-// UpdateImgScanOverview updates the serverity and components status of a record in img_scan_overview
-func UpdateImgScanOverview(digest, detailsKey string, sev models.Severity, compOverview *models.ComponentsOverview) error {
+// UpdateImgScanOverview updates the serverity, components status and the severity of each CVE of a record in img_scan_overview
+func UpdateImgScanOverview(digest, scanner, detailsKey string, sev models.Severity, compOverview *models.ComponentsOverview,
+	vulns map[string]models.Severity) error {
 	o := GetOrmer()
 	b, err := json.Marshal(compOverview)
 	if err != nil {
 		return err
 	}
+	v, err := json.Marshal(vulns)
+	if err != nil {
+		return err
+	}
 	rec := &models.ImgScanOverview{
 		Digest:          digest,
 		Sev:             int(sev),
 		CompOverviewStr: string(b),
 		DetailsKey:      detailsKey,
+		Scanner:         scanner,
+		VulnsStr:        string(v),
 		UpdateTime:      time.Now(),
 	}
-	n, err := o.Update(rec, "Sev", "CompOverviewStr", "DetailsKey", "UpdateTime")
+	n, err := o.Update(rec, "Sev", "CompOverviewStr", "DetailsKey", "Scanner", "VulnsStr", "UpdateTime")
 	if n == 0 || err != nil {
 		return fmt.Errorf("Failed to update scan overview record with digest: %s, error: %v", digest, err)
 	}
@@ -0,0 +1,111 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddSigningKey adds a signing key, the private key must have been encrypted
+func AddSigningKey(key *models.SigningKey) (int64, error) {
+	key.CreationTime = time.Now()
+	return GetOrmer().Insert(key)
+}
+
+// GetSigningKey returns the key specified by ID, nil is returned if the key
+// does not exist
+func GetSigningKey(id int64) (*models.SigningKey, error) {
+	key := &models.SigningKey{
+		ID: id,
+	}
+	if err := GetOrmer().Read(key); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetSigningKeyByKeyID returns the key whose key ID is keyID, nil is
+// returned if the key does not exist
+func GetSigningKeyByKeyID(keyID string) (*models.SigningKey, error) {
+	key := &models.SigningKey{
+		KeyID: keyID,
+	}
+	if err := GetOrmer().Read(key, "KeyID"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListSigningKeys returns all the signing keys ordered by creation
+func ListSigningKeys() ([]*models.SigningKey, error) {
+	keys := []*models.SigningKey{}
+	_, err := GetOrmer().QueryTable(models.SigningKeyTable).
+		OrderBy("id").
+		All(&keys)
+	return keys, err
+}
+
+// ActivateSigningKey makes the key specified by ID the active one, the
+// previous active key is retired and expires at the time specified
+func ActivateSigningKey(id int64, retiredKeyExpiresAt int64) (err error) {
+	o := orm.NewOrm()
+	if err = o.Begin(); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			o.Rollback()
+			return
+		}
+		err = o.Commit()
+	}()
+
+	if _, err = o.QueryTable(models.SigningKeyTable).
+		Filter("active", true).
+		Exclude("id", id).
+		Update(orm.Params{
+			"active":     false,
+			"expires_at": retiredKeyExpiresAt,
+		}); err != nil {
+		return err
+	}
+	_, err = o.QueryTable(models.SigningKeyTable).
+		Filter("id", id).
+		Update(orm.Params{
+			"active":     true,
+			"expires_at": 0,
+		})
+	return err
+}
+
+// DeleteExpiredSigningKeys deletes the retired keys whose overlap window
+// has passed
+func DeleteExpiredSigningKeys() error {
+	_, err := GetOrmer().QueryTable(models.SigningKeyTable).
+		Filter("active", false).
+		Filter("expires_at__gt", 0).
+		Filter("expires_at__lte", time.Now().Unix()).
+		Delete()
+	return err
+}
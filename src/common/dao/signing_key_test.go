@@ -0,0 +1,79 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestSigningKey(t *testing.T) {
+	oldID, err := AddSigningKey(&models.SigningKey{
+		KeyID:      "key_for_test_dao_old",
+		PrivateKey: "private_key",
+		Active:     true,
+	})
+	require.Nil(t, err)
+	newID, err := AddSigningKey(&models.SigningKey{
+		KeyID:      "key_for_test_dao_new",
+		PrivateKey: "private_key",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if _, err := GetOrmer().QueryTable(models.SigningKeyTable).
+			Filter("id__in", oldID, newID).Delete(); err != nil {
+			t.Errorf("failed to clear up signing keys: %v", err)
+		}
+	}()
+
+	key, err := GetSigningKeyByKeyID("key_for_test_dao_new")
+	require.Nil(t, err)
+	require.NotNil(t, key)
+	assert.Equal(t, newID, key.ID)
+
+	// the key ID is unique
+	_, err = AddSigningKey(&models.SigningKey{
+		KeyID:      "key_for_test_dao_new",
+		PrivateKey: "private_key",
+	})
+	assert.NotNil(t, err)
+
+	// the previous active key is retired
+	require.Nil(t, ActivateSigningKey(newID, time.Now().Add(-time.Minute).Unix()))
+	key, err = GetSigningKey(newID)
+	require.Nil(t, err)
+	require.NotNil(t, key)
+	assert.True(t, key.Active)
+	assert.Equal(t, int64(0), key.ExpiresAt)
+	key, err = GetSigningKey(oldID)
+	require.Nil(t, err)
+	require.NotNil(t, key)
+	assert.False(t, key.Active)
+	assert.True(t, key.IsExpired())
+
+	keys, err := ListSigningKeys()
+	require.Nil(t, err)
+	assert.True(t, len(keys) >= 2)
+
+	// the expired key is deleted
+	require.Nil(t, DeleteExpiredSigningKeys())
+	key, err = GetSigningKey(oldID)
+	require.Nil(t, err)
+	assert.Nil(t, key)
+}
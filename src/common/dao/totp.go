@@ -0,0 +1,93 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddUserTOTP inserts the TOTP settings of the user
+func AddUserTOTP(t *models.UserTOTP) (int64, error) {
+	now := time.Now()
+	t.CreationTime = now
+	t.UpdateTime = now
+	return GetOrmer().Insert(t)
+}
+
+// GetUserTOTP returns the TOTP settings of the user, nil is returned if the
+// user has not enrolled
+func GetUserTOTP(userID int) (*models.UserTOTP, error) {
+	t := &models.UserTOTP{
+		UserID: userID,
+	}
+	if err := GetOrmer().Read(t, "UserID"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateUserTOTP updates the specified columns of the TOTP settings
+func UpdateUserTOTP(t *models.UserTOTP, cols ...string) error {
+	t.UpdateTime = time.Now()
+	if len(cols) > 0 {
+		cols = append(cols, "UpdateTime")
+	}
+	_, err := GetOrmer().Update(t, cols...)
+	return err
+}
+
+// UpdateTOTPLastStep records the step of the accepted one-time password, it
+// returns false if a password of the same or a later step has been accepted
+func UpdateTOTPLastStep(userID int, step int64) (bool, error) {
+	r, err := GetOrmer().Raw(`update user_totp set last_step = ?, update_time = ?
+		where user_id = ? and last_step < ?`, step, time.Now(), userID, step).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := r.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// UpdateTOTPRecoveryCodes replaces the recovery codes of the user, it returns
+// false if they have been changed concurrently, e.g. the same code is used
+// by two logins at the same time
+func UpdateTOTPRecoveryCodes(userID int, old, codes string) (bool, error) {
+	r, err := GetOrmer().Raw(`update user_totp set recovery_codes = ?, update_time = ?
+		where user_id = ? and recovery_codes = ?`, codes, time.Now(), userID, old).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := r.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// DeleteUserTOTP deletes the TOTP settings of the user
+func DeleteUserTOTP(userID int) error {
+	_, err := GetOrmer().QueryTable(&models.UserTOTP{}).
+		Filter("UserID", userID).Delete()
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestUserTOTP(t *testing.T) {
+	// not exist
+	ut, err := GetUserTOTP(currentUser.UserID)
+	require.Nil(t, err)
+	assert.Nil(t, ut)
+
+	// add
+	_, err = AddUserTOTP(&models.UserTOTP{
+		UserID: currentUser.UserID,
+		Secret: "secret",
+	})
+	require.Nil(t, err)
+	defer func() {
+		if err := DeleteUserTOTP(currentUser.UserID); err != nil {
+			t.Errorf("failed to clear up TOTP of user %d: %v", currentUser.UserID, err)
+		}
+	}()
+
+	ut, err = GetUserTOTP(currentUser.UserID)
+	require.Nil(t, err)
+	require.NotNil(t, ut)
+	assert.False(t, ut.Enabled)
+	assert.Equal(t, "secret", ut.Secret)
+
+	// enable
+	ut.Enabled = true
+	ut.RecoveryCodes = "a,b"
+	require.Nil(t, UpdateUserTOTP(ut, "Enabled", "RecoveryCodes"))
+	ut, err = GetUserTOTP(currentUser.UserID)
+	require.Nil(t, err)
+	assert.True(t, ut.Enabled)
+
+	// the same step can only be accepted once
+	ok, err := UpdateTOTPLastStep(currentUser.UserID, 100)
+	require.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = UpdateTOTPLastStep(currentUser.UserID, 100)
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	// the recovery codes are replaced only if they are not changed
+	ok, err = UpdateTOTPRecoveryCodes(currentUser.UserID, "a,b", "b")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = UpdateTOTPRecoveryCodes(currentUser.UserID, "a,b", "b")
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	// delete
+	require.Nil(t, DeleteUserTOTP(currentUser.UserID))
+	ut, err = GetUserTOTP(currentUser.UserID)
+	require.Nil(t, err)
+	assert.Nil(t, ut)
+}
@@ -85,7 +85,7 @@ func LoginByDb(auth models.AuthModel) (*models.User, error) {
 
 	user := users[0]
 
-	if user.Password != utils.Encrypt(auth.Password, user.Salt) {
+	if !matchPassword(&user, auth.Password) {
 		return nil, nil
 	}
 
@@ -155,13 +155,31 @@ func ChangeUserPassword(u models.User, oldPassword ...string) (err error) {
 
 	o := GetOrmer()
 
-	var r sql.Result
 	salt := utils.GenerateRandomString()
+	password, err := utils.EncryptPassword(u.Password, salt, utils.CurrentPasswordVersion)
+	if err != nil {
+		return err
+	}
+
+	var r sql.Result
 	if len(oldPassword) == 0 {
 		//In some cases, it may no need to check old password, just as Linux change password policies.
-		r, err = o.Raw(`update user set password=?, salt=? where user_id=?`, utils.Encrypt(u.Password, salt), salt, u.UserID).Exec()
+		r, err = o.Raw(`update user set password=?, salt=?, password_version=? where user_id=?`,
+			password, salt, utils.CurrentPasswordVersion, u.UserID).Exec()
 	} else {
-		r, err = o.Raw(`update user set password=?, salt=? where user_id=? and password = ?`, utils.Encrypt(u.Password, salt), salt, u.UserID, utils.Encrypt(oldPassword[0], u.Salt)).Exec()
+		var current *models.User
+		current, err = getUserWithPassword(u.UserID)
+		if err != nil {
+			return err
+		}
+		if current == nil || !utils.MatchPassword(oldPassword[0], current.Salt,
+			current.PasswordVersion, current.Password) {
+			return errors.New("old password is not correct, change password failed")
+		}
+		// the stored hash is checked again to avoid overwriting a password
+		// changed concurrently
+		r, err = o.Raw(`update user set password=?, salt=?, password_version=? where user_id=? and password = ?`,
+			password, salt, utils.CurrentPasswordVersion, u.UserID, current.Password).Exec()
 	}
 
 	if err != nil {
@@ -180,8 +198,15 @@ func ChangeUserPassword(u models.User, oldPassword ...string) (err error) {
 
 // ResetUserPassword ...
 func ResetUserPassword(u models.User) error {
+	salt := utils.GenerateRandomString()
+	password, err := utils.EncryptPassword(u.Password, salt, utils.CurrentPasswordVersion)
+	if err != nil {
+		return err
+	}
+
 	o := GetOrmer()
-	r, err := o.Raw(`update user set password=?, reset_uuid=? where reset_uuid=?`, utils.Encrypt(u.Password, u.Salt), "", u.ResetUUID).Exec()
+	r, err := o.Raw(`update user set password=?, salt=?, password_version=?, reset_uuid=? where reset_uuid=?`,
+		password, salt, utils.CurrentPasswordVersion, "", u.ResetUUID).Exec()
 	if err != nil {
 		return err
 	}
@@ -213,23 +238,62 @@ func CheckUserPassword(query models.User) (*models.User, error) {
 		return nil, nil
 	}
 
-	sql := `select user_id, username, salt from user where deleted = 0 and username = ? and password = ?`
-	queryParam := make([]interface{}, 1)
-	queryParam = append(queryParam, currentUser.Username)
-	queryParam = append(queryParam, utils.Encrypt(query.Password, currentUser.Salt))
-	o := GetOrmer()
-	var user []models.User
+	user, err := getUserWithPassword(currentUser.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !matchPassword(user, query.Password) {
+		log.Warning("User principal does not match password. Current:", currentUser)
+		return nil, nil
+	}
+
+	return &models.User{
+		UserID:   user.UserID,
+		Username: user.Username,
+		Salt:     user.Salt,
+	}, nil
+}
 
-	n, err := o.Raw(sql, queryParam).QueryRows(&user)
+// getUserWithPassword returns the user including the password hash
+func getUserWithPassword(userID int) (*models.User, error) {
+	var users []models.User
+	n, err := GetOrmer().Raw(`select * from user where user_id = ? and deleted = 0`,
+		userID).QueryRows(&users)
 	if err != nil {
 		return nil, err
 	}
 	if n == 0 {
-		log.Warning("User principal does not match password. Current:", currentUser)
 		return nil, nil
 	}
+	return &users[0], nil
+}
 
-	return &user[0], nil
+// matchPassword checks the password against the hash stored for the user,
+// if they match and the hash was generated by an older version, the password
+// is rehashed with the current version
+func matchPassword(user *models.User, password string) bool {
+	if !utils.MatchPassword(password, user.Salt, user.PasswordVersion, user.Password) {
+		return false
+	}
+	if user.PasswordVersion == utils.CurrentPasswordVersion {
+		return true
+	}
+
+	salt := utils.GenerateRandomString()
+	hash, err := utils.EncryptPassword(password, salt, utils.CurrentPasswordVersion)
+	if err != nil {
+		log.Errorf("failed to rehash the password of user %d: %v", user.UserID, err)
+		return true
+	}
+	if _, err = GetOrmer().Raw(`update user set password=?, salt=?, password_version=? where user_id=? and password=?`,
+		hash, salt, utils.CurrentPasswordVersion, user.UserID, user.Password).Exec(); err != nil {
+		log.Errorf("failed to upgrade the password hash of user %d: %v", user.UserID, err)
+		return true
+	}
+	user.Password = hash
+	user.Salt = salt
+	user.PasswordVersion = utils.CurrentPasswordVersion
+	return true
 }
 
 // DeleteUser ...
@@ -0,0 +1,156 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddUserGroup adds a user group
+func AddUserGroup(group *models.UserGroup) (int, error) {
+	now := time.Now()
+	group.CreationTime = now
+	group.UpdateTime = now
+	id, err := GetOrmer().Insert(group)
+	if err != nil {
+		return 0, err
+	}
+	group.ID = int(id)
+	return group.ID, nil
+}
+
+// GetUserGroup returns the user group specified by ID, nil is returned
+// if the group does not exist
+func GetUserGroup(id int) (*models.UserGroup, error) {
+	return getUserGroup(&models.UserGroup{ID: id})
+}
+
+// GetUserGroupByLdapDN returns the LDAP group specified by the DN, nil is
+// returned if the group has not been imported
+func GetUserGroupByLdapDN(dn string) (*models.UserGroup, error) {
+	return getUserGroup(&models.UserGroup{
+		GroupType:   models.LdapGroupType,
+		LdapGroupDN: dn,
+	}, "GroupType", "LdapGroupDN")
+}
+
+func getUserGroup(group *models.UserGroup, cols ...string) (*models.UserGroup, error) {
+	if err := GetOrmer().Read(group, cols...); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// OnboardLdapGroup adds the LDAP group if it has not been imported and fills
+// the ID of the group
+func OnboardLdapGroup(group *models.UserGroup) error {
+	group.GroupType = models.LdapGroupType
+	existing, err := GetUserGroupByLdapDN(group.LdapGroupDN)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		group.ID = existing.ID
+		return nil
+	}
+	_, err = AddUserGroup(group)
+	return err
+}
+
+// GetUserGroupByOIDCName returns the OIDC group specified by the name, nil
+// is returned if the group has not been added
+func GetUserGroupByOIDCName(name string) (*models.UserGroup, error) {
+	return getUserGroup(&models.UserGroup{
+		GroupType: models.OIDCGroupType,
+		GroupName: name,
+	}, "GroupType", "GroupName")
+}
+
+// GetLdapGroupIDs returns the IDs of the imported LDAP groups whose DNs are
+// in the list, the DNs of groups which have not been imported are ignored
+func GetLdapGroupIDs(dns []string) ([]int, error) {
+	ids := []int{}
+	if len(dns) == 0 {
+		return ids, nil
+	}
+
+	groups := []*models.UserGroup{}
+	_, err := GetOrmer().QueryTable(models.UserGroupTable).
+		Filter("group_type", models.LdapGroupType).
+		Filter("ldap_group_dn__in", dns).
+		All(&groups, "ID")
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+	return ids, nil
+}
+
+// OnboardOIDCGroup adds the OIDC group if it has not been added and fills
+// the ID of the group
+func OnboardOIDCGroup(group *models.UserGroup) error {
+	group.GroupType = models.OIDCGroupType
+	existing, err := GetUserGroupByOIDCName(group.GroupName)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		group.ID = existing.ID
+		return nil
+	}
+	_, err = AddUserGroup(group)
+	return err
+}
+
+// GetOIDCGroupIDs returns the IDs of the OIDC groups whose names are in the
+// list, the groups which have not been added are ignored
+func GetOIDCGroupIDs(names []string) ([]int, error) {
+	ids := []int{}
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	groups := []*models.UserGroup{}
+	_, err := GetOrmer().QueryTable(models.UserGroupTable).
+		Filter("group_type", models.OIDCGroupType).
+		Filter("group_name__in", names).
+		All(&groups, "ID")
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+	return ids, nil
+}
+
+// DeleteUserGroup deletes the user group and removes it from all projects
+func DeleteUserGroup(id int) error {
+	o := GetOrmer()
+	if _, err := o.Raw(`delete from project_group_member where group_id = ?`,
+		id).Exec(); err != nil {
+		return err
+	}
+	_, err := o.Delete(&models.UserGroup{ID: id})
+	return err
+}
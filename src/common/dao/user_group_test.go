@@ -0,0 +1,122 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestUserGroup(t *testing.T) {
+	dn := "cn=group_for_test_dao,ou=groups,dc=example,dc=com"
+
+	// onboard
+	group := &models.UserGroup{
+		GroupName:   "group_for_test_dao",
+		LdapGroupDN: dn,
+	}
+	require.Nil(t, OnboardLdapGroup(group))
+	require.True(t, group.ID > 0)
+	defer func() {
+		if err := DeleteUserGroup(group.ID); err != nil {
+			t.Errorf("failed to clear up group %d: %v", group.ID, err)
+		}
+	}()
+
+	// onboard again returns the same group
+	g := &models.UserGroup{
+		GroupName:   "group_for_test_dao",
+		LdapGroupDN: dn,
+	}
+	require.Nil(t, OnboardLdapGroup(g))
+	assert.Equal(t, group.ID, g.ID)
+
+	// get
+	g, err := GetUserGroup(group.ID)
+	require.Nil(t, err)
+	require.NotNil(t, g)
+	assert.Equal(t, models.LdapGroupType, g.GroupType)
+	assert.Equal(t, dn, g.LdapGroupDN)
+
+	// get IDs by DNs
+	ids, err := GetLdapGroupIDs([]string{dn, "cn=non_exist,dc=example,dc=com"})
+	require.Nil(t, err)
+	assert.Equal(t, []int{group.ID}, ids)
+
+	// add to project
+	require.Nil(t, AddProjectGroupMember(1, group.ID, models.DEVELOPER))
+	member, err := GetProjectGroupMember(1, group.ID)
+	require.Nil(t, err)
+	require.NotNil(t, member)
+	assert.Equal(t, "group_for_test_dao", member.GroupName)
+	assert.Equal(t, models.DEVELOPER, member.Role)
+
+	roles, err := GetGroupProjectRoles([]int{group.ID}, 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(roles))
+	assert.Equal(t, "RWS", roles[0].RoleCode)
+
+	// update the role
+	require.Nil(t, UpdateProjectGroupMember(1, group.ID, models.GUEST))
+	members, err := GetProjectGroupMembers(1)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(members))
+	assert.Equal(t, models.GUEST, members[0].Role)
+
+	// remove from project
+	require.Nil(t, DeleteProjectGroupMember(1, group.ID))
+	member, err = GetProjectGroupMember(1, group.ID)
+	require.Nil(t, err)
+	assert.Nil(t, member)
+}
+
+func TestOIDCGroup(t *testing.T) {
+	group := &models.UserGroup{
+		GroupName: "group_for_test_dao_oidc",
+	}
+	require.Nil(t, OnboardOIDCGroup(group))
+	require.True(t, group.ID > 0)
+	defer func() {
+		if err := DeleteUserGroup(group.ID); err != nil {
+			t.Errorf("failed to clear up group %d: %v", group.ID, err)
+		}
+	}()
+
+	// onboard again returns the same group
+	g := &models.UserGroup{
+		GroupName: "group_for_test_dao_oidc",
+	}
+	require.Nil(t, OnboardOIDCGroup(g))
+	assert.Equal(t, group.ID, g.ID)
+
+	g, err := GetUserGroupByOIDCName("group_for_test_dao_oidc")
+	require.Nil(t, err)
+	require.NotNil(t, g)
+	assert.Equal(t, models.OIDCGroupType, g.GroupType)
+
+	// get IDs by names, the LDAP group with the same name is ignored
+	ldapGroup := &models.UserGroup{
+		GroupName:   "group_for_test_dao_oidc",
+		LdapGroupDN: "cn=group_for_test_dao_oidc,ou=groups,dc=example,dc=com",
+	}
+	require.Nil(t, OnboardLdapGroup(ldapGroup))
+	defer DeleteUserGroup(ldapGroup.ID)
+	ids, err := GetOIDCGroupIDs([]string{"group_for_test_dao_oidc", "non_exist"})
+	require.Nil(t, err)
+	assert.Equal(t, []int{group.ID}, ids)
+}
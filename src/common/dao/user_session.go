@@ -0,0 +1,118 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// AddUserSession records a session of UI, the ID of the session must have
+// been hashed
+func AddUserSession(session *models.UserSession) (int64, error) {
+	now := time.Now()
+	session.CreationTime = now
+	session.LastActiveAt = now.Unix()
+	return GetOrmer().Insert(session)
+}
+
+// GetUserSession returns the session specified by ID, nil is returned if the
+// session does not exist
+func GetUserSession(id int64) (*models.UserSession, error) {
+	session := &models.UserSession{
+		ID: id,
+	}
+	if err := GetOrmer().Read(session); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetUserSessionBySessionID returns the session whose hashed session ID is
+// sessionID, nil is returned if the session does not exist
+func GetUserSessionBySessionID(sessionID string) (*models.UserSession, error) {
+	session := &models.UserSession{
+		SessionID: sessionID,
+	}
+	if err := GetOrmer().Read(session, "SessionID"); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// ListUserSessions returns the sessions of the user which have been active
+// since activeSince, the most recently active ones first
+func ListUserSessions(userID int, activeSince time.Time) ([]*models.UserSession, error) {
+	sessions := []*models.UserSession{}
+	_, err := GetOrmer().QueryTable(models.UserSessionTable).
+		Filter("user_id", userID).
+		Filter("last_active_at__gte", activeSince.Unix()).
+		OrderBy("-last_active_at", "-id").
+		All(&sessions)
+	return sessions, err
+}
+
+// UpdateUserSessionLastActive records the time when the session is used
+func UpdateUserSessionLastActive(id int64, t time.Time) error {
+	_, err := GetOrmer().Update(&models.UserSession{
+		ID:           id,
+		LastActiveAt: t.Unix(),
+	}, "LastActiveAt")
+	return err
+}
+
+// DeleteUserSession deletes the session specified by ID
+func DeleteUserSession(id int64) error {
+	_, err := GetOrmer().Delete(&models.UserSession{
+		ID: id,
+	})
+	return err
+}
+
+// DeleteUserSessionBySessionID deletes the session whose hashed session ID
+// is sessionID
+func DeleteUserSessionBySessionID(sessionID string) error {
+	_, err := GetOrmer().QueryTable(models.UserSessionTable).
+		Filter("session_id", sessionID).
+		Delete()
+	return err
+}
+
+// DeleteUserSessions deletes all the sessions of the user except the one
+// whose hashed session ID is exceptSessionID, which can be empty
+func DeleteUserSessions(userID int, exceptSessionID string) (int64, error) {
+	qs := GetOrmer().QueryTable(models.UserSessionTable).
+		Filter("user_id", userID)
+	if len(exceptSessionID) > 0 {
+		qs = qs.Exclude("session_id", exceptSessionID)
+	}
+	return qs.Delete()
+}
+
+// DeleteInactiveUserSessions deletes the sessions of all users which have
+// not been active since activeSince
+func DeleteInactiveUserSessions(activeSince time.Time) (int64, error) {
+	return GetOrmer().QueryTable(models.UserSessionTable).
+		Filter("last_active_at__lt", activeSince.Unix()).
+		Delete()
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestUserSession(t *testing.T) {
+	id1, err := AddUserSession(&models.UserSession{
+		SessionID: "session_for_test_dao_1",
+		UserID:    currentUser.UserID,
+		IP:        "127.0.0.1",
+	})
+	require.Nil(t, err)
+	id2, err := AddUserSession(&models.UserSession{
+		SessionID: "session_for_test_dao_2",
+		UserID:    currentUser.UserID,
+	})
+	require.Nil(t, err)
+	defer func() {
+		if _, err := DeleteUserSessions(currentUser.UserID, ""); err != nil {
+			t.Errorf("failed to clear up sessions: %v", err)
+		}
+	}()
+
+	session, err := GetUserSessionBySessionID("session_for_test_dao_1")
+	require.Nil(t, err)
+	require.NotNil(t, session)
+	assert.Equal(t, id1, session.ID)
+	assert.Equal(t, "127.0.0.1", session.IP)
+
+	// the most recently active session is listed first
+	later := time.Now().Add(time.Hour)
+	require.Nil(t, UpdateUserSessionLastActive(id1, later))
+	sessions, err := ListUserSessions(currentUser.UserID, time.Time{})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(sessions))
+	assert.Equal(t, id1, sessions[0].ID)
+	assert.Equal(t, later.Unix(), sessions[0].LastActiveAt)
+
+	// the inactive sessions are not listed and can be pruned
+	sessions, err = ListUserSessions(currentUser.UserID, later)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(sessions))
+	assert.Equal(t, id1, sessions[0].ID)
+	n, err := DeleteInactiveUserSessions(later)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	session, err = GetUserSession(id2)
+	require.Nil(t, err)
+	assert.Nil(t, session)
+	id2, err = AddUserSession(&models.UserSession{
+		SessionID: "session_for_test_dao_2",
+		UserID:    currentUser.UserID,
+	})
+	require.Nil(t, err)
+
+	n, err = DeleteUserSessions(currentUser.UserID, "session_for_test_dao_1")
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	session, err = GetUserSession(id2)
+	require.Nil(t, err)
+	assert.Nil(t, session)
+
+	require.Nil(t, DeleteUserSessionBySessionID("session_for_test_dao_1"))
+	session, err = GetUserSession(id1)
+	require.Nil(t, err)
+	assert.Nil(t, session)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessTokenTable is the name of the table whose data is mapped by
+// AccessToken struct.
+const AccessTokenTable = "access_token"
+
+const accessTokenNameMaxLength = 64
+
+// AccessToken is a personal access token which can be used by the scripts
+// of the user to call the API instead of the password of the user
+type AccessToken struct {
+	ID     int64  `orm:"pk;auto;column(id)" json:"id"`
+	UserID int    `orm:"column(user_id)" json:"user_id"`
+	Name   string `orm:"column(name)" json:"name"`
+	// Secret is the salted hash of the secret part of the token
+	Secret string `orm:"column(secret)" json:"-"`
+	Salt   string `orm:"column(salt)" json:"-"`
+	// ReadOnly tokens can only be used to read projects and pull images
+	ReadOnly bool `orm:"column(read_only)" json:"read_only"`
+	// ExpiresAt is a unix timestamp, the token never expires if it is 0
+	ExpiresAt int64 `orm:"column(expires_at)" json:"expires_at"`
+	// LastUsedAt is a unix timestamp, it is 0 if the token is never used
+	LastUsedAt   int64     `orm:"column(last_used_at)" json:"last_used_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName is required by by beego orm to map AccessToken to table access_token
+func (a *AccessToken) TableName() string {
+	return AccessTokenTable
+}
+
+// Valid checks the fields of the token which are set by users
+func (a *AccessToken) Valid() error {
+	if len(a.Name) == 0 || len(a.Name) > accessTokenNameMaxLength {
+		return fmt.Errorf("invalid name: %s", a.Name)
+	}
+	if a.ExpiresAt < 0 {
+		return fmt.Errorf("invalid expires_at: %d", a.ExpiresAt)
+	}
+	return nil
+}
+
+// IsExpired returns whether the token is expired
+func (a *AccessToken) IsExpired() bool {
+	return a.ExpiresAt > 0 && time.Now().Unix() >= a.ExpiresAt
+}
+
+// FormatAccessToken returns the token which is handed to the user, it
+// contains the ID of the token so that the token can be found without
+// storing the secret in plain text
+func FormatAccessToken(id int64, secret string) string {
+	return fmt.Sprintf("%d.%s", id, secret)
+}
+
+// ParseAccessToken returns the ID and the secret contained in the token
+func ParseAccessToken(token string) (int64, string, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return 0, "", fmt.Errorf("malformed access token")
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, "", fmt.Errorf("malformed access token")
+	}
+	return id, parts[1], nil
+}
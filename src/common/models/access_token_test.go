@@ -0,0 +1,46 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidAccessToken(t *testing.T) {
+	assert.Nil(t, (&AccessToken{Name: "ci"}).Valid())
+	assert.NotNil(t, (&AccessToken{}).Valid())
+	assert.NotNil(t, (&AccessToken{Name: "ci", ExpiresAt: -1}).Valid())
+
+	token := &AccessToken{}
+	assert.False(t, token.IsExpired())
+	token.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	assert.True(t, token.IsExpired())
+}
+
+func TestParseAccessToken(t *testing.T) {
+	id, secret, err := ParseAccessToken(FormatAccessToken(12, "abc.def"))
+	require.Nil(t, err)
+	assert.Equal(t, int64(12), id)
+	assert.Equal(t, "abc.def", secret)
+
+	for _, token := range []string{"", "abc", "12", "12.", "abc.def", "-1.abc"} {
+		_, _, err = ParseAccessToken(token)
+		assert.NotNil(t, err, token)
+	}
+}
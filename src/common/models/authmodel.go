@@ -18,4 +18,10 @@ package models
 type AuthModel struct {
 	Principal string
 	Password  string
+	// the source IP of the login request, the failed logins are also
+	// counted per source IP if it is set
+	RemoteAddr string
+	// the one-time password or recovery code of the user who enables
+	// two-factor authentication
+	OTP string
 }
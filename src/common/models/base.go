@@ -28,5 +28,16 @@ func init() {
 		new(AccessLog),
 		new(ScanJob),
 		new(RepoRecord),
-		new(ImgScanOverview))
+		new(ImgScanOverview),
+		new(ProjectMetadata),
+		new(Robot),
+		new(OIDCUser),
+		new(UserGroup),
+		new(LoginFailure),
+		new(UserTOTP),
+		new(AccessToken),
+		new(SigningKey),
+		new(UserSession),
+		new(ScanAllJob),
+		new(CVEAllowlistItem))
 }
@@ -14,6 +14,10 @@
 
 package models
 
+import (
+	"sort"
+)
+
 //ClairLayer ...
 type ClairLayer struct {
 	Name           string            `json:"Name,omitempty"`
@@ -57,3 +61,43 @@ type ClairLayerEnvelope struct {
 	Layer *ClairLayer `json:"Layer,omitempty"`
 	Error *ClairError `json:"Error,omitempty"`
 }
+
+// VulnerabilityItem is an item in the vulnerability list of an image, it is
+// normalized from the report of the scanner
+type VulnerabilityItem struct {
+	ID          string   `json:"id"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	Fixed       string   `json:"fixed_version"`
+	Severity    Severity `json:"severity"`
+	Description string   `json:"description"`
+	Link        string   `json:"link"`
+	// Allowlisted is true if the CVE is allowlisted by the system or the project
+	Allowlisted bool `json:"allowlisted"`
+}
+
+// SortVulnerabilities sorts the vulnerabilities by severity in descending order,
+// the ones with the same severity are sorted by ID and package
+func SortVulnerabilities(items []*VulnerabilityItem) {
+	sort.Sort(vulnSorter(items))
+}
+
+type vulnSorter []*VulnerabilityItem
+
+func (v vulnSorter) Len() int {
+	return len(v)
+}
+
+func (v vulnSorter) Less(i, j int) bool {
+	if v[i].Severity != v[j].Severity {
+		return v[i].Severity > v[j].Severity
+	}
+	if v[i].ID != v[j].ID {
+		return v[i].ID < v[j].ID
+	}
+	return v[i].Package < v[j].Package
+}
+
+func (v vulnSorter) Swap(i, j int) {
+	v[i], v[j] = v[j], v[i]
+}
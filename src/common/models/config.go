@@ -14,6 +14,12 @@
 
 package models
 
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
 /*
 // Authentication ...
 type Authentication struct {
@@ -25,14 +31,79 @@ type Authentication struct {
 
 // LDAP ...
 type LDAP struct {
-	URL            string `json:"url"`
-	SearchDN       string `json:"search_dn"`
-	SearchPassword string `json:"search_password"`
-	BaseDN         string `json:"base_dn"`
-	Filter         string `json:"filter"`
-	UID            string `json:"uid"`
-	Scope          int    `json:"scope"`
-	Timeout        int    `json:"timeout"` // in second
+	URL             string `json:"url"`
+	SearchDN        string `json:"search_dn"`
+	SearchPassword  string `json:"search_password"`
+	BaseDN          string `json:"base_dn"`
+	Filter          string `json:"filter"`
+	UID             string `json:"uid"`
+	Scope           int    `json:"scope"`
+	Timeout         int    `json:"timeout"` // in second
+	GroupBaseDN     string `json:"group_base_dn"`
+	GroupFilter     string `json:"group_filter"`
+	GroupNameAttr   string `json:"group_name_attribute"`
+	GroupScope      int    `json:"group_scope"`
+	GroupMemberAttr string `json:"group_membership_attribute"`
+}
+
+// LoginLockSetting holds the settings of account lockout
+type LoginLockSetting struct {
+	// the number of failed logins after which the username is locked,
+	// 0 means never
+	MaxFailures int `json:"max_failures"`
+	// the number of failed logins after which the source IP is locked,
+	// 0 means never
+	MaxFailuresPerIP int `json:"max_failures_per_ip"`
+	// the duration in seconds of the first lockout, it doubles every
+	// time the username or IP is locked again
+	Duration int `json:"duration"`
+}
+
+// PasswordPolicy holds the rules that the passwords of users must follow
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireNumber    bool `json:"require_number"`
+	RequireSpecial   bool `json:"require_special"`
+}
+
+// PasswordMaxLength is the max length of passwords
+const PasswordMaxLength = 128
+
+// Validate returns an error describing the first rule that the password
+// breaks, or nil if it follows the policy
+func (p *PasswordPolicy) Validate(password string) error {
+	if len(password) < p.MinLength || len(password) > PasswordMaxLength {
+		return fmt.Errorf("password must be %d to %d characters long", p.MinLength, PasswordMaxLength)
+	}
+
+	var upper, lower, number, special bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			number = true
+		default:
+			special = true
+		}
+	}
+	if p.RequireUppercase && !upper {
+		return errors.New("password must contain at least one uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		return errors.New("password must contain at least one lowercase letter")
+	}
+	if p.RequireNumber && !number {
+		return errors.New("password must contain at least one number")
+	}
+	if p.RequireSpecial && !special {
+		return errors.New("password must contain at least one special character")
+	}
+	return nil
 }
 
 // Database ...
@@ -67,6 +138,14 @@ type Email struct {
 	From     string `json:"from"`
 }
 
+// RetrySetting holds the settings of retrying failed replication jobs
+type RetrySetting struct {
+	MaxAttempts  int     `json:"max_attempts"`  // 0 means retrying until the job succeeds
+	InitialDelay int     `json:"initial_delay"` // in second
+	Multiplier   float64 `json:"multiplier"`
+	Jitter       float64 `json:"jitter"` // the fraction by which the delay is randomized, between 0 and 1
+}
+
 /*
 // Registry ...
 type Registry struct {
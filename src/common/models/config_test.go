@@ -0,0 +1,44 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := &PasswordPolicy{
+		MinLength:        8,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireNumber:    true,
+	}
+	assert.Nil(t, policy.Validate("Harbor12345"))
+	assert.NotNil(t, policy.Validate("Hb1"))
+	assert.NotNil(t, policy.Validate("Hb1"+strings.Repeat("a", PasswordMaxLength)))
+	assert.NotNil(t, policy.Validate("harbor12345"))
+	assert.NotNil(t, policy.Validate("HARBOR12345"))
+	assert.NotNil(t, policy.Validate("HarborHarbor"))
+
+	policy.RequireSpecial = true
+	assert.NotNil(t, policy.Validate("Harbor12345"))
+	assert.Nil(t, policy.Validate("Harbor12345!"))
+
+	policy = &PasswordPolicy{MinLength: 4}
+	assert.Nil(t, policy.Validate("pass"))
+}
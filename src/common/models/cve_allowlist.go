@@ -0,0 +1,55 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// CVEAllowlistTable is the name of the table whose data is mapped by CVEAllowlistItem struct.
+const CVEAllowlistTable = "cve_allowlist"
+
+var cveIDReg = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// CVEAllowlistItem is a CVE which is accepted by the system or a project, it
+// is excluded when computing the severity used by the policy which prevents
+// vulnerable images from running
+type CVEAllowlistItem struct {
+	ID int64 `orm:"pk;auto;column(id)" json:"-"`
+	// ProjectID is 0 if the CVE is allowlisted in system level
+	ProjectID int64  `orm:"column(project_id)" json:"-"`
+	CVEID     string `orm:"column(cve_id)" json:"cve_id"`
+	// ExpiresAt is a unix timestamp, the item never expires if it is 0
+	ExpiresAt    int64     `orm:"column(expires_at)" json:"expires_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName is required by by beego orm to map CVEAllowlistItem to table cve_allowlist
+func (c *CVEAllowlistItem) TableName() string {
+	return CVEAllowlistTable
+}
+
+// Valid checks the fields of the item which are set by users
+func (c *CVEAllowlistItem) Valid() error {
+	if !cveIDReg.MatchString(c.CVEID) || len(c.CVEID) > 64 {
+		return fmt.Errorf("invalid CVE ID: %s", c.CVEID)
+	}
+	if c.ExpiresAt < 0 {
+		return fmt.Errorf("invalid expiration time of %s: %d", c.CVEID, c.ExpiresAt)
+	}
+	return nil
+}
@@ -16,14 +16,19 @@ package models
 
 // LdapConf holds information about ldap configuration
 type LdapConf struct {
-	LdapURL               string `json:"ldap_url"`
-	LdapSearchDn          string `json:"ldap_search_dn"`
-	LdapSearchPassword    string `json:"ldap_search_password"`
-	LdapBaseDn            string `json:"ldap_base_dn"`
-	LdapFilter            string `json:"ldap_filter"`
-	LdapUID               string `json:"ldap_uid"`
-	LdapScope             int    `json:"ldap_scope"`
-	LdapConnectionTimeout int    `json:"ldap_connection_timeout"`
+	LdapURL                      string `json:"ldap_url"`
+	LdapSearchDn                 string `json:"ldap_search_dn"`
+	LdapSearchPassword           string `json:"ldap_search_password"`
+	LdapBaseDn                   string `json:"ldap_base_dn"`
+	LdapFilter                   string `json:"ldap_filter"`
+	LdapUID                      string `json:"ldap_uid"`
+	LdapScope                    int    `json:"ldap_scope"`
+	LdapConnectionTimeout        int    `json:"ldap_connection_timeout"`
+	LdapGroupBaseDN              string `json:"ldap_group_base_dn"`
+	LdapGroupFilter              string `json:"ldap_group_filter"`
+	LdapGroupNameAttribute       string `json:"ldap_group_name_attribute"`
+	LdapGroupScope               int    `json:"ldap_group_scope"`
+	LdapGroupMembershipAttribute string `json:"ldap_group_membership_attribute"`
 }
 
 // LdapUser ...
@@ -32,6 +37,8 @@ type LdapUser struct {
 	Email    string `json:"ldap_email"`
 	Realname string `json:"ldap_realname"`
 	DN       string `json:"-"`
+	// the DNs of the groups which the user belongs to
+	GroupDNList []string `json:"-"`
 }
 
 //LdapImportUser ...
@@ -44,3 +51,20 @@ type LdapFailedImportUser struct {
 	UID   string `json:"uid"`
 	Error string `json:"err_msg"`
 }
+
+// LdapGroup ...
+type LdapGroup struct {
+	GroupName string `json:"group_name"`
+	GroupDN   string `json:"ldap_group_dn"`
+}
+
+// LdapImportGroup ...
+type LdapImportGroup struct {
+	LdapGroupDNList []string `json:"ldap_group_dn_list"`
+}
+
+// LdapFailedImportGroup ...
+type LdapFailedImportGroup struct {
+	DN    string `json:"ldap_group_dn"`
+	Error string `json:"err_msg"`
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// LoginFailureTable is the name of table in DB that holds the failed
+	// logins
+	LoginFailureTable = "login_failure"
+	// LoginFailureTypeUser is the type of the failures counted per username
+	LoginFailureTypeUser = "user"
+	// LoginFailureTypeIP is the type of the failures counted per source IP
+	LoginFailureTypeIP = "ip"
+)
+
+// LoginFailure holds the count of consecutive failed logins of a username
+// or a source IP and the lockout caused by them
+type LoginFailure struct {
+	ID   int64  `orm:"pk;auto;column(id)" json:"id"`
+	Type string `orm:"column(type)" json:"type"`
+	Name string `orm:"column(name)" json:"name"`
+	// the failures since the last lockout
+	Failures int `orm:"column(failures)" json:"failures"`
+	// the unix time of the last failure
+	LastFailure int64 `orm:"column(last_failure)" json:"last_failure"`
+	// the times it has been locked since the last successful login, used
+	// to calculate the duration of the next lockout
+	Lockouts int `orm:"column(lockouts)" json:"lockouts"`
+	// the unix time until which it is locked
+	LockedUntil  int64     `orm:"column(locked_until)" json:"locked_until"`
+	CreationTime time.Time `orm:"column(creation_time)" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time)" json:"update_time"`
+}
+
+// TableName ...
+func (l *LoginFailure) TableName() string {
+	return LoginFailureTable
+}
+
+// IsLocked returns whether it is locked at the time
+func (l *LoginFailure) IsLocked(now time.Time) bool {
+	return l.LockedUntil > now.Unix()
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginFailureIsLocked(t *testing.T) {
+	now := time.Now()
+	lf := &LoginFailure{}
+	assert.False(t, lf.IsLocked(now))
+
+	lf.LockedUntil = now.Add(time.Minute).Unix()
+	assert.True(t, lf.IsLocked(now))
+	assert.False(t, lf.IsLocked(now.Add(2*time.Minute)))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// OIDCUserTable is the name of the table whose data is mapped by OIDCUser struct.
+const OIDCUserTable = "oidc_user"
+
+// OIDCSetting holds the settings of the OpenID Connect provider
+type OIDCSetting struct {
+	Endpoint     string   `json:"endpoint"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"-"`
+	Scope        []string `json:"scope"`
+	GroupsClaim  string   `json:"groups_claim"`
+	VerifyCert   bool     `json:"verify_cert"`
+	// RedirectURL is the URL the provider redirects users to after
+	// they login, it is built from the external endpoint of Harbor
+	RedirectURL string `json:"redirect_url"`
+}
+
+// OIDCUser links a user in Harbor with the subject in the ID token issued
+// by the OpenID Connect provider
+type OIDCUser struct {
+	ID     int64 `orm:"pk;auto;column(id)" json:"id"`
+	UserID int   `orm:"column(user_id)" json:"user_id"`
+	// SubIss is the concatenation of subject and issuer in the ID token,
+	// which identifies the user in the provider
+	SubIss string `orm:"column(subiss)" json:"-"`
+	// Secret is the encrypted CLI secret which is used as the password
+	// of docker login
+	Secret string `orm:"column(secret)" json:"-"`
+	// GroupNames is the JSON array of the groups in the last ID token of
+	// the user, so that the groups apply to docker login as well
+	GroupNames   string    `orm:"column(group_names)" json:"-"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName is required by by beego orm to map OIDCUser to table oidc_user
+func (o *OIDCUser) TableName() string {
+	return OIDCUserTable
+}
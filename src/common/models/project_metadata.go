@@ -0,0 +1,45 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package models
+
+import (
+	"time"
+)
+
+// ProjectMetadataTable is the name of the table whose data is mapped by ProjectMetadata struct.
+const ProjectMetadataTable = "project_metadata"
+
+// keys of project metadata
+const (
+	ProMetaEnableContentTrust = "enable_content_trust"
+	ProMetaPreventVulnerable  = "prevent_vulnerable_images_from_running"
+	ProMetaSeverity           = "prevent_vulnerable_images_from_running_severity"
+	ProMetaAutoScan           = "automatically_scan_images_on_push"
+)
+
+// ProjectMetadata holds a piece of metadata of a project, e.g. the policies
+// which are not stored in table project.
+type ProjectMetadata struct {
+	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
+	Name         string    `orm:"column(name)" json:"name"`
+	Value        string    `orm:"column(value)" json:"value"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName is required by by beego orm to map ProjectMetadata to table project_metadata
+func (pm *ProjectMetadata) TableName() string {
+	return ProjectMetadataTable
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+const (
+	//RepFilterKindRepository means the filter is applied to the repository name, e.g. library/ubuntu
+	RepFilterKindRepository = "repository"
+	//RepFilterKindTag means the filter is applied to the tag
+	RepFilterKindTag = "tag"
+	//RepFilterTypeInclude means only the names matching the filter are replicated
+	RepFilterTypeInclude = "include"
+	//RepFilterTypeExclude means the names matching the filter are not replicated
+	RepFilterTypeExclude = "exclude"
+	//RepFilterSyntaxGlob is the syntax of shell file name patterns, it is the default syntax
+	RepFilterSyntaxGlob = "glob"
+	//RepFilterSyntaxRegex is the syntax of regular expressions, the pattern must match the whole name
+	RepFilterSyntaxRegex = "regex"
+)
+
+// RepFilter filters the repositories or tags to be replicated by a policy
+type RepFilter struct {
+	Kind    string `json:"kind"`
+	Type    string `json:"type"`
+	Syntax  string `json:"syntax"`
+	Pattern string `json:"pattern"`
+}
+
+// Validate checks the fields of the filter and whether the pattern can be compiled
+func (f *RepFilter) Validate() error {
+	if f.Kind != RepFilterKindRepository && f.Kind != RepFilterKindTag {
+		return fmt.Errorf("invalid kind %q, should be %s or %s", f.Kind,
+			RepFilterKindRepository, RepFilterKindTag)
+	}
+	if f.Type != RepFilterTypeInclude && f.Type != RepFilterTypeExclude {
+		return fmt.Errorf("invalid type %q, should be %s or %s", f.Type,
+			RepFilterTypeInclude, RepFilterTypeExclude)
+	}
+	if len(f.Pattern) == 0 {
+		return fmt.Errorf("pattern can not be empty")
+	}
+
+	switch f.Syntax {
+	case "", RepFilterSyntaxGlob:
+		if _, err := path.Match(f.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %v", f.Pattern, err)
+		}
+	case RepFilterSyntaxRegex:
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			return fmt.Errorf("invalid regular expression %q: %v", f.Pattern, err)
+		}
+	default:
+		return fmt.Errorf("invalid syntax %q, should be %s or %s", f.Syntax,
+			RepFilterSyntaxGlob, RepFilterSyntaxRegex)
+	}
+
+	return nil
+}
+
+// Match returns whether the name matches the pattern of the filter, invalid
+// patterns match nothing
+func (f *RepFilter) Match(name string) bool {
+	if f.Syntax == RepFilterSyntaxRegex {
+		re, err := regexp.Compile("^(?:" + f.Pattern + ")$")
+		if err != nil {
+			return false
+		}
+		return re.MatchString(name)
+	}
+
+	matched, err := path.Match(f.Pattern, name)
+	return err == nil && matched
+}
+
+// MatchRepFilters returns whether the name passes the filters of the kind: it must
+// match at least one of the include filters if there is any, and must not match
+// any of the exclude filters
+func MatchRepFilters(filters []*RepFilter, kind, name string) bool {
+	included, hasInclude := false, false
+	for _, f := range filters {
+		if f.Kind != kind {
+			continue
+		}
+		if f.Type == RepFilterTypeExclude {
+			if f.Match(name) {
+				return false
+			}
+			continue
+		}
+		hasInclude = true
+		if !included && f.Match(name) {
+			included = true
+		}
+	}
+	return !hasInclude || included
+}
+
+// FilterTags returns the tags which pass the tag filters
+func FilterTags(filters []*RepFilter, tags []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		if MatchRepFilters(filters, RepFilterKindTag, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
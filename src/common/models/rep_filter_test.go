@@ -0,0 +1,67 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRepFilter(t *testing.T) {
+	valid := []*RepFilter{
+		{Kind: RepFilterKindRepository, Type: RepFilterTypeExclude, Pattern: "*/scratch"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Syntax: RepFilterSyntaxGlob, Pattern: "release-*"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Syntax: RepFilterSyntaxRegex, Pattern: `v\d+\.\d+`},
+	}
+	for _, f := range valid {
+		assert.Nil(t, f.Validate(), "%+v", f)
+	}
+
+	invalid := []*RepFilter{
+		{Kind: "image", Type: RepFilterTypeInclude, Pattern: "*"},
+		{Kind: RepFilterKindTag, Type: "only", Pattern: "*"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Pattern: "[a-"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Syntax: RepFilterSyntaxRegex, Pattern: "(a"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Syntax: "wildcard", Pattern: "*"},
+	}
+	for _, f := range invalid {
+		assert.NotNil(t, f.Validate(), "%+v", f)
+	}
+}
+
+func TestMatchRepFilters(t *testing.T) {
+	filters := []*RepFilter{
+		{Kind: RepFilterKindRepository, Type: RepFilterTypeExclude, Pattern: "*/scratch"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Pattern: "release-*"},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeInclude, Syntax: RepFilterSyntaxRegex, Pattern: `v\d+`},
+		{Kind: RepFilterKindTag, Type: RepFilterTypeExclude, Pattern: "*-rc"},
+	}
+
+	assert.True(t, MatchRepFilters(filters, RepFilterKindRepository, "library/ubuntu"))
+	assert.False(t, MatchRepFilters(filters, RepFilterKindRepository, "library/scratch"))
+
+	assert.True(t, MatchRepFilters(filters, RepFilterKindTag, "release-1.0"))
+	assert.True(t, MatchRepFilters(filters, RepFilterKindTag, "v2"))
+	assert.False(t, MatchRepFilters(filters, RepFilterKindTag, "v2.0"))
+	assert.False(t, MatchRepFilters(filters, RepFilterKindTag, "latest"))
+	assert.False(t, MatchRepFilters(filters, RepFilterKindTag, "release-2.0-rc"))
+
+	assert.True(t, MatchRepFilters(nil, RepFilterKindTag, "latest"))
+
+	assert.Equal(t, []string{"release-1.0", "v3"},
+		FilterTags(filters, []string{"latest", "release-1.0", "v3", "release-1.1-rc"}))
+}
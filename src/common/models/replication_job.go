@@ -19,6 +19,7 @@ import (
 
 	"github.com/astaxie/beego/validation"
 	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/common/utils/cron"
 )
 
 const (
@@ -26,6 +27,10 @@ const (
 	RepOpTransfer string = "transfer"
 	//RepOpDelete represents the operation of a job to remove repository from a remote registry/harbor instance.
 	RepOpDelete string = "delete"
+	//RepDirectionPush represents the policy which pushes repositories of the project to the target.
+	RepDirectionPush string = "push"
+	//RepDirectionPull represents the policy which pulls repositories from the target into the project.
+	RepDirectionPull string = "pull"
 	//UISecretCookie is the cookie name to contain the UI secret
 	UISecretCookie string = "secret"
 	//RepTargetTable is the table name for replication targets
@@ -44,15 +49,27 @@ type RepPolicy struct {
 	TargetID    int64  `orm:"column(target_id)" json:"target_id"`
 	TargetName  string `json:"target_name,omitempty"`
 	Name        string `orm:"column(name)" json:"name"`
+	// Direction is "push" or "pull", the policy pushes if it is empty
+	Direction string `orm:"column(direction)" json:"direction"`
 	//	Target       RepTarget `orm:"-" json:"target"`
 	Enabled       int       `orm:"column(enabled)" json:"enabled"`
 	Description   string    `orm:"column(description)" json:"description"`
 	CronStr       string    `orm:"column(cron_str)" json:"cron_str"`
 	StartTime     time.Time `orm:"column(start_time)" json:"start_time"`
-	CreationTime  time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
-	UpdateTime    time.Time `orm:"column(update_time);auto_now" json:"update_time"`
-	ErrorJobCount int       `json:"error_job_count"`
-	Deleted       int       `orm:"column(deleted)" json:"deleted"`
+	LastRunTime   time.Time `orm:"column(last_run_time)" json:"last_run_time"`
+	NextRunTime   time.Time `orm:"column(next_run_time)" json:"next_run_time"`
+	ScheduleError string    `orm:"column(schedule_error)" json:"schedule_error"`
+	// the retry settings of the jobs of this policy, zero values mean using the global settings
+	RetryMaxAttempts  int          `orm:"column(retry_max_attempts)" json:"retry_max_attempts"`
+	RetryInitialDelay int          `orm:"column(retry_initial_delay)" json:"retry_initial_delay"`
+	RetryMultiplier   float64      `orm:"column(retry_multiplier)" json:"retry_multiplier"`
+	RetryJitter       float64      `orm:"column(retry_jitter)" json:"retry_jitter"`
+	Filters           string       `orm:"column(filters)" json:"-"`
+	FilterList        []*RepFilter `orm:"-" json:"filters"`
+	CreationTime      time.Time    `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime        time.Time    `orm:"column(update_time);auto_now" json:"update_time"`
+	ErrorJobCount     int          `json:"error_job_count"`
+	Deleted           int          `orm:"column(deleted)" json:"deleted"`
 }
 
 // Valid ...
@@ -77,8 +94,43 @@ func (r *RepPolicy) Valid(v *validation.Validation) {
 		v.SetError("enabled", "must be 0 or 1")
 	}
 
+	if len(r.Direction) != 0 && r.Direction != RepDirectionPush &&
+		r.Direction != RepDirectionPull {
+		v.SetError("direction", "must be push or pull")
+	}
+
 	if len(r.CronStr) > 256 {
 		v.SetError("cron_str", "max length is 256")
+	} else if len(r.CronStr) != 0 {
+		if _, err := cron.Parse(r.CronStr); err != nil {
+			v.SetError("cron_str", err.Error())
+		}
+	}
+
+	if r.RetryMaxAttempts < 0 {
+		v.SetError("retry_max_attempts", "can not be negative")
+	}
+
+	if r.RetryInitialDelay < 0 {
+		v.SetError("retry_initial_delay", "can not be negative")
+	}
+
+	if r.RetryMultiplier != 0 && r.RetryMultiplier < 1 {
+		v.SetError("retry_multiplier", "must be 0 or not less than 1")
+	}
+
+	if r.RetryJitter < 0 || r.RetryJitter > 1 {
+		v.SetError("retry_jitter", "must be between 0 and 1")
+	}
+
+	for _, filter := range r.FilterList {
+		if filter == nil {
+			v.SetError("filters", "can not contain null")
+			continue
+		}
+		if err := filter.Validate(); err != nil {
+			v.SetError("filters", err.Error())
+		}
 	}
 }
 
@@ -93,6 +145,8 @@ type RepJob struct {
 	Tags       string   `orm:"column(tags)" json:"-"`
 	TagList    []string `orm:"-" json:"tags"`
 	//	Policy       RepPolicy `orm:"-" json:"policy"`
+	Attempts     int       `orm:"column(attempts)" json:"attempts"`
+	RetryTime    time.Time `orm:"column(retry_time)" json:"retry_time"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
@@ -146,6 +200,22 @@ func (r *RepJob) TableName() string {
 	return RepJobTable
 }
 
+// IsPull returns whether the policy pulls repositories from the target
+// into the project
+func (r *RepPolicy) IsPull() bool {
+	return r.Direction == RepDirectionPull
+}
+
+// MatchRepository returns whether the repository passes the repository filters of the policy
+func (r *RepPolicy) MatchRepository(repository string) bool {
+	return MatchRepFilters(r.FilterList, RepFilterKindRepository, repository)
+}
+
+// FilterTags returns the tags which pass the tag filters of the policy
+func (r *RepPolicy) FilterTags(tags []string) []string {
+	return FilterTags(r.FilterList, tags)
+}
+
 //TableName is required by by beego orm to map RepPolicy to table replication_policy
 func (r *RepPolicy) TableName() string {
 	return RepPolicyTable
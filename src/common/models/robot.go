@@ -0,0 +1,142 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RobotTable is the name of the table whose data is mapped by Robot struct.
+const RobotTable = "robot"
+
+const (
+	// RobotNamePrefix is the prefix of the name a robot account uses to
+	// authenticate itself, e.g. robot$library+ci
+	RobotNamePrefix = "robot$"
+	// RobotNameSeparator separates the project name and the robot name in
+	// the name a robot account uses to authenticate itself, it is allowed
+	// in neither project names nor robot names
+	RobotNameSeparator = "+"
+	// RobotActionPull allows the robot to pull images
+	RobotActionPull = "pull"
+	// RobotActionPush allows the robot to push images
+	RobotActionPush = "push"
+)
+
+var robotNameReg = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+// Robot is an account which is used by non-human clients, e.g. CI pipelines,
+// to access the repositories of a project
+type Robot struct {
+	ID          int64  `orm:"pk;auto;column(id)" json:"id"`
+	Name        string `orm:"column(name)" json:"name"`
+	Description string `orm:"column(description)" json:"description"`
+	ProjectID   int64  `orm:"column(project_id)" json:"project_id"`
+	// Secret is the salted hash of the token generated for the robot
+	Secret string `orm:"column(secret)" json:"-"`
+	Salt   string `orm:"column(salt)" json:"-"`
+	// Actions and Repositories are comma separated lists stored in database
+	Actions        string   `orm:"column(actions)" json:"-"`
+	ActionList     []string `orm:"-" json:"actions"`
+	Repositories   string   `orm:"column(repositories)" json:"-"`
+	RepositoryList []string `orm:"-" json:"repositories"`
+	// ExpiresAt is a unix timestamp, the robot never expires if it is 0
+	ExpiresAt    int64     `orm:"column(expires_at)" json:"expires_at"`
+	Disabled     bool      `orm:"column(disabled)" json:"disabled"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName is required by by beego orm to map Robot to table robot
+func (r *Robot) TableName() string {
+	return RobotTable
+}
+
+// Valid checks the fields of the robot which are set by users
+func (r *Robot) Valid() error {
+	if !robotNameReg.MatchString(r.Name) || len(r.Name) > 64 {
+		return fmt.Errorf("invalid name: %s", r.Name)
+	}
+	if len(r.ActionList) == 0 {
+		return fmt.Errorf("at least one action is required")
+	}
+	for _, action := range r.ActionList {
+		if action != RobotActionPull && action != RobotActionPush {
+			return fmt.Errorf("invalid action: %s", action)
+		}
+	}
+	for _, repository := range r.RepositoryList {
+		if len(repository) == 0 || strings.Contains(repository, ",") {
+			return fmt.Errorf("invalid repository: %s", repository)
+		}
+	}
+	if r.ExpiresAt < 0 {
+		return fmt.Errorf("invalid expires_at: %d", r.ExpiresAt)
+	}
+	return nil
+}
+
+// RobotLoginName returns the name the robot of the project uses to
+// authenticate itself, robot names are only unique in a project
+func RobotLoginName(projectName, name string) string {
+	return RobotNamePrefix + projectName + RobotNameSeparator + name
+}
+
+// ParseRobotLoginName returns the project name and the robot name in the
+// login name of a robot, ok is false if it isn't the login name of a robot
+func ParseRobotLoginName(loginName string) (projectName, name string, ok bool) {
+	if !strings.HasPrefix(loginName, RobotNamePrefix) {
+		return "", "", false
+	}
+	strs := strings.SplitN(strings.TrimPrefix(loginName, RobotNamePrefix), RobotNameSeparator, 2)
+	if len(strs) != 2 || len(strs[0]) == 0 || len(strs[1]) == 0 {
+		return "", "", false
+	}
+	return strs[0], strs[1], true
+}
+
+// IsExpired returns whether the robot is expired
+func (r *Robot) IsExpired() bool {
+	return r.ExpiresAt > 0 && time.Now().Unix() >= r.ExpiresAt
+}
+
+// HasAction returns whether the robot is allowed to do the action
+func (r *Robot) HasAction(action string) bool {
+	for _, a := range r.ActionList {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessRepository returns whether the robot can access the repository,
+// the repository name doesn't contain the project name, e.g. ubuntu for
+// library/ubuntu. All repositories of the project can be accessed if no
+// repository is specified
+func (r *Robot) CanAccessRepository(repository string) bool {
+	if len(r.RepositoryList) == 0 {
+		return true
+	}
+	for _, repo := range r.RepositoryList {
+		if repo == repository {
+			return true
+		}
+	}
+	return false
+}
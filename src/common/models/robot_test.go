@@ -0,0 +1,74 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidRobot(t *testing.T) {
+	valid := &Robot{
+		Name:           "ci",
+		ActionList:     []string{RobotActionPull, RobotActionPush},
+		RepositoryList: []string{"app", "team/app"},
+	}
+	assert.Nil(t, valid.Valid())
+
+	invalid := []*Robot{
+		{Name: "", ActionList: []string{RobotActionPull}},
+		{Name: "robot$ci", ActionList: []string{RobotActionPull}},
+		{Name: "ci"},
+		{Name: "ci", ActionList: []string{"delete"}},
+		{Name: "ci", ActionList: []string{RobotActionPull}, RepositoryList: []string{"a,b"}},
+		{Name: "ci", ActionList: []string{RobotActionPull}, ExpiresAt: -1},
+	}
+	for _, r := range invalid {
+		assert.NotNil(t, r.Valid(), "%+v", r)
+	}
+}
+
+func TestRobotPermissions(t *testing.T) {
+	r := &Robot{
+		ActionList: []string{RobotActionPull},
+	}
+	assert.False(t, r.IsExpired())
+	assert.True(t, r.HasAction(RobotActionPull))
+	assert.False(t, r.HasAction(RobotActionPush))
+	assert.True(t, r.CanAccessRepository("app"))
+
+	r.RepositoryList = []string{"app"}
+	assert.True(t, r.CanAccessRepository("app"))
+	assert.False(t, r.CanAccessRepository("other"))
+
+	r.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	assert.True(t, r.IsExpired())
+}
+
+func TestRobotLoginName(t *testing.T) {
+	loginName := RobotLoginName("library", "ci")
+	assert.Equal(t, "robot$library+ci", loginName)
+	projectName, name, ok := ParseRobotLoginName(loginName)
+	assert.True(t, ok)
+	assert.Equal(t, "library", projectName)
+	assert.Equal(t, "ci", name)
+
+	for _, loginName := range []string{"ci", "robot$ci", "robot$+ci", "robot$library+", "library+ci"} {
+		_, _, ok = ParseRobotLoginName(loginName)
+		assert.False(t, ok, loginName)
+	}
+}
@@ -14,6 +14,10 @@
 
 package models
 
+import (
+	"fmt"
+)
+
 const (
 	//PROJECTADMIN project administrator
 	PROJECTADMIN = 1
@@ -23,6 +27,43 @@ const (
 	GUEST = 3
 )
 
+// the permissions which are granted to the roles of projects
+const (
+	PermProjectRead   = "project.read"
+	PermProjectDelete = "project.delete"
+	PermRepoPull      = "repository.pull"
+	PermRepoPush      = "repository.push"
+	PermRepoDelete    = "repository.delete"
+	PermTagDelete     = "tag.delete"
+	PermScan          = "scan"
+	PermMemberManage  = "member.manage"
+	PermPolicyManage  = "policy.manage"
+	PermLogRead       = "log.read"
+)
+
+// the codes of the builtin roles
+const (
+	roleCodeProjAdmin = "MDRWS"
+	roleCodeDeveloper = "RWS"
+	roleCodeGuest     = "RS"
+)
+
+const roleNameMaxLength = 20
+
+// Permissions contains all the permissions which can be granted to roles
+var Permissions = []string{
+	PermProjectRead,
+	PermProjectDelete,
+	PermRepoPull,
+	PermRepoPush,
+	PermRepoDelete,
+	PermTagDelete,
+	PermScan,
+	PermMemberManage,
+	PermPolicyManage,
+	PermLogRead,
+}
+
 // Role holds the details of a role.
 type Role struct {
 	RoleID   int    `orm:"pk;auto;column(role_id)" json:"role_id"`
@@ -30,4 +71,53 @@ type Role struct {
 	Name     string `orm:"column(name)" json:"role_name"`
 
 	RoleMask int `orm:"role_mask" json:"role_mask"`
+	// Permissions is a comma separated list stored in database
+	Permissions    string   `orm:"column(permissions)" json:"-"`
+	PermissionList []string `orm:"-" json:"permissions"`
+}
+
+// IsBuiltin returns whether the role is one of projectAdmin, developer and
+// guest, which can not be modified
+func (r *Role) IsBuiltin() bool {
+	switch r.RoleCode {
+	case roleCodeProjAdmin, roleCodeDeveloper, roleCodeGuest:
+		return true
+	}
+	return false
+}
+
+// Valid checks the fields of the role which are set by users
+func (r *Role) Valid() error {
+	if len(r.Name) == 0 || len(r.Name) > roleNameMaxLength {
+		return fmt.Errorf("invalid name: %s", r.Name)
+	}
+	if len(r.PermissionList) == 0 {
+		return fmt.Errorf("at least one permission is required")
+	}
+	for _, perm := range r.PermissionList {
+		if !IsValidPermission(perm) {
+			return fmt.Errorf("invalid permission: %s", perm)
+		}
+	}
+	return nil
+}
+
+// HasPermission returns whether the role grants the permission
+func (r *Role) HasPermission(permission string) bool {
+	for _, perm := range r.PermissionList {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPermission returns whether the permission is a known one
+func IsValidPermission(permission string) bool {
+	for _, perm := range Permissions {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
 }
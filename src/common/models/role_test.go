@@ -0,0 +1,56 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidRole(t *testing.T) {
+	valid := &Role{
+		Name:           "scanner",
+		PermissionList: []string{PermProjectRead, PermScan},
+	}
+	assert.Nil(t, valid.Valid())
+
+	invalid := []*Role{
+		{Name: "", PermissionList: []string{PermScan}},
+		{Name: "a_name_longer_than_twenty", PermissionList: []string{PermScan}},
+		{Name: "scanner"},
+		{Name: "scanner", PermissionList: []string{"repository.copy"}},
+	}
+	for _, r := range invalid {
+		assert.NotNil(t, r.Valid(), "%+v", r)
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	r := &Role{
+		RoleCode:       "RS",
+		PermissionList: []string{PermProjectRead, PermRepoPull},
+	}
+	assert.True(t, r.IsBuiltin())
+	assert.True(t, r.HasPermission(PermRepoPull))
+	assert.False(t, r.HasPermission(PermRepoPush))
+
+	r = &Role{
+		Name: "scanner",
+	}
+	assert.False(t, r.IsBuiltin())
+	assert.False(t, IsValidPermission("repository.copy"))
+	assert.True(t, IsValidPermission(PermMemberManage))
+}
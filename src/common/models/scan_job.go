@@ -19,6 +19,15 @@ import "time"
 //ScanJobTable is the name of the table whose data is mapped by ScanJob struct.
 const ScanJobTable = "img_scan_job"
 
+//ScanAllJobTable is the name of the table whose data is mapped by ScanAllJob struct.
+const ScanAllJobTable = "scan_all_job"
+
+// the triggers of scanning all images
+const (
+	ScanAllTriggerManual   = "manual"
+	ScanAllTriggerSchedule = "schedule"
+)
+
 //ScanOverviewTable is the name of the table whose data is mapped by ImgScanOverview struct.
 const ScanOverviewTable = "img_scan_overview"
 
@@ -29,6 +38,7 @@ type ScanJob struct {
 	Repository   string    `orm:"column(repository)" json:"repository"`
 	Tag          string    `orm:"column(tag)" json:"tag"`
 	Digest       string    `orm:"column(digest)" json:"digest"`
+	RetryTime    time.Time `orm:"column(retry_time)" json:"retry_time"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
@@ -46,11 +56,50 @@ const (
 	SevHigh
 )
 
+// String returns the name of the severity.
+func (sev Severity) String() string {
+	switch sev {
+	case SevNone:
+		return "none"
+	case SevUnknown:
+		return "unknown"
+	case SevLow:
+		return "low"
+	case SevMedium:
+		return "medium"
+	case SevHigh:
+		return "high"
+	default:
+		return "invalid"
+	}
+}
+
 //TableName is required by by beego orm to map ScanJob to table img_scan_job
 func (s *ScanJob) TableName() string {
 	return ScanJobTable
 }
 
+//ScanAllJob records a run of scanning all images, the images referenced by
+//more than one tag are only counted and scanned once.
+type ScanAllJob struct {
+	ID      int64  `orm:"pk;auto;column(id)" json:"id"`
+	Status  string `orm:"column(status)" json:"status"`
+	Trigger string `orm:"column(triggered_by)" json:"trigger"`
+	// Total is the number of images to scan, it is 0 until all the tags
+	// are walked through
+	Total int `orm:"column(total)" json:"total"`
+	// Scheduled is the number of the scan jobs created so far
+	Scheduled int       `orm:"column(scheduled)" json:"scheduled"`
+	Error     string    `orm:"column(error)" json:"error"`
+	StartTime time.Time `orm:"column(start_time)" json:"start_time"`
+	EndTime   time.Time `orm:"column(end_time)" json:"end_time"`
+}
+
+//TableName is required by by beego orm to map ScanAllJob to table scan_all_job
+func (s *ScanAllJob) TableName() string {
+	return ScanAllJobTable
+}
+
 //ImgScanOverview mapped to a record of image scan overview.
 type ImgScanOverview struct {
 	Digest          string              `orm:"pk;column(image_digest)" json:"image_digest"`
@@ -60,8 +109,16 @@ type ImgScanOverview struct {
 	CompOverviewStr string              `orm:"column(components_overview)" json:"-"`
 	CompOverview    *ComponentsOverview `orm:"-" json:"components,omitempty"`
 	DetailsKey      string              `orm:"column(details_key)" json:"details_key"`
-	CreationTime    time.Time           `orm:"column(creation_time);auto_now_add" json:"creation_time,omitempty"`
-	UpdateTime      time.Time           `orm:"column(update_time);auto_now" json:"update_time,omitempty"`
+	// Scanner is the kind of the scanner which the details key belongs to,
+	// it is empty if the image was scanned by clair before the scanner is
+	// pluggable
+	Scanner string `orm:"column(scanner)" json:"-"`
+	// VulnsStr stores the severity of each CVE in JSON, it is empty if the
+	// image was scanned before the CVE allowlist is supported
+	VulnsStr     string              `orm:"column(vulnerabilities)" json:"-"`
+	Vulns        map[string]Severity `orm:"-" json:"-"`
+	CreationTime time.Time           `orm:"column(creation_time);auto_now_add" json:"creation_time,omitempty"`
+	UpdateTime   time.Time           `orm:"column(update_time);auto_now" json:"update_time,omitempty"`
 }
 
 //TableName ...
@@ -0,0 +1,54 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// SigningKeyTable is the name of the table whose data is mapped by
+// SigningKey struct.
+const SigningKeyTable = "signing_key"
+
+// SigningKey is a key used by the token service to sign the tokens of
+// registry and notary
+type SigningKey struct {
+	ID int64 `orm:"pk;auto;column(id)" json:"id"`
+	// KeyID is put in the "kid" header of the tokens signed by the key
+	KeyID string `orm:"column(key_id)" json:"kid"`
+	// PrivateKey is the PEM encoded private key encrypted by the secret key
+	PrivateKey string `orm:"column(private_key)" json:"-"`
+	// Certificate is the PEM encoded self-signed certificate of the key, it
+	// can be appended to the root certificate bundle of the services which
+	// don't support JWKS. It is empty for the key imported from the file
+	Certificate string `orm:"column(certificate)" json:"certificate"`
+	// Active key is the one used to sign new tokens, only one key is active
+	Active bool `orm:"column(active)" json:"active"`
+	// ExpiresAt is a unix timestamp set when the key is retired, the key is
+	// published until then so that the tokens signed by it stay valid
+	ExpiresAt    int64     `orm:"column(expires_at)" json:"expires_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName is required by by beego orm to map SigningKey to table signing_key
+func (s *SigningKey) TableName() string {
+	return SigningKeyTable
+}
+
+// IsExpired returns whether the key is retired and the overlap window has
+// passed
+func (s *SigningKey) IsExpired() bool {
+	return s.ExpiresAt > 0 && time.Now().Unix() >= s.ExpiresAt
+}
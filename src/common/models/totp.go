@@ -0,0 +1,66 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// UserTOTPTable is the name of the table whose data is mapped by UserTOTP struct.
+const UserTOTPTable = "user_totp"
+
+// UserTOTP holds the TOTP (time-based one-time password) settings of a
+// user who enrolls in two-factor authentication
+type UserTOTP struct {
+	ID     int64 `orm:"pk;auto;column(id)" json:"id"`
+	UserID int   `orm:"column(user_id)" json:"user_id"`
+	// Secret is the encrypted key from which the one-time passwords are
+	// generated
+	Secret string `orm:"column(secret)" json:"-"`
+	// Enabled is false until the user verifies the enrollment with a
+	// one-time password
+	Enabled bool `orm:"column(enabled)" json:"enabled"`
+	// LastStep is the time step of the last accepted one-time password,
+	// the passwords of this step or earlier ones are rejected to avoid replay
+	LastStep int64 `orm:"column(last_step)" json:"-"`
+	// RecoveryCodes is a comma separated list of the hashes of the unused
+	// recovery codes
+	RecoveryCodes string `orm:"column(recovery_codes)" json:"-"`
+	// CLISecret is the encrypted secret which is used as the password of
+	// docker login once two-factor authentication is enabled
+	CLISecret    string    `orm:"column(cli_secret)" json:"-"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// TableName is required by by beego orm to map UserTOTP to table user_totp
+func (u *UserTOTP) TableName() string {
+	return UserTOTPTable
+}
+
+// TOTPEnrollment is returned when a user starts to enroll in two-factor
+// authentication
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth:// URI which authenticator apps scan
+	// as a QR code
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TOTPActivation is returned when a user verifies the enrollment
+type TOTPActivation struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	CLISecret     string   `json:"cli_secret"`
+}
@@ -32,11 +32,17 @@ type User struct {
 	//to it.
 	Role int `orm:"-" json:"role_id"`
 	//	RoleList     []Role `json:"role_list"`
-	HasAdminRole int       `orm:"column(sysadmin_flag)" json:"has_admin_role"`
-	ResetUUID    string    `orm:"column(reset_uuid)" json:"reset_uuid"`
-	Salt         string    `orm:"column(salt)" json:"-"`
-	CreationTime time.Time `orm:"creation_time" json:"creation_time"`
-	UpdateTime   time.Time `orm:"update_time" json:"update_time"`
+	HasAdminRole int    `orm:"column(sysadmin_flag)" json:"has_admin_role"`
+	ResetUUID    string `orm:"column(reset_uuid)" json:"reset_uuid"`
+	Salt         string `orm:"column(salt)" json:"-"`
+	// PasswordVersion is the version of the algorithm with which the
+	// password is hashed
+	PasswordVersion string    `orm:"column(password_version)" json:"-"`
+	CreationTime    time.Time `orm:"creation_time" json:"creation_time"`
+	UpdateTime      time.Time `orm:"update_time" json:"update_time"`
+	// GroupList holds the IDs of the user groups the user belongs to, it
+	// is resolved when the user logs in
+	GroupList []int `orm:"-" json:"-"`
 }
 
 // UserQuery ...
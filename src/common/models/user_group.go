@@ -0,0 +1,55 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	// UserGroupTable is the name of table in DB that holds the user groups
+	UserGroupTable = "user_group"
+	// LdapGroupType is the type of groups imported from LDAP
+	LdapGroupType = 1
+	// OIDCGroupType is the type of groups in the groups claim of the ID
+	// tokens issued by the OIDC provider, they are identified by names
+	OIDCGroupType = 2
+)
+
+// UserGroup holds the details of a group of users, e.g. a group in LDAP
+type UserGroup struct {
+	ID           int       `orm:"pk;auto;column(id)" json:"id"`
+	GroupName    string    `orm:"column(group_name)" json:"group_name"`
+	GroupType    int       `orm:"column(group_type)" json:"group_type"`
+	LdapGroupDN  string    `orm:"column(ldap_group_dn)" json:"ldap_group_dn"`
+	CreationTime time.Time `orm:"column(creation_time)" json:"creation_time"`
+	UpdateTime   time.Time `orm:"column(update_time)" json:"update_time"`
+}
+
+// TableName ...
+func (u *UserGroup) TableName() string {
+	return UserGroupTable
+}
+
+// ProjectGroupMember holds the details of a group which is a member of
+// the project
+type ProjectGroupMember struct {
+	ProjectID   int64  `orm:"column(project_id)" json:"project_id"`
+	GroupID     int    `orm:"column(group_id)" json:"group_id"`
+	GroupName   string `orm:"column(group_name)" json:"group_name"`
+	LdapGroupDN string `orm:"column(ldap_group_dn)" json:"ldap_group_dn"`
+	Role        int    `orm:"column(role)" json:"role_id"`
+	Rolename    string `orm:"column(rolename)" json:"role_name"`
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"time"
+)
+
+// UserSessionTable is the name of the table whose data is mapped by
+// UserSession struct.
+const UserSessionTable = "user_session"
+
+// UserSession records a session of UI created when the user logs in, the
+// session is only accepted while the record exists, so deleting the record
+// revokes the session on all the UI instances
+type UserSession struct {
+	ID int64 `orm:"pk;auto;column(id)" json:"id"`
+	// SessionID is the SHA256 digest of the ID of the session
+	SessionID string `orm:"column(session_id)" json:"-"`
+	UserID    int    `orm:"column(user_id)" json:"user_id"`
+	IP        string `orm:"column(ip)" json:"ip"`
+	UserAgent string `orm:"column(user_agent)" json:"user_agent"`
+	// LastActiveAt is a unix timestamp
+	LastActiveAt int64     `orm:"column(last_active_at)" json:"last_active_at"`
+	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	// Current is true if the session is the one the request is sent with
+	Current bool `orm:"-" json:"current"`
+}
+
+// TableName is required by by beego orm to map UserSession to table user_session
+func (u *UserSession) TableName() string {
+	return UserSessionTable
+}
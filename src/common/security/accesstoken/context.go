@@ -0,0 +1,49 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesstoken
+
+import (
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/security"
+)
+
+// SecurityContext implements security.Context interface based on personal
+// access tokens, it wraps the security context of the owner of the token
+// and limits the permissions if the token is read-only
+type SecurityContext struct {
+	security.Context
+	token *models.AccessToken
+}
+
+// NewSecurityContext ...
+func NewSecurityContext(ctx security.Context, token *models.AccessToken) *SecurityContext {
+	return &SecurityContext{
+		Context: ctx,
+		token:   token,
+	}
+}
+
+// Can returns whether the owner of the token has the permission to the
+// project, only the permissions to read are kept for read-only tokens
+func (s *SecurityContext) Can(permission string, projectIDOrName interface{}) bool {
+	if s.token.ReadOnly {
+		switch permission {
+		case models.PermProjectRead, models.PermRepoPull, models.PermLogRead:
+		default:
+			return false
+		}
+	}
+	return s.Context.Can(permission, projectIDOrName)
+}
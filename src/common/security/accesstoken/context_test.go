@@ -0,0 +1,60 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesstoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// fakeContext grants all the permissions to the user
+type fakeContext struct{}
+
+func (f *fakeContext) IsAuthenticated() bool {
+	return true
+}
+
+func (f *fakeContext) GetUsername() string {
+	return "jack"
+}
+
+func (f *fakeContext) IsSysAdmin() bool {
+	return false
+}
+
+func (f *fakeContext) Can(permission string, projectIDOrName interface{}) bool {
+	return true
+}
+
+func TestCan(t *testing.T) {
+	ctx := NewSecurityContext(&fakeContext{}, &models.AccessToken{})
+	assert.True(t, ctx.IsAuthenticated())
+	assert.Equal(t, "jack", ctx.GetUsername())
+	for _, perm := range models.Permissions {
+		assert.True(t, ctx.Can(perm, "library"))
+	}
+
+	ctx = NewSecurityContext(&fakeContext{}, &models.AccessToken{
+		ReadOnly: true,
+	})
+	assert.True(t, ctx.Can(models.PermProjectRead, "library"))
+	assert.True(t, ctx.Can(models.PermRepoPull, "library"))
+	assert.True(t, ctx.Can(models.PermLogRead, "library"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "library"))
+	assert.False(t, ctx.Can(models.PermRepoDelete, "library"))
+	assert.False(t, ctx.Can(models.PermMemberManage, "library"))
+}
@@ -22,10 +22,16 @@ type Context interface {
 	GetUsername() string
 	// IsSysAdmin returns whether the user is system admin
 	IsSysAdmin() bool
-	// HasReadPerm returns whether the user has read permission to the project
-	HasReadPerm(projectIDOrName interface{}) bool
-	// HasWritePerm  returns whether the user has write permission to the project
-	HasWritePerm(projectIDOrName interface{}) bool
-	// HasAllPerm  returns whether the user has all permissions to the project
-	HasAllPerm(projectIDOrName interface{}) bool
+	// Can returns whether the user has the permission to the project, the
+	// permissions are defined in models, e.g. models.PermRepoPush
+	Can(permission string, projectIDOrName interface{}) bool
+}
+
+// RepositoryScopedContext is implemented by the contexts whose permissions
+// to a project are only granted on some repositories of the project
+type RepositoryScopedContext interface {
+	Context
+	// CanAccessRepository returns whether the permissions to the project
+	// apply to the repository, e.g. library/ubuntu
+	CanAccessRepository(repository string) bool
 }
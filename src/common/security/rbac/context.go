@@ -15,12 +15,15 @@
 package rbac
 
 import (
-	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/utils/log"
 	"github.com/vmware/harbor/src/ui/projectmanager"
 )
 
+// rolePermissions returns the permissions granted by the roles
+var rolePermissions = dao.GetRolePermissions
+
 // SecurityContext implements security.Context interface based on database
 type SecurityContext struct {
 	user *models.User
@@ -58,8 +61,10 @@ func (s *SecurityContext) IsSysAdmin() bool {
 	return s.user.HasAdminRole == 1
 }
 
-// HasReadPerm returns whether the user has read permission to the project
-func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+// Can returns whether the user has the permission to the project, the
+// permissions are granted by the roles the user and the groups of the user
+// have to the project
+func (s *SecurityContext) Can(permission string, projectIDOrName interface{}) bool {
 	// not exist
 	exist, err := s.pm.Exist(projectIDOrName)
 	if err != nil {
@@ -72,18 +77,19 @@ func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
 		return false
 	}
 
-	// public project
-	public, err := s.pm.IsPublic(projectIDOrName)
-	if err != nil {
-		log.Errorf("failed to check the public of project %v: %v",
-			projectIDOrName, err)
-		return false
-	}
-	if public {
-		return true
+	// everyone can read and pull from public projects
+	if permission == models.PermProjectRead || permission == models.PermRepoPull {
+		public, err := s.pm.IsPublic(projectIDOrName)
+		if err != nil {
+			log.Errorf("failed to check the public of project %v: %v",
+				projectIDOrName, err)
+			return false
+		}
+		if public {
+			return true
+		}
 	}
 
-	// private project
 	if !s.IsAuthenticated() {
 		return false
 	}
@@ -93,59 +99,20 @@ func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
 		return true
 	}
 
-	roles, err := s.pm.GetRoles(s.GetUsername(), projectIDOrName)
+	roles, err := s.getRoles(projectIDOrName)
 	if err != nil {
 		log.Errorf("failed to get roles of user %s to project %v: %v",
 			s.GetUsername(), projectIDOrName, err)
 		return false
 	}
 
-	for _, role := range roles {
-		switch role {
-		case common.RoleProjectAdmin,
-			common.RoleDeveloper,
-			common.RoleGuest:
-			return true
-		}
-	}
-
-	return false
-}
-
-// HasWritePerm returns whether the user has write permission to the project
-func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
-	if !s.IsAuthenticated() {
-		return false
-	}
-
-	// project does not exist
-	exist, err := s.pm.Exist(projectIDOrName)
+	perms, err := rolePermissions(roles)
 	if err != nil {
-		log.Errorf("failed to check the existence of project %v: %v",
-			projectIDOrName, err)
+		log.Errorf("failed to get permissions of roles %v: %v", roles, err)
 		return false
 	}
-
-	if !exist {
-		return false
-	}
-
-	// system admin
-	if s.IsSysAdmin() {
-		return true
-	}
-
-	roles, err := s.pm.GetRoles(s.GetUsername(), projectIDOrName)
-	if err != nil {
-		log.Errorf("failed to get roles of user %s to project %v: %v",
-			s.GetUsername(), projectIDOrName, err)
-		return false
-	}
-
-	for _, role := range roles {
-		switch role {
-		case common.RoleProjectAdmin,
-			common.RoleDeveloper:
+	for _, perm := range perms {
+		if perm == permission {
 			return true
 		}
 	}
@@ -153,42 +120,21 @@ func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
 	return false
 }
 
-// HasAllPerm returns whether the user has all permissions to the project
-func (s *SecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
-	if !s.IsAuthenticated() {
-		return false
-	}
-
-	// project does not exist
-	exist, err := s.pm.Exist(projectIDOrName)
+// getRoles returns the roles that the user has to the project, including
+// the roles of the groups which the user belongs to
+func (s *SecurityContext) getRoles(projectIDOrName interface{}) ([]int, error) {
+	roles, err := s.pm.GetRoles(s.GetUsername(), projectIDOrName)
 	if err != nil {
-		log.Errorf("failed to check the existence of project %v: %v",
-			projectIDOrName, err)
-		return false
+		return nil, err
 	}
 
-	if !exist {
-		return false
-	}
-
-	// system admin
-	if s.IsSysAdmin() {
-		return true
+	if len(s.user.GroupList) == 0 {
+		return roles, nil
 	}
 
-	roles, err := s.pm.GetRoles(s.GetUsername(), projectIDOrName)
+	groupRoles, err := s.pm.GetGroupRoles(s.user.GroupList, projectIDOrName)
 	if err != nil {
-		log.Errorf("failed to get roles of user %s to project %v: %v",
-			s.GetUsername(), projectIDOrName, err)
-		return false
-	}
-
-	for _, role := range roles {
-		switch role {
-		case common.RoleProjectAdmin:
-			return true
-		}
+		return nil, err
 	}
-
-	return false
+	return append(roles, groupRoles...), nil
 }
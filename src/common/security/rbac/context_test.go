@@ -47,9 +47,33 @@ var (
 	}
 )
 
+// fakeRolePermissions returns the permissions of the builtin roles
+// without accessing the database
+func fakeRolePermissions(roles []int) ([]string, error) {
+	perms := map[int][]string{
+		common.RoleGuest: []string{models.PermProjectRead,
+			models.PermRepoPull, models.PermLogRead},
+		common.RoleDeveloper: []string{models.PermProjectRead,
+			models.PermRepoPull, models.PermRepoPush, models.PermLogRead},
+		common.RoleProjectAdmin: models.Permissions,
+		// a custom role
+		4: []string{models.PermScan},
+	}
+	result := []string{}
+	for _, role := range roles {
+		result = append(result, perms[role]...)
+	}
+	return result, nil
+}
+
+func init() {
+	rolePermissions = fakeRolePermissions
+}
+
 type fakePM struct {
-	projects []*models.Project
-	roles    map[string][]int
+	projects   []*models.Project
+	roles      map[string][]int
+	groupRoles map[string][]int
 }
 
 func (f *fakePM) IsPublic(projectIDOrName interface{}) (bool, error) {
@@ -63,6 +87,12 @@ func (f *fakePM) IsPublic(projectIDOrName interface{}) (bool, error) {
 func (f *fakePM) GetRoles(username string, projectIDOrName interface{}) ([]int, error) {
 	return f.roles[projectIDOrName.(string)], nil
 }
+func (f *fakePM) GetGroupRoles(groupIDs []int, projectIDOrName interface{}) ([]int, error) {
+	if len(groupIDs) == 0 {
+		return []int{}, nil
+	}
+	return f.groupRoles[projectIDOrName.(string)], nil
+}
 func (f *fakePM) Get(projectIDOrName interface{}) (*models.Project, error) {
 	for _, project := range f.projects {
 		if project.Name == projectIDOrName.(string) {
@@ -163,7 +193,7 @@ func TestIsSysAdmin(t *testing.T) {
 	assert.True(t, ctx.IsSysAdmin())
 }
 
-func TestHasReadPerm(t *testing.T) {
+func TestCanRead(t *testing.T) {
 	pm := &fakePM{
 		projects: []*models.Project{public, private, read},
 		roles: map[string][]int{
@@ -173,44 +203,47 @@ func TestHasReadPerm(t *testing.T) {
 
 	// non-exist project
 	ctx := NewSecurityContext(nil, pm)
-	assert.False(t, ctx.HasReadPerm("non_exist_project"))
+	assert.False(t, ctx.Can(models.PermProjectRead, "non_exist_project"))
 
 	// public project
 	ctx = NewSecurityContext(nil, pm)
-	assert.True(t, ctx.HasReadPerm("public_project"))
+	assert.True(t, ctx.Can(models.PermProjectRead, "public_project"))
+	assert.True(t, ctx.Can(models.PermRepoPull, "public_project"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "public_project"))
 
 	// private project, unauthenticated
 	ctx = NewSecurityContext(nil, pm)
-	assert.False(t, ctx.HasReadPerm("private_project"))
+	assert.False(t, ctx.Can(models.PermProjectRead, "private_project"))
 
 	// private project, authenticated, has no perm
 	ctx = NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.False(t, ctx.HasReadPerm("private_project"))
+	assert.False(t, ctx.Can(models.PermProjectRead, "private_project"))
 
 	// private project, authenticated, has read perm
 	ctx = NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.True(t, ctx.HasReadPerm("has_read_perm_project"))
+	assert.True(t, ctx.Can(models.PermProjectRead, "has_read_perm_project"))
+	assert.True(t, ctx.Can(models.PermRepoPull, "has_read_perm_project"))
 
 	// private project, authenticated, system admin
 	ctx = NewSecurityContext(&models.User{
 		Username:     "test",
 		HasAdminRole: 1,
 	}, pm)
-	assert.True(t, ctx.HasReadPerm("private_project"))
+	assert.True(t, ctx.Can(models.PermProjectRead, "private_project"))
 
 	// non-exist project, authenticated, system admin
 	ctx = NewSecurityContext(&models.User{
 		Username:     "test",
 		HasAdminRole: 1,
 	}, pm)
-	assert.False(t, ctx.HasReadPerm("non_exist_project"))
+	assert.False(t, ctx.Can(models.PermProjectRead, "non_exist_project"))
 }
 
-func TestHasWritePerm(t *testing.T) {
+func TestCanPush(t *testing.T) {
 	pm := &fakePM{
 		projects: []*models.Project{read, write, private},
 		roles: map[string][]int{
@@ -221,90 +254,109 @@ func TestHasWritePerm(t *testing.T) {
 
 	// unauthenticated
 	ctx := NewSecurityContext(nil, pm)
-	assert.False(t, ctx.HasWritePerm("has_write_perm_project"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "has_write_perm_project"))
 
 	// authenticated, non-exist project
 	ctx = NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.False(t, ctx.HasWritePerm("non_exist_project"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "non_exist_project"))
 
 	// authenticated, has read perm
 	ctx = NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.False(t, ctx.HasWritePerm("has_read_perm_project")) // authenticated, has read perm
+	assert.False(t, ctx.Can(models.PermRepoPush, "has_read_perm_project"))
 
 	// authenticated, has write perm
 	ctx = NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.True(t, ctx.HasWritePerm("has_write_perm_project"))
+	assert.True(t, ctx.Can(models.PermRepoPush, "has_write_perm_project"))
+	assert.False(t, ctx.Can(models.PermRepoDelete, "has_write_perm_project"))
 
 	// authenticated, system admin
 	ctx = NewSecurityContext(&models.User{
 		Username:     "test",
 		HasAdminRole: 1,
 	}, pm)
-	assert.True(t, ctx.HasReadPerm("private_project"))
+	assert.True(t, ctx.Can(models.PermRepoPush, "private_project"))
 
 	// authenticated, system admin, non-exist project
 	ctx = NewSecurityContext(&models.User{
 		Username:     "test",
 		HasAdminRole: 1,
 	}, pm)
-	assert.False(t, ctx.HasReadPerm("non_exist_project"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "non_exist_project"))
 }
 
-func TestHasAllPerm(t *testing.T) {
+func TestCanWithProjectAdminRole(t *testing.T) {
 	pm := &fakePM{
-		projects: []*models.Project{read, write, all, private},
+		projects: []*models.Project{write, all},
 		roles: map[string][]int{
-			"has_read_perm_project":  []int{common.RoleGuest},
 			"has_write_perm_project": []int{common.RoleGuest, common.RoleDeveloper},
-			"has_all_perm_project":   []int{common.RoleGuest, common.RoleDeveloper, common.RoleProjectAdmin},
+			"has_all_perm_project":   []int{common.RoleProjectAdmin},
 		},
 	}
 
 	// unauthenticated
 	ctx := NewSecurityContext(nil, pm)
-	assert.False(t, ctx.HasAllPerm("has_all_perm_project"))
+	assert.False(t, ctx.Can(models.PermMemberManage, "has_all_perm_project"))
 
-	// authenticated, non-exist project
+	// authenticated, developer
 	ctx = NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.False(t, ctx.HasAllPerm("non_exist_project"))
+	assert.False(t, ctx.Can(models.PermMemberManage, "has_write_perm_project"))
 
-	// authenticated, has read perm
-	ctx = NewSecurityContext(&models.User{
-		Username: "test",
-	}, pm)
-	assert.False(t, ctx.HasAllPerm("has_read_perm_project"))
+	// authenticated, project admin
+	for _, perm := range models.Permissions {
+		assert.True(t, ctx.Can(perm, "has_all_perm_project"))
+	}
+}
 
-	// authenticated, has write perm
-	ctx = NewSecurityContext(&models.User{
-		Username: "test",
-	}, pm)
-	assert.False(t, ctx.HasAllPerm("has_write_perm_project"))
+func TestCanWithCustomRole(t *testing.T) {
+	pm := &fakePM{
+		projects: []*models.Project{private},
+		roles: map[string][]int{
+			"private_project": []int{4},
+		},
+	}
 
-	// authenticated, has all perms
-	ctx = NewSecurityContext(&models.User{
+	ctx := NewSecurityContext(&models.User{
 		Username: "test",
 	}, pm)
-	assert.True(t, ctx.HasAllPerm("has_all_perm_project"))
+	assert.True(t, ctx.Can(models.PermScan, "private_project"))
+	assert.False(t, ctx.Can(models.PermProjectRead, "private_project"))
+	assert.False(t, ctx.Can(models.PermRepoPull, "private_project"))
+}
 
-	// authenticated, system admin
-	ctx = NewSecurityContext(&models.User{
-		Username:     "test",
-		HasAdminRole: 1,
+func TestCanByGroup(t *testing.T) {
+	pm := &fakePM{
+		projects: []*models.Project{read, write, private},
+		roles: map[string][]int{
+			"has_read_perm_project": []int{common.RoleGuest},
+		},
+		groupRoles: map[string][]int{
+			"has_read_perm_project":  []int{common.RoleDeveloper},
+			"has_write_perm_project": []int{common.RoleGuest},
+		},
+	}
+
+	// not a member of any group
+	ctx := NewSecurityContext(&models.User{
+		Username: "test",
 	}, pm)
-	assert.True(t, ctx.HasAllPerm("private_project"))
+	assert.False(t, ctx.Can(models.PermProjectRead, "has_write_perm_project"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "has_read_perm_project"))
 
-	// authenticated, system admin, non-exist project
+	// the roles of the groups are merged with the roles of the user
 	ctx = NewSecurityContext(&models.User{
-		Username:     "test",
-		HasAdminRole: 1,
+		Username:  "test",
+		GroupList: []int{1},
 	}, pm)
-	assert.False(t, ctx.HasAllPerm("non_exist_project"))
+	assert.True(t, ctx.Can(models.PermProjectRead, "has_write_perm_project"))
+	assert.False(t, ctx.Can(models.PermRepoPush, "has_write_perm_project"))
+	assert.True(t, ctx.Can(models.PermRepoPush, "has_read_perm_project"))
+	assert.False(t, ctx.Can(models.PermMemberManage, "has_read_perm_project"))
 }
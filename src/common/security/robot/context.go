@@ -0,0 +1,117 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/ui/projectmanager"
+)
+
+// SecurityContext implements security.Context interface based on
+// robot accounts
+type SecurityContext struct {
+	robot *models.Robot
+	pm    projectmanager.ProjectManager
+}
+
+// NewSecurityContext ...
+func NewSecurityContext(robot *models.Robot, pm projectmanager.ProjectManager) *SecurityContext {
+	return &SecurityContext{
+		robot: robot,
+		pm:    pm,
+	}
+}
+
+// IsAuthenticated returns true if the robot is enabled and not expired
+func (s *SecurityContext) IsAuthenticated() bool {
+	return s.robot != nil && !s.robot.Disabled && !s.robot.IsExpired()
+}
+
+// GetUsername returns the name the robot uses to authenticate itself,
+// e.g. robot$library+ci
+func (s *SecurityContext) GetUsername() string {
+	if !s.IsAuthenticated() {
+		return ""
+	}
+	project, err := s.pm.Get(s.robot.ProjectID)
+	if err != nil {
+		log.Errorf("failed to get project %d: %v", s.robot.ProjectID, err)
+		return ""
+	}
+	if project == nil {
+		log.Errorf("project %d of robot %s not found", s.robot.ProjectID, s.robot.Name)
+		return ""
+	}
+	return models.RobotLoginName(project.Name, s.robot.Name)
+}
+
+// IsSysAdmin always returns false
+func (s *SecurityContext) IsSysAdmin() bool {
+	return false
+}
+
+// Can returns true if the project is public and the permission is to read
+// it or pull images from it, or the robot belongs to the project and the
+// permission is granted by the actions of the robot
+func (s *SecurityContext) Can(permission string, projectIDOrName interface{}) bool {
+	switch permission {
+	case models.PermProjectRead, models.PermRepoPull:
+		public, err := s.pm.IsPublic(projectIDOrName)
+		if err != nil {
+			log.Errorf("failed to check the public of project %v: %v",
+				projectIDOrName, err)
+			return false
+		}
+		if public {
+			return true
+		}
+		return s.hasAction(projectIDOrName, models.RobotActionPull)
+	case models.PermRepoPush:
+		return s.hasAction(projectIDOrName, models.RobotActionPush)
+	}
+	return false
+}
+
+// CanAccessRepository returns false if the repository is in the project the
+// robot belongs to but not in the repository list of the robot
+func (s *SecurityContext) CanAccessRepository(repository string) bool {
+	if !s.IsAuthenticated() {
+		return false
+	}
+
+	projectName, repo := utils.ParseRepository(repository)
+	if !s.belongsTo(projectName) {
+		return true
+	}
+	return s.robot.CanAccessRepository(repo)
+}
+
+func (s *SecurityContext) hasAction(projectIDOrName interface{}, action string) bool {
+	if !s.IsAuthenticated() {
+		return false
+	}
+	return s.belongsTo(projectIDOrName) && s.robot.HasAction(action)
+}
+
+func (s *SecurityContext) belongsTo(projectIDOrName interface{}) bool {
+	project, err := s.pm.Get(projectIDOrName)
+	if err != nil {
+		log.Errorf("failed to get project %v: %v", projectIDOrName, err)
+		return false
+	}
+	return project != nil && project.ProjectID == s.robot.ProjectID
+}
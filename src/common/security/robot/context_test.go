@@ -0,0 +1,124 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/ui/projectmanager"
+)
+
+var (
+	public = &models.Project{
+		ProjectID: 1,
+		Name:      "public_project",
+		Public:    1,
+	}
+
+	private = &models.Project{
+		ProjectID: 2,
+		Name:      "private_project",
+	}
+
+	other = &models.Project{
+		ProjectID: 3,
+		Name:      "other_project",
+	}
+)
+
+// fakePM only implements the methods the robot context calls
+type fakePM struct {
+	projectmanager.ProjectManager
+	projects []*models.Project
+}
+
+func (f *fakePM) IsPublic(projectIDOrName interface{}) (bool, error) {
+	project, _ := f.Get(projectIDOrName)
+	return project != nil && project.Public == 1, nil
+}
+
+func (f *fakePM) Get(projectIDOrName interface{}) (*models.Project, error) {
+	for _, project := range f.projects {
+		if project.Name == projectIDOrName || project.ProjectID == projectIDOrName {
+			return project, nil
+		}
+	}
+	return nil, nil
+}
+
+var pm = &fakePM{
+	projects: []*models.Project{public, private, other},
+}
+
+func TestIsAuthenticated(t *testing.T) {
+	ctx := NewSecurityContext(nil, pm)
+	assert.False(t, ctx.IsAuthenticated())
+	assert.Equal(t, "", ctx.GetUsername())
+
+	robot := &models.Robot{
+		Name:      "ci",
+		ProjectID: private.ProjectID,
+	}
+	ctx = NewSecurityContext(robot, pm)
+	assert.True(t, ctx.IsAuthenticated())
+	assert.Equal(t, "robot$private_project+ci", ctx.GetUsername())
+	assert.False(t, ctx.IsSysAdmin())
+
+	robot.Disabled = true
+	assert.False(t, ctx.IsAuthenticated())
+
+	robot.Disabled = false
+	robot.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	assert.False(t, ctx.IsAuthenticated())
+}
+
+func TestCan(t *testing.T) {
+	ctx := NewSecurityContext(&models.Robot{
+		Name:       "ci",
+		ProjectID:  private.ProjectID,
+		ActionList: []string{models.RobotActionPull},
+	}, pm)
+	assert.True(t, ctx.Can(models.PermRepoPull, public.Name))
+	assert.True(t, ctx.Can(models.PermRepoPull, private.Name))
+	assert.True(t, ctx.Can(models.PermProjectRead, private.Name))
+	assert.False(t, ctx.Can(models.PermRepoPull, other.Name))
+	assert.False(t, ctx.Can(models.PermRepoPush, private.Name))
+	assert.False(t, ctx.Can(models.PermRepoDelete, private.Name))
+
+	ctx = NewSecurityContext(&models.Robot{
+		Name:       "ci",
+		ProjectID:  private.ProjectID,
+		ActionList: []string{models.RobotActionPull, models.RobotActionPush},
+	}, pm)
+	assert.True(t, ctx.Can(models.PermRepoPush, private.Name))
+	assert.False(t, ctx.Can(models.PermRepoPush, public.Name))
+	assert.False(t, ctx.Can(models.PermMemberManage, private.Name))
+}
+
+func TestCanAccessRepository(t *testing.T) {
+	ctx := NewSecurityContext(&models.Robot{
+		Name:           "ci",
+		ProjectID:      private.ProjectID,
+		ActionList:     []string{models.RobotActionPull},
+		RepositoryList: []string{"app"},
+	}, pm)
+	assert.True(t, ctx.CanAccessRepository("private_project/app"))
+	assert.False(t, ctx.CanAccessRepository("private_project/db"))
+	// the permissions to the other projects are not limited by the list
+	assert.True(t, ctx.CanAccessRepository("public_project/db"))
+}
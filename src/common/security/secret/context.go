@@ -15,6 +15,7 @@
 package secret
 
 import (
+	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/secret"
 )
 
@@ -54,21 +55,15 @@ func (s *SecurityContext) IsSysAdmin() bool {
 	return false
 }
 
-// HasReadPerm returns true if the corresponding user of the secret
-// is jobservice, otherwise returns false
-func (s *SecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
+// Can returns true if the corresponding user of the secret is jobservice
+// and the permission is to read the project or pull images from it,
+// otherwise returns false
+func (s *SecurityContext) Can(permission string, projectIDOrName interface{}) bool {
 	if s.store == nil {
 		return false
 	}
+	if permission != models.PermProjectRead && permission != models.PermRepoPull {
+		return false
+	}
 	return s.store.GetUsername(s.secret) == secret.JobserviceUser
 }
-
-// HasWritePerm always returns false
-func (s *SecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
-	return false
-}
-
-// HasAllPerm always returns false
-func (s *SecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
-	return false
-}
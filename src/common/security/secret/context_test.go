@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/secret"
 )
 
@@ -76,59 +77,46 @@ func TestIsSysAdmin(t *testing.T) {
 	assert.False(t, isSysAdmin)
 }
 
-func TestHasReadPerm(t *testing.T) {
+func TestCanRead(t *testing.T) {
 	// secret store is null
 	context := NewSecurityContext("", nil)
-	hasReadPerm := context.HasReadPerm("project_name")
-	assert.False(t, hasReadPerm)
+	assert.False(t, context.Can(models.PermRepoPull, "project_name"))
 
 	//invalid secret
 	context = NewSecurityContext("invalid_secret",
 		secret.NewStore(map[string]string{
 			"jobservice_secret": secret.JobserviceUser,
 		}))
-	hasReadPerm = context.HasReadPerm("project_name")
-	assert.False(t, hasReadPerm)
+	assert.False(t, context.Can(models.PermRepoPull, "project_name"))
 
 	//valid secret, project name
 	context = NewSecurityContext("jobservice_secret",
 		secret.NewStore(map[string]string{
 			"jobservice_secret": secret.JobserviceUser,
 		}))
-	hasReadPerm = context.HasReadPerm("project_name")
-	assert.True(t, hasReadPerm)
+	assert.True(t, context.Can(models.PermRepoPull, "project_name"))
+	assert.True(t, context.Can(models.PermProjectRead, "project_name"))
 
 	//valid secret, project ID
-	hasReadPerm = context.HasReadPerm(1)
-	assert.True(t, hasReadPerm)
+	assert.True(t, context.Can(models.PermRepoPull, 1))
 }
 
-func TestHasWritePerm(t *testing.T) {
+func TestCanWrite(t *testing.T) {
 	context := NewSecurityContext("secret",
 		secret.NewStore(map[string]string{
 			"secret": "username",
 		}))
 
 	// project name
-	hasWritePerm := context.HasWritePerm("project_name")
-	assert.False(t, hasWritePerm)
+	assert.False(t, context.Can(models.PermRepoPush, "project_name"))
 
 	// project ID
-	hasWritePerm = context.HasWritePerm(1)
-	assert.False(t, hasWritePerm)
-}
+	assert.False(t, context.Can(models.PermRepoPush, 1))
 
-func TestHasAllPerm(t *testing.T) {
-	context := NewSecurityContext("secret",
+	context = NewSecurityContext("jobservice_secret",
 		secret.NewStore(map[string]string{
-			"secret": "username",
+			"jobservice_secret": secret.JobserviceUser,
 		}))
-
-	// project name
-	hasAllPerm := context.HasAllPerm("project_name")
-	assert.False(t, hasAllPerm)
-
-	// project ID
-	hasAllPerm = context.HasAllPerm(1)
-	assert.False(t, hasAllPerm)
+	assert.False(t, context.Can(models.PermRepoPush, "project_name"))
+	assert.False(t, context.Can(models.PermMemberManage, "project_name"))
 }
@@ -15,8 +15,9 @@
 package clair
 
 import (
-	"github.com/vmware/harbor/src/common/models"
 	"strings"
+
+	"github.com/vmware/harbor/src/common/models"
 )
 
 // ParseClairSev parse the severity of clair to Harbor's Severity type if the string is not recognized the value will be set to unknown.
@@ -35,3 +36,27 @@ func ParseClairSev(clairSev string) models.Severity {
 		return models.SevUnknown
 	}
 }
+
+// TransformVuln transforms the result of Clair to the list of vulnerabilities,
+// the most severe ones are listed first
+func TransformVuln(envelope *models.ClairLayerEnvelope) []*models.VulnerabilityItem {
+	items := []*models.VulnerabilityItem{}
+	if envelope == nil || envelope.Layer == nil {
+		return items
+	}
+	for _, f := range envelope.Layer.Features {
+		for _, v := range f.Vulnerabilities {
+			items = append(items, &models.VulnerabilityItem{
+				ID:          v.Name,
+				Package:     f.Name,
+				Version:     f.Version,
+				Fixed:       v.FixedBy,
+				Severity:    ParseClairSev(v.Severity),
+				Description: v.Description,
+				Link:        v.Link,
+			})
+		}
+	}
+	models.SortVulnerabilities(items)
+	return items
+}
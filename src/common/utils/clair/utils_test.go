@@ -33,3 +33,60 @@ func TestParseServerity(t *testing.T) {
 		assert.Equal(v, ParseClairSev(k))
 	}
 }
+
+func TestTransformVuln(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(0, len(TransformVuln(nil)))
+
+	envelope := &models.ClairLayerEnvelope{
+		Layer: &models.ClairLayer{
+			Features: []models.ClairFeature{
+				{
+					Name:    "openssl",
+					Version: "1.0.1t-1",
+					Vulnerabilities: []models.ClairVulnerability{
+						{
+							Name:     "CVE-2016-2178",
+							Severity: "Low",
+						},
+						{
+							Name:        "CVE-2016-2177",
+							Severity:    "High",
+							Description: "pointer arithmetic",
+							Link:        "https://security-tracker.debian.org/tracker/CVE-2016-2177",
+							FixedBy:     "1.0.1t-1+deb8u1",
+						},
+					},
+				},
+				{
+					Name:    "bash",
+					Version: "4.3-11",
+				},
+				{
+					Name:    "glibc",
+					Version: "2.19-18",
+					Vulnerabilities: []models.ClairVulnerability{
+						{
+							Name:     "CVE-2015-8776",
+							Severity: "Low",
+						},
+					},
+				},
+			},
+		},
+	}
+	items := TransformVuln(envelope)
+	assert.Equal(3, len(items))
+	assert.Equal(&models.VulnerabilityItem{
+		ID:          "CVE-2016-2177",
+		Package:     "openssl",
+		Version:     "1.0.1t-1",
+		Fixed:       "1.0.1t-1+deb8u1",
+		Severity:    models.SevHigh,
+		Description: "pointer arithmetic",
+		Link:        "https://security-tracker.debian.org/tracker/CVE-2016-2177",
+	}, items[0])
+	assert.Equal("CVE-2015-8776", items[1].ID)
+	assert.Equal("glibc", items[1].Package)
+	assert.Equal("CVE-2016-2178", items[2].ID)
+}
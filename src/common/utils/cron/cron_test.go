@@ -0,0 +1,85 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"0 2 * * *",
+		"*/15 0-6,18-23 * * 1-5",
+		"5/10 * 1,15 * 0",
+		"0 0 * * 7",
+		"@daily",
+		"@Hourly",
+	}
+	for _, spec := range valid {
+		_, err := Parse(spec)
+		assert.Nil(t, err, spec)
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+	}
+	for _, spec := range invalid {
+		_, err := Parse(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2017, 7, 31, 23, 58, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2017, 7, 31, 23, 59, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2017, 8, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// 2017-08-06 is a Sunday
+		{"0 12 * * 0", time.Date(2017, 8, 6, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2017, 8, 6, 12, 0, 0, 0, time.UTC)},
+		// either the day of month or the day of week matches
+		{"0 0 15 * 0", time.Date(2017, 8, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, c := range cases {
+		s, err := Parse(c.spec)
+		require.Nil(t, err, c.spec)
+		assert.Equal(t, c.next, s.Next(from), c.spec)
+	}
+}
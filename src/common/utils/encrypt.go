@@ -19,6 +19,8 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -33,6 +35,42 @@ func Encrypt(content string, salt string) string {
 	return fmt.Sprintf("%x", pbkdf2.Key([]byte(content), []byte(salt), 4096, 16, sha1.New))
 }
 
+const (
+	// PasswordVersionSHA1 is the legacy version of password hash: PBKDF2-SHA1
+	// with 4096 iterations and 16 bytes key
+	PasswordVersionSHA1 = "sha1"
+	// PasswordVersionSHA256 is the version of password hash: PBKDF2-SHA256
+	// with 100000 iterations and 32 bytes key
+	PasswordVersionSHA256 = "sha256"
+	// CurrentPasswordVersion is the version with which the new passwords are
+	// hashed, the passwords hashed with other versions are upgraded to it
+	// when the users log in
+	CurrentPasswordVersion = PasswordVersionSHA256
+)
+
+// EncryptPassword hashes the password with salt by the algorithm of the
+// version specified, the legacy version is used if the version is empty
+func EncryptPassword(password, salt, version string) (string, error) {
+	switch version {
+	case "", PasswordVersionSHA1:
+		return Encrypt(password, salt), nil
+	case PasswordVersionSHA256:
+		return fmt.Sprintf("%x", pbkdf2.Key([]byte(password), []byte(salt), 100000, 32, sha256.New)), nil
+	default:
+		return "", fmt.Errorf("unsupported password version: %s", version)
+	}
+}
+
+// MatchPassword checks whether the password matches the hash generated by
+// the version specified
+func MatchPassword(password, salt, version, hash string) bool {
+	h, err := EncryptPassword(password, salt, version)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1
+}
+
 const (
 	// EncryptHeaderV1 ...
 	EncryptHeaderV1 = "<enc-v1>"
@@ -57,6 +57,11 @@ func GetSystemLdapConf() (models.LdapConf, error) {
 	ldapConfs.LdapUID = ldap.UID
 	ldapConfs.LdapScope = ldap.Scope
 	ldapConfs.LdapConnectionTimeout = ldap.Timeout
+	ldapConfs.LdapGroupBaseDN = ldap.GroupBaseDN
+	ldapConfs.LdapGroupFilter = ldap.GroupFilter
+	ldapConfs.LdapGroupNameAttribute = ldap.GroupNameAttr
+	ldapConfs.LdapGroupScope = ldap.GroupScope
+	ldapConfs.LdapGroupMembershipAttribute = ldap.GroupMemberAttr
 
 	//	ldapConfs = config.LDAP().URL
 	//	ldapConfs.LdapSearchDn = config.LDAP().SearchDn
@@ -95,20 +100,25 @@ func ValidateLdapConf(ldapConfs models.LdapConf) (models.LdapConf, error) {
 		return ldapConfs, err
 	}
 
-	// Compatible with legacy codes
-	// in previous harbor.cfg:
-	// the scope to search for users, 1-LDAP_SCOPE_BASE, 2-LDAP_SCOPE_ONELEVEL, 3-LDAP_SCOPE_SUBTREE
-	switch ldapConfs.LdapScope {
-	case 1:
-		ldapConfs.LdapScope = goldap.ScopeBaseObject
-	case 2:
-		ldapConfs.LdapScope = goldap.ScopeSingleLevel
-	case 3:
-		ldapConfs.LdapScope = goldap.ScopeWholeSubtree
-	default:
+	ldapConfs.LdapScope, err = convertScope(ldapConfs.LdapScope)
+	if err != nil {
 		return ldapConfs, fmt.Errorf("invalid ldap search scope")
 	}
 
+	// the settings of groups are optional, the group search scope
+	// defaults to one level
+	if ldapConfs.LdapGroupScope == 0 {
+		ldapConfs.LdapGroupScope = goldap.ScopeSingleLevel
+	} else {
+		ldapConfs.LdapGroupScope, err = convertScope(ldapConfs.LdapGroupScope)
+		if err != nil {
+			return ldapConfs, fmt.Errorf("invalid ldap group search scope")
+		}
+	}
+	if ldapConfs.LdapGroupNameAttribute == "" {
+		ldapConfs.LdapGroupNameAttribute = "cn"
+	}
+
 	//	value := reflect.ValueOf(ldapConfs)
 	//	lType := reflect.TypeOf(ldapConfs)
 	//	for i := 0; i < value.NumField(); i++ {
@@ -119,6 +129,22 @@ func ValidateLdapConf(ldapConfs models.LdapConf) (models.LdapConf, error) {
 
 }
 
+// Compatible with legacy codes
+// in previous harbor.cfg:
+// the scope to search for users, 1-LDAP_SCOPE_BASE, 2-LDAP_SCOPE_ONELEVEL, 3-LDAP_SCOPE_SUBTREE
+func convertScope(scope int) (int, error) {
+	switch scope {
+	case 1:
+		return goldap.ScopeBaseObject, nil
+	case 2:
+		return goldap.ScopeSingleLevel, nil
+	case 3:
+		return goldap.ScopeWholeSubtree, nil
+	default:
+		return 0, fmt.Errorf("invalid scope: %d", scope)
+	}
+}
+
 // MakeFilter ...
 func MakeFilter(username string, ldapFilter string, ldapUID string) string {
 
@@ -204,6 +230,11 @@ func SearchUser(ldapConfs models.LdapConf) ([]models.LdapUser, error) {
 		for _, attr := range ldapEntry.Attributes {
 			val := attr.Values[0]
 			log.Debugf("Current ldap entry attr name: %s\n", attr.Name)
+			if len(ldapConfs.LdapGroupMembershipAttribute) > 0 &&
+				strings.EqualFold(attr.Name, ldapConfs.LdapGroupMembershipAttribute) {
+				u.GroupDNList = append(u.GroupDNList, attr.Values...)
+				continue
+			}
 			switch strings.ToLower(attr.Name) {
 			case strings.ToLower(ldapConfs.LdapUID):
 				u.Username = val
@@ -313,6 +344,100 @@ func ImportUser(user models.LdapUser) (int64, error) {
 	return UserID, nil
 }
 
+// MakeGroupFilter returns the filter to search the groups whose names match
+// the groupName, all groups are matched if the groupName is empty
+func MakeGroupFilter(groupName string, groupFilter string, nameAttribute string) string {
+	if groupName == "" {
+		groupName = "*"
+	}
+	filter := "(" + nameAttribute + "=" + groupName + ")"
+	if groupFilter != "" {
+		if !strings.HasPrefix(groupFilter, "(") {
+			groupFilter = "(" + groupFilter + ")"
+		}
+		filter = "(&" + groupFilter + filter + ")"
+	}
+	return filter
+}
+
+// SearchGroup searches groups under the group base DN by the name, or the
+// group specified by the DN if groupDN is not empty
+func SearchGroup(ldapConfs models.LdapConf, groupName, groupDN string) ([]models.LdapGroup, error) {
+	baseDN := ldapConfs.LdapGroupBaseDN
+	scope := ldapConfs.LdapGroupScope
+	if groupDN != "" {
+		baseDN = groupDN
+		scope = goldap.ScopeBaseObject
+	}
+	if baseDN == "" {
+		return nil, fmt.Errorf("can not get any available LDAP_GROUP_BASE_DN")
+	}
+
+	ldapConn, err := dialLDAP(ldapConfs)
+	if err != nil {
+		return nil, err
+	}
+	defer ldapConn.Close()
+
+	if ldapConfs.LdapSearchDn != "" {
+		if err = bindLDAPSearchDN(ldapConfs, ldapConn); err != nil {
+			return nil, err
+		}
+	}
+
+	searchRequest := goldap.NewSearchRequest(
+		baseDN,
+		scope,
+		goldap.NeverDerefAliases,
+		0,     // Unlimited results.
+		0,     // Search Timeout.
+		false, // Types Only
+		MakeGroupFilter(groupName, ldapConfs.LdapGroupFilter, ldapConfs.LdapGroupNameAttribute),
+		[]string{ldapConfs.LdapGroupNameAttribute},
+		nil,
+	)
+
+	result, err := ldapConn.Search(searchRequest)
+	if err != nil {
+		// searching a DN which doesn't exist returns an error
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+			return []models.LdapGroup{}, nil
+		}
+		log.Debug("LDAP group search error", err)
+		return nil, err
+	}
+
+	groups := []models.LdapGroup{}
+	for _, entry := range result.Entries {
+		group := models.LdapGroup{
+			GroupDN: entry.DN,
+		}
+		// the attribute name returned by the server may be in different case
+		for _, attr := range entry.Attributes {
+			if strings.EqualFold(attr.Name, ldapConfs.LdapGroupNameAttribute) &&
+				len(attr.Values) > 0 {
+				group.GroupName = attr.Values[0]
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// ImportGroup imports the LDAP group into Harbor and returns its ID, the ID
+// of the existing one is returned if the group has been imported
+func ImportGroup(group models.LdapGroup) (int, error) {
+	g := &models.UserGroup{
+		GroupName:   group.GroupName,
+		LdapGroupDN: group.GroupDN,
+	}
+	if err := dao.OnboardLdapGroup(g); err != nil {
+		log.Errorf("failed to onboard LDAP group %s: %v", group.GroupDN, err)
+		return 0, fmt.Errorf("internal_error")
+	}
+	return g.ID, nil
+}
+
 // Bind establish a connection to ldap based on ldapConfs and bind the user with given parameters.
 func Bind(ldapConfs models.LdapConf, dn string, password string) error {
 	conn, err := dialLDAP(ldapConfs)
@@ -377,6 +502,9 @@ func searchLDAP(ldapConfs models.LdapConf, ldap *goldap.Conn) (*goldap.SearchRes
 	if lowerUID != "uid" && lowerUID != "cn" && lowerUID != "mail" && lowerUID != "email" {
 		attributes = append(attributes, ldapConfs.LdapUID)
 	}
+	if len(ldapConfs.LdapGroupMembershipAttribute) > 0 {
+		attributes = append(attributes, ldapConfs.LdapGroupMembershipAttribute)
+	}
 	searchRequest := goldap.NewSearchRequest(
 		ldapBaseDn,
 		ldapScope,
@@ -39,14 +39,19 @@ var adminServerLdapTestConfig = map[string]interface{}{
 	common.MySQLDatabase: "registry",
 	common.SQLiteFile:    "/tmp/registry.db",
 	//config.SelfRegistration: true,
-	common.LDAPURL:       "ldap://127.0.0.1",
-	common.LDAPSearchDN:  "cn=admin,dc=example,dc=com",
-	common.LDAPSearchPwd: "admin",
-	common.LDAPBaseDN:    "dc=example,dc=com",
-	common.LDAPUID:       "uid",
-	common.LDAPFilter:    "",
-	common.LDAPScope:     3,
-	common.LDAPTimeout:   30,
+	common.LDAPURL:                 "ldap://127.0.0.1",
+	common.LDAPSearchDN:            "cn=admin,dc=example,dc=com",
+	common.LDAPSearchPwd:           "admin",
+	common.LDAPBaseDN:              "dc=example,dc=com",
+	common.LDAPUID:                 "uid",
+	common.LDAPFilter:              "",
+	common.LDAPScope:               3,
+	common.LDAPTimeout:             30,
+	common.LDAPGroupBaseDN:         "ou=groups,dc=example,dc=com",
+	common.LDAPGroupFilter:         "objectclass=groupOfUniqueNames",
+	common.LDAPGroupNameAttribute:  "cn",
+	common.LDAPGroupScope:          2,
+	common.LDAPGroupMembershipAttr: "memberof",
 	//	config.TokenServiceURL:            "",
 	//	config.RegistryURL:                "",
 	//	config.EmailHost:                  "",
@@ -254,3 +259,52 @@ func TestSearchUser(t *testing.T) {
 		t.Errorf("unexpected ldap user search result: %s = %s", "ldapUsers[0].Username", ldapUsers[0].Username)
 	}
 }
+
+func TestMakeGroupFilter(t *testing.T) {
+	filter := MakeGroupFilter("", "", "cn")
+	if filter != "(cn=*)" {
+		t.Errorf("unexpected filter: %s != %s", filter, "(cn=*)")
+	}
+
+	filter = MakeGroupFilter("harbor_users", "objectclass=groupOfUniqueNames", "cn")
+	if filter != "(&(objectclass=groupOfUniqueNames)(cn=harbor_users))" {
+		t.Errorf("unexpected filter: %s != %s", filter,
+			"(&(objectclass=groupOfUniqueNames)(cn=harbor_users))")
+	}
+}
+
+func TestSearchGroup(t *testing.T) {
+	testLdapConfig, err := GetSystemLdapConf()
+	if err != nil {
+		t.Fatalf("failed to get system ldap config %v", err)
+	}
+	testLdapConfig.LdapURL = "ldap://localhost:389"
+	testLdapConfig, err = ValidateLdapConf(testLdapConfig)
+	if err != nil {
+		t.Fatalf("failed to validate ldap config %v", err)
+	}
+
+	groups, err := SearchGroup(testLdapConfig, "harbor_users", "")
+	if err != nil {
+		t.Fatalf("unexpected ldap group search fail: %v", err)
+	}
+	if len(groups) != 1 || groups[0].GroupDN != "cn=harbor_users,ou=groups,dc=example,dc=com" {
+		t.Errorf("unexpected ldap group search result: %+v", groups)
+	}
+
+	groups, err = SearchGroup(testLdapConfig, "", "cn=harbor_users,ou=groups,dc=example,dc=com")
+	if err != nil {
+		t.Fatalf("unexpected ldap group search fail: %v", err)
+	}
+	if len(groups) != 1 || groups[0].GroupName != "harbor_users" {
+		t.Errorf("unexpected ldap group search result: %+v", groups)
+	}
+
+	groups, err = SearchGroup(testLdapConfig, "", "cn=non_exist,ou=groups,dc=example,dc=com")
+	if err != nil {
+		t.Fatalf("unexpected ldap group search fail: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("unexpected ldap group search result: %+v", groups)
+	}
+}
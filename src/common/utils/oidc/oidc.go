@@ -0,0 +1,332 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oidc implements the parts of OpenID Connect which are needed by
+// the authorization code flow: discovery, token exchange and the
+// verification of ID tokens.
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/registry"
+)
+
+// Token is the response of the token endpoint
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	IDToken      string `json:"id_token"`
+}
+
+// Claims holds the claims in the ID token which are used by Harbor
+type Claims struct {
+	Subject  string
+	Issuer   string
+	Username string
+	Name     string
+	Email    string
+	Groups   []string
+	Nonce    string
+}
+
+// SubIss returns the identity of the user in the provider
+func (c *Claims) SubIss() string {
+	return c.Subject + c.Issuer
+}
+
+// provider holds the metadata of the provider got by discovery and
+// the keys used to sign the ID tokens
+type provider struct {
+	Issuer           string `json:"issuer"`
+	AuthEndpoint     string `json:"authorization_endpoint"`
+	TokenEndpoint    string `json:"token_endpoint"`
+	JWKSURI          string `json:"jwks_uri"`
+	UserInfoEndpoint string `json:"userinfo_endpoint"`
+
+	keys map[string]*rsa.PublicKey // key: kid
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+var (
+	// key: endpoint of the provider
+	providers = map[string]*provider{}
+	lock      = &sync.Mutex{}
+)
+
+func newClient(setting *models.OIDCSetting) *http.Client {
+	return &http.Client{
+		Transport: registry.GetHTTPTransport(!setting.VerifyCert),
+		Timeout:   30 * time.Second,
+	}
+}
+
+func getJSON(client *http.Client, u string, v interface{}) error {
+	resp, err := client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, u, string(data))
+	}
+	return json.Unmarshal(data, v)
+}
+
+// getProvider returns the provider got by discovery, the result is cached
+func getProvider(setting *models.OIDCSetting) (*provider, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if p, ok := providers[setting.Endpoint]; ok {
+		return p, nil
+	}
+
+	p := &provider{}
+	if err := getJSON(newClient(setting),
+		setting.Endpoint+"/.well-known/openid-configuration", p); err != nil {
+		return nil, fmt.Errorf("failed to discover the OIDC provider %s: %v", setting.Endpoint, err)
+	}
+	if strings.TrimRight(p.Issuer, "/") != setting.Endpoint {
+		return nil, fmt.Errorf("the issuer %s does not match the endpoint %s", p.Issuer, setting.Endpoint)
+	}
+	providers[setting.Endpoint] = p
+	return p, nil
+}
+
+// getKey returns the key specified by kid, the keys are refreshed if the
+// kid is not found as the provider may rotate them
+func getKey(setting *models.OIDCSetting, p *provider, kid string) (*rsa.PublicKey, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if key := findKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+
+	set := &struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	if err := getJSON(newClient(setting), p.JWKSURI, set); err != nil {
+		return nil, fmt.Errorf("failed to get the keys of the OIDC provider: %v", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (len(k.Use) > 0 && k.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAKey(k)
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+
+	if key := findKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("key %s not found", kid)
+}
+
+// findKey returns the key specified by kid, if the kid is empty the only
+// key is returned
+func findKey(keys map[string]*rsa.PublicKey, kid string) *rsa.PublicKey {
+	if len(kid) == 0 && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+func parseRSAKey(k *jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus of key %s: %v", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent of key %s: %v", k.Kid, err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// AuthCodeURL returns the URL of the provider to which the users are
+// redirected to login
+func AuthCodeURL(setting *models.OIDCSetting, state, nonce string) (string, error) {
+	p, err := getProvider(setting)
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("response_type", "code")
+	values.Set("client_id", setting.ClientID)
+	values.Set("redirect_uri", setting.RedirectURL)
+	values.Set("scope", strings.Join(setting.Scope, " "))
+	values.Set("state", state)
+	values.Set("nonce", nonce)
+
+	sep := "?"
+	if strings.Contains(p.AuthEndpoint, "?") {
+		sep = "&"
+	}
+	return p.AuthEndpoint + sep + values.Encode(), nil
+}
+
+// ExchangeToken exchanges the authorization code for tokens
+func ExchangeToken(setting *models.OIDCSetting, code string) (*Token, error) {
+	p, err := getProvider(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("redirect_uri", setting.RedirectURL)
+	req, err := http.NewRequest(http.MethodPost, p.TokenEndpoint,
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(setting.ClientID), url.QueryEscape(setting.ClientSecret))
+
+	resp, err := newClient(setting).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange token, status code %d: %s", resp.StatusCode, string(data))
+	}
+
+	token := &Token{}
+	if err = json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	if len(token.IDToken) == 0 {
+		return nil, fmt.Errorf("no ID token in the response of token endpoint")
+	}
+	return token, nil
+}
+
+// VerifyIDToken verifies the signature, issuer, audience and expiration of
+// the ID token and returns the claims in it
+func VerifyIDToken(setting *models.OIDCSetting, rawIDToken string) (*Claims, error) {
+	p, err := getProvider(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &jwt.Parser{
+		ValidMethods: []string{jwt.SigningMethodRS256.Alg()},
+	}
+	token, err := parser.Parse(rawIDToken, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return getKey(setting, p, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %v", err)
+	}
+
+	m := token.Claims.(jwt.MapClaims)
+	if !m.VerifyIssuer(p.Issuer, true) {
+		return nil, fmt.Errorf("invalid issuer of ID token: %v", m["iss"])
+	}
+	if !verifyAudience(m["aud"], setting.ClientID) {
+		return nil, fmt.Errorf("invalid audience of ID token: %v", m["aud"])
+	}
+	if !m.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("the ID token is expired")
+	}
+
+	claims := &Claims{
+		Issuer: p.Issuer,
+	}
+	claims.Subject, _ = m["sub"].(string)
+	claims.Username, _ = m["preferred_username"].(string)
+	claims.Name, _ = m["name"].(string)
+	claims.Email, _ = m["email"].(string)
+	claims.Nonce, _ = m["nonce"].(string)
+	if len(claims.Subject) == 0 {
+		return nil, fmt.Errorf("no subject in ID token")
+	}
+	if len(setting.GroupsClaim) > 0 {
+		claims.Groups = stringList(m[setting.GroupsClaim])
+	}
+	return claims, nil
+}
+
+// the audience is either a string or an array of strings
+func verifyAudience(aud interface{}, clientID string) bool {
+	for _, a := range stringList(aud) {
+		if a == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func stringList(v interface{}) []string {
+	list := []string{}
+	switch value := v.(type) {
+	case string:
+		list = append(list, value)
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/test"
+)
+
+func TestAuthCodeFlow(t *testing.T) {
+	provider, err := test.NewOIDCProvider("harbor", "secret")
+	require.Nil(t, err)
+	defer provider.Close()
+	provider.Claims["sub"] = "user01-id"
+	provider.Claims["preferred_username"] = "user01"
+	provider.Claims["email"] = "user01@example.com"
+	provider.Claims["groups"] = []string{"dev", "ops"}
+
+	setting := &models.OIDCSetting{
+		Endpoint:     provider.URL,
+		ClientID:     "harbor",
+		ClientSecret: "secret",
+		Scope:        []string{"openid", "profile"},
+		GroupsClaim:  "groups",
+		RedirectURL:  "https://harbor.example.com/c/oidc/callback",
+	}
+
+	u, err := AuthCodeURL(setting, "state01", "nonce01")
+	require.Nil(t, err)
+	authURL, err := url.Parse(u)
+	require.Nil(t, err)
+	assert.Equal(t, provider.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, "openid profile", authURL.Query().Get("scope"))
+
+	// the provider redirects back with the code and state
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(u)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.Nil(t, err)
+	assert.Equal(t, "state01", callback.Query().Get("state"))
+
+	token, err := ExchangeToken(setting, callback.Query().Get("code"))
+	require.Nil(t, err)
+
+	claims, err := VerifyIDToken(setting, token.IDToken)
+	require.Nil(t, err)
+	assert.Equal(t, "user01-id", claims.Subject)
+	assert.Equal(t, "user01", claims.Username)
+	assert.Equal(t, "user01@example.com", claims.Email)
+	assert.Equal(t, "nonce01", claims.Nonce)
+	assert.Equal(t, []string{"dev", "ops"}, claims.Groups)
+	assert.Equal(t, "user01-id"+provider.URL, claims.SubIss())
+
+	// the code can only be used once
+	_, err = ExchangeToken(setting, callback.Query().Get("code"))
+	assert.NotNil(t, err)
+
+	// the ID token is issued to another client
+	_, err = VerifyIDToken(&models.OIDCSetting{
+		Endpoint: provider.URL,
+		ClientID: "other",
+	}, token.IDToken)
+	assert.NotNil(t, err)
+
+	// the ID token is tampered
+	_, err = VerifyIDToken(setting, token.IDToken+"a")
+	assert.NotNil(t, err)
+}
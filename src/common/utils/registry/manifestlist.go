@@ -0,0 +1,113 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/docker/distribution"
+	digestutil "github.com/docker/distribution/digest"
+	distmanifest "github.com/docker/distribution/manifest"
+)
+
+// MediaTypeManifestList is the media type of the manifest list which
+// references the manifests of an image built for different platforms
+const MediaTypeManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+func init() {
+	listFunc := func(b []byte) (distribution.Manifest, distribution.Descriptor, error) {
+		m := new(DeserializedManifestList)
+		if err := m.UnmarshalJSON(b); err != nil {
+			return nil, distribution.Descriptor{}, err
+		}
+
+		dgst := digestutil.FromBytes(b)
+		return m, distribution.Descriptor{Digest: dgst, Size: int64(len(b)), MediaType: MediaTypeManifestList}, nil
+	}
+	if err := distribution.RegisterManifestSchema(MediaTypeManifestList, listFunc); err != nil {
+		panic(fmt.Sprintf("unable to register manifest list: %v", err))
+	}
+}
+
+// Platform describes the platform which the image in the manifest runs on
+type Platform struct {
+	Architecture string   `json:"architecture"`
+	OS           string   `json:"os"`
+	OSVersion    string   `json:"os.version,omitempty"`
+	OSFeatures   []string `json:"os.features,omitempty"`
+	Variant      string   `json:"variant,omitempty"`
+	Features     []string `json:"features,omitempty"`
+}
+
+// ManifestDescriptor references a platform specific manifest
+type ManifestDescriptor struct {
+	distribution.Descriptor
+
+	Platform Platform `json:"platform"`
+}
+
+// ManifestList references manifests for various platforms
+type ManifestList struct {
+	distmanifest.Versioned
+
+	Manifests []ManifestDescriptor `json:"manifests"`
+}
+
+// References returns the distribution descriptors of the referenced manifests
+func (m ManifestList) References() []distribution.Descriptor {
+	dependencies := make([]distribution.Descriptor, len(m.Manifests))
+	for i := range m.Manifests {
+		dependencies[i] = m.Manifests[i].Descriptor
+	}
+	return dependencies
+}
+
+// DeserializedManifestList wraps ManifestList with a copy of the original JSON
+type DeserializedManifestList struct {
+	ManifestList
+
+	// canonical is the canonical byte representation of the manifest list
+	canonical []byte
+}
+
+// UnmarshalJSON populates a new ManifestList struct from JSON data
+func (m *DeserializedManifestList) UnmarshalJSON(b []byte) error {
+	m.canonical = make([]byte, len(b), len(b))
+	copy(m.canonical, b)
+
+	var list ManifestList
+	if err := json.Unmarshal(m.canonical, &list); err != nil {
+		return err
+	}
+
+	m.ManifestList = list
+	return nil
+}
+
+// MarshalJSON returns the contents of canonical
+func (m *DeserializedManifestList) MarshalJSON() ([]byte, error) {
+	if len(m.canonical) > 0 {
+		return m.canonical, nil
+	}
+
+	return nil, errors.New("JSON representation not initialized in DeserializedManifestList")
+}
+
+// Payload returns the raw content of the manifest list
+func (m DeserializedManifestList) Payload() (string, []byte, error) {
+	return m.MediaType, m.canonical, nil
+}
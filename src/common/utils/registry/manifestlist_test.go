@@ -0,0 +1,83 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+)
+
+func TestUnMarshalManifestList(t *testing.T) {
+	b := []byte(`{
+   "schemaVersion": 2,
+   "mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+   "manifests": [
+      {
+         "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+         "size": 527,
+         "digest": "sha256:e692418e4cbaf90ca69d05a66403747baa33ee08806650b51fab815ad7fc331f",
+         "platform": {
+            "architecture": "amd64",
+            "os": "linux"
+         }
+      },
+      {
+         "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+         "size": 527,
+         "digest": "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+         "platform": {
+            "architecture": "arm64",
+            "os": "linux",
+            "variant": "v8"
+         }
+      }
+   ]
+}`)
+
+	manifest, descriptor, err := UnMarshal(MediaTypeManifestList, b)
+	if err != nil {
+		t.Fatalf("failed to parse manifest list: %v", err)
+	}
+
+	if descriptor.MediaType != MediaTypeManifestList {
+		t.Errorf("unexpected media type: %s != %s", descriptor.MediaType, MediaTypeManifestList)
+	}
+
+	list, ok := manifest.(*DeserializedManifestList)
+	if !ok {
+		t.Fatalf("unexpected type of manifest: %T", manifest)
+	}
+
+	refs := list.References()
+	if len(refs) != 2 {
+		t.Fatalf("unexpected length of reference: %d != %d", len(refs), 2)
+	}
+
+	digest := "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"
+	if refs[1].Digest.String() != digest {
+		t.Errorf("unexpected digest: %s != %s", refs[1].Digest.String(), digest)
+	}
+
+	if list.Manifests[1].Platform.Architecture != "arm64" || list.Manifests[1].Platform.Variant != "v8" {
+		t.Errorf("unexpected platform: %+v", list.Manifests[1].Platform)
+	}
+
+	mediaType, payload, err := list.Payload()
+	if err != nil {
+		t.Fatalf("failed to get payload: %v", err)
+	}
+	if mediaType != MediaTypeManifestList || string(payload) != string(b) {
+		t.Errorf("unexpected payload: %s %s", mediaType, string(payload))
+	}
+}
@@ -127,6 +127,9 @@ func (r *Repository) ManifestExist(reference string) (digest string, exist bool,
 
 	req.Header.Add(http.CanonicalHeaderKey("Accept"), schema1.MediaTypeManifest)
 	req.Header.Add(http.CanonicalHeaderKey("Accept"), schema2.MediaTypeManifest)
+	// without it the registry returns the digest of a platform specific
+	// manifest for the tag which references a manifest list
+	req.Header.Add(http.CanonicalHeaderKey("Accept"), MediaTypeManifestList)
 
 	resp, err := r.client.Do(req)
 	if err != nil {
@@ -347,6 +350,64 @@ func (r *Repository) PullBlob(digest string) (size int64, data io.ReadCloser, er
 	return
 }
 
+// PullBlobFrom pulls the content of the blob starting at offset, it is used to
+// resume an interrupted download. The leading bytes are skipped if the registry
+// ignores the Range header. The size returned is the size of the whole blob.
+// Client must close data if it is not nil
+func (r *Repository) PullBlobFrom(digest string, offset int64) (size int64, data io.ReadCloser, err error) {
+	if offset <= 0 {
+		return r.PullBlob(digest)
+	}
+
+	req, err := http.NewRequest("GET", buildBlobURL(r.Endpoint.String(), r.Name, digest), nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Range"), fmt.Sprintf("bytes=%d-", offset))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		err = parseError(err)
+		return
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		size, err = parseContentRangeSize(resp.Header.Get(http.CanonicalHeaderKey("Content-Range")))
+		if err != nil {
+			resp.Body.Close()
+			return
+		}
+		data = resp.Body
+		return
+	case http.StatusOK:
+		contentLength := resp.Header.Get(http.CanonicalHeaderKey("Content-Length"))
+		size, err = strconv.ParseInt(contentLength, 10, 64)
+		if err == nil {
+			_, err = io.CopyN(ioutil.Discard, resp.Body, offset)
+		}
+		if err != nil {
+			resp.Body.Close()
+			return
+		}
+		data = resp.Body
+		return
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	err = &registry_error.Error{
+		StatusCode: resp.StatusCode,
+		Detail:     string(b),
+	}
+
+	return
+}
+
 func (r *Repository) initiateBlobUpload(name string) (location, uploadUUID string, err error) {
 	req, err := http.NewRequest("POST", buildInitiateBlobUploadURL(r.Endpoint.String(), r.Name), nil)
 	req.Header.Set(http.CanonicalHeaderKey("Content-Length"), "0")
@@ -415,6 +476,169 @@ func (r *Repository) PushBlob(digest string, size int64, data io.Reader) error {
 	return r.monolithicBlobUpload(location, digest, size, data)
 }
 
+// MountBlob mounts the blob from another repository in the same registry, it
+// returns false if the registry can not mount it, e.g. the blob does not
+// exist in the repository "from" or the registry does not support mounting
+func (r *Repository) MountBlob(digest, from string) (bool, error) {
+	req, err := http.NewRequest("POST", buildMountBlobURL(r.Endpoint.String(), r.Name, digest, from), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Content-Length"), "0")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, parseError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusCreated {
+		return true, nil
+	}
+
+	// the registry falls back to initiating an upload session when
+	// the blob can not be mounted, cancel it as it will not be used
+	if resp.StatusCode == http.StatusAccepted {
+		if location := resp.Header.Get(http.CanonicalHeaderKey("Location")); len(location) != 0 {
+			r.cancelBlobUpload(location)
+		}
+		return false, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	return false, &registry_error.Error{
+		StatusCode: resp.StatusCode,
+		Detail:     string(b),
+	}
+}
+
+// cancelBlobUpload cancels the upload session, the errors are ignored as the
+// registry purges the stale sessions anyway
+func (r *Repository) cancelBlobUpload(location string) {
+	location, err := r.resolveLocation(location)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest("DELETE", location, nil)
+	if err != nil {
+		return
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// InitiateBlobUpload starts an upload session and returns its location, the
+// blob can then be uploaded in chunks with PushBlobChunk
+func (r *Repository) InitiateBlobUpload() (string, error) {
+	location, _, err := r.initiateBlobUpload(r.Name)
+	if err != nil {
+		return "", err
+	}
+	return r.resolveLocation(location)
+}
+
+// PushBlobChunk uploads the chunk with the length starting at offset to the
+// upload session, the location for the next request and the offset of the
+// next chunk are returned
+func (r *Repository) PushBlobChunk(location string, offset, length int64, chunk io.Reader) (string, int64, error) {
+	req, err := http.NewRequest("PATCH", location, chunk)
+	if err != nil {
+		return "", 0, err
+	}
+	req.ContentLength = length
+	req.Header.Set(http.CanonicalHeaderKey("Content-Type"), "application/octet-stream")
+	req.Header.Set(http.CanonicalHeaderKey("Content-Range"), fmt.Sprintf("%d-%d", offset, offset+length-1))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", 0, parseError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		next, err := r.resolveLocation(resp.Header.Get(http.CanonicalHeaderKey("Location")))
+		if err != nil {
+			return "", 0, err
+		}
+		return next, offset + length, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return "", 0, &registry_error.Error{
+		StatusCode: resp.StatusCode,
+		Detail:     string(b),
+	}
+}
+
+// BlobUploadStatus returns the location of the upload session and the offset
+// the next chunk must start at, which is the length of the data the registry
+// has received. It is used to resume an interrupted upload
+func (r *Repository) BlobUploadStatus(location string) (string, int64, error) {
+	req, err := http.NewRequest("GET", location, nil)
+	if err != nil {
+		return "", 0, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", 0, parseError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		offset, err := parseUploadRange(resp.Header.Get(http.CanonicalHeaderKey("Range")))
+		if err != nil {
+			return "", 0, err
+		}
+		next := location
+		if l := resp.Header.Get(http.CanonicalHeaderKey("Location")); len(l) != 0 {
+			if next, err = r.resolveLocation(l); err != nil {
+				return "", 0, err
+			}
+		}
+		return next, offset, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return "", 0, &registry_error.Error{
+		StatusCode: resp.StatusCode,
+		Detail:     string(b),
+	}
+}
+
+// CompleteBlobUpload ends the upload session after all the chunks are uploaded
+func (r *Repository) CompleteBlobUpload(location, digest string) error {
+	return r.monolithicBlobUpload(location, digest, 0, nil)
+}
+
+// resolveLocation returns the absolute URL of the location returned by
+// registry, which may be relative to the endpoint
+func (r *Repository) resolveLocation(location string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return r.Endpoint.ResolveReference(u).String(), nil
+}
+
 // DeleteBlob ...
 func (r *Repository) DeleteBlob(digest string) error {
 	req, err := http.NewRequest("DELETE", buildBlobURL(r.Endpoint.String(), r.Name, digest), nil)
@@ -444,6 +668,38 @@ func (r *Repository) DeleteBlob(digest string) error {
 	}
 }
 
+// parseUploadRange parses the Range header of the upload status, e.g. "0-1023",
+// and returns the length of the received data. The registry returns "0-0"
+// when it has received nothing
+func parseUploadRange(rng string) (int64, error) {
+	parts := strings.SplitN(rng, "-", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid range: %s", rng)
+	}
+	end, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid range: %s", rng)
+	}
+	if end == 0 {
+		return 0, nil
+	}
+	return end + 1, nil
+}
+
+// parseContentRangeSize returns the complete length in the Content-Range
+// header, e.g. "bytes 100-199/200"
+func parseContentRangeSize(contentRange string) (int64, error) {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid content range: %s", contentRange)
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid content range: %s", contentRange)
+	}
+	return size, nil
+}
+
 func buildPingURL(endpoint string) string {
 	return fmt.Sprintf("%s/v2/", endpoint)
 }
@@ -464,6 +720,11 @@ func buildInitiateBlobUploadURL(endpoint, repoName string) string {
 	return fmt.Sprintf("%s/v2/%s/blobs/uploads/", endpoint, repoName)
 }
 
+func buildMountBlobURL(endpoint, repoName, digest, from string) string {
+	return fmt.Sprintf("%s/v2/%s/blobs/uploads/?mount=%s&from=%s", endpoint, repoName,
+		url.QueryEscape(digest), url.QueryEscape(from))
+}
+
 func buildMonolithicBlobUploadURL(location, digest string) string {
 	query := ""
 	if strings.ContainsRune(location, '?') {
@@ -183,6 +183,243 @@ func TestPushBlob(t *testing.T) {
 	}
 }
 
+func TestPullBlobFrom(t *testing.T) {
+	// the first server supports range requests, the second one ignores them
+	handlers := []func(http.ResponseWriter, *http.Request){
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Range") != "bytes=2-" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 2-%d/%d", len(blob)-1, len(blob)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(blob[2:])
+		},
+		test.Handler(&test.Response{
+			Headers: map[string]string{
+				"Content-Length": strconv.Itoa(len(blob)),
+			},
+			Body: blob,
+		}),
+	}
+
+	for _, handler := range handlers {
+		server := test.NewServer(
+			&test.RequestHandlerMapping{
+				Method:  "GET",
+				Pattern: fmt.Sprintf("/v2/%s/blobs/%s", repository, digest),
+				Handler: handler,
+			})
+
+		client, err := newRepository(server.URL)
+		if err != nil {
+			t.Fatalf("failed to create client for repository: %v", err)
+		}
+
+		size, reader, err := client.PullBlobFrom(digest, 2)
+		if err != nil {
+			t.Fatalf("failed to pull blob: %v", err)
+		}
+
+		if size != int64(len(blob)) {
+			t.Errorf("unexpected size of blob: %d != %d", size, len(blob))
+		}
+
+		b, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("failed to read from reader: %v", err)
+		}
+
+		if bytes.Compare(b, blob[2:]) != 0 {
+			t.Errorf("unexpected blob: %s != %s", string(b), string(blob[2:]))
+		}
+		server.Close()
+	}
+}
+
+func TestPushBlobInChunks(t *testing.T) {
+	uploaded := []byte{}
+	completed := false
+	location := fmt.Sprintf("/v2/%s/blobs/uploads/%s", repository, uuid)
+	initUploadHandler := test.Handler(&test.Response{
+		StatusCode: http.StatusAccepted,
+		Headers: map[string]string{
+			"Location":           location,
+			"Docker-Upload-UUID": uuid,
+		},
+	})
+
+	uploadHandler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PATCH":
+			expected := fmt.Sprintf("%d-", len(uploaded))
+			if !strings.HasPrefix(r.Header.Get("Content-Range"), expected) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			uploaded = append(uploaded, b...)
+			w.Header().Set("Location", location)
+			w.WriteHeader(http.StatusAccepted)
+		case "GET":
+			w.Header().Set("Range", fmt.Sprintf("0-%d", len(uploaded)-1))
+			w.WriteHeader(http.StatusNoContent)
+		case "PUT":
+			if r.URL.Query().Get("digest") != digest {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			completed = true
+			w.WriteHeader(http.StatusCreated)
+		}
+	}
+
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "POST",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/", repository),
+			Handler: initUploadHandler,
+		},
+		&test.RequestHandlerMapping{
+			Method:  "PATCH",
+			Pattern: location,
+			Handler: uploadHandler,
+		},
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: location,
+			Handler: uploadHandler,
+		},
+		&test.RequestHandlerMapping{
+			Method:  "PUT",
+			Pattern: location,
+			Handler: uploadHandler,
+		})
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client for repository: %v", err)
+	}
+
+	loc, err := client.InitiateBlobUpload()
+	if err != nil {
+		t.Fatalf("failed to initiate blob upload: %v", err)
+	}
+	if loc != server.URL+location {
+		t.Errorf("unexpected location: %s != %s", loc, server.URL+location)
+	}
+
+	loc, offset, err := client.PushBlobChunk(loc, 0, 3, bytes.NewReader(blob[:3]))
+	if err != nil {
+		t.Fatalf("failed to push chunk: %v", err)
+	}
+	if offset != 3 {
+		t.Errorf("unexpected offset: %d != %d", offset, 3)
+	}
+
+	// the chunk does not start at the offset the registry expects
+	if _, _, err = client.PushBlobChunk(loc, 1, 3, bytes.NewReader(blob[1:])); err == nil {
+		t.Errorf("pushing a chunk with wrong offset should fail")
+	}
+
+	loc, offset, err = client.BlobUploadStatus(loc)
+	if err != nil {
+		t.Fatalf("failed to get blob upload status: %v", err)
+	}
+	if offset != 3 {
+		t.Errorf("unexpected offset: %d != %d", offset, 3)
+	}
+
+	if _, _, err = client.PushBlobChunk(loc, offset, int64(len(blob))-offset,
+		bytes.NewReader(blob[offset:])); err != nil {
+		t.Fatalf("failed to push chunk: %v", err)
+	}
+
+	if err = client.CompleteBlobUpload(loc, digest); err != nil {
+		t.Fatalf("failed to complete blob upload: %v", err)
+	}
+
+	if !completed || bytes.Compare(uploaded, blob) != 0 {
+		t.Errorf("unexpected blob: %s != %s", string(uploaded), string(blob))
+	}
+}
+
+func TestMountBlob(t *testing.T) {
+	canceled := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("mount") == digest && q.Get("from") == "library/busybox" {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", repository, uuid))
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "POST",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/", repository),
+			Handler: handler,
+		},
+		&test.RequestHandlerMapping{
+			Method:  "DELETE",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/%s", repository, uuid),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				canceled = true
+				w.WriteHeader(http.StatusNoContent)
+			},
+		})
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client for repository: %v", err)
+	}
+
+	mounted, err := client.MountBlob(digest, "library/busybox")
+	if err != nil {
+		t.Fatalf("failed to mount blob: %v", err)
+	}
+	if !mounted {
+		t.Errorf("blob should be mounted")
+	}
+
+	mounted, err = client.MountBlob(digest, "library/alpine")
+	if err != nil {
+		t.Fatalf("failed to mount blob: %v", err)
+	}
+	if mounted {
+		t.Errorf("blob should not be mounted")
+	}
+	if !canceled {
+		t.Errorf("the upload session should be canceled")
+	}
+}
+
+func TestParseUploadRange(t *testing.T) {
+	cases := map[string]int64{
+		"0-0":    0,
+		"0-1023": 1024,
+	}
+	for rng, expected := range cases {
+		offset, err := parseUploadRange(rng)
+		if err != nil {
+			t.Errorf("failed to parse range %s: %v", rng, err)
+			continue
+		}
+		if offset != expected {
+			t.Errorf("unexpected offset of range %s: %d != %d", rng, offset, expected)
+		}
+	}
+
+	if _, err := parseUploadRange("invalid"); err == nil {
+		t.Errorf("an error expected when parsing invalid range")
+	}
+}
+
 func TestDeleteBlob(t *testing.T) {
 	handler := test.Handler(&test.Response{
 		StatusCode: http.StatusAccepted,
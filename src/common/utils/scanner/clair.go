@@ -0,0 +1,71 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/clair"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// clairScanner scans the images with the v1 API of Clair v2, the layers are
+// posted one by one and the report is the result of the top layer
+type clairScanner struct {
+	client *clair.Client
+}
+
+func newClairScanner(endpoint string, logger *log.Logger) *clairScanner {
+	return &clairScanner{
+		client: clair.NewClient(endpoint, logger),
+	}
+}
+
+func (c *clairScanner) Submit(img *Image) (string, error) {
+	if len(img.Layers) == 0 {
+		return "", errors.New("no layer to scan")
+	}
+	parent := ""
+	for _, l := range img.Layers {
+		layer := models.ClairLayer{
+			Name:       fmt.Sprintf("%s-%s", img.ID, l.Digest),
+			Headers:    img.Headers,
+			Format:     "Docker",
+			Path:       l.URL,
+			ParentName: parent,
+		}
+		if err := c.client.ScanLayer(layer); err != nil {
+			return "", err
+		}
+		parent = layer.Name
+	}
+	return parent, nil
+}
+
+func (c *clairScanner) GetReport(key string) (*Report, error) {
+	res, err := c.client.GetResult(key)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		Vulnerabilities: clair.TransformVuln(res),
+	}
+	if res.Layer != nil {
+		report.Components = len(res.Layer.Features)
+	}
+	return report, nil
+}
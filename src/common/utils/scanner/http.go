@@ -0,0 +1,158 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// httpScanner talks to the scanners which implement the generic HTTP scanner
+// protocol:
+//
+// POST {endpoint}/api/v1/scan submits an image, the body is the Image in JSON,
+// the scanner responds 201 or 202 with {"id": "..."} which is the key of the
+// report.
+//
+// GET {endpoint}/api/v1/scan/{id}/report gets the report, the scanner responds
+// 202 if the scanning is in progress, or 200 with the report:
+// {"components": 10, "vulnerabilities": [{"id": "CVE-2016-2177", "package": "openssl",
+// "version": "1.0.1t-1", "fixed_version": "1.0.1t-1+deb8u1", "severity": "high",
+// "description": "...", "link": "..."}]}
+// The severity is one of negligible, low, medium, high and critical.
+type httpScanner struct {
+	endpoint string
+	client   *http.Client
+}
+
+type httpScanResponse struct {
+	ID string `json:"id"`
+}
+
+type httpReport struct {
+	Components      int                  `json:"components"`
+	Vulnerabilities []*httpVulnerability `json:"vulnerabilities"`
+}
+
+type httpVulnerability struct {
+	ID          string `json:"id"`
+	Package     string `json:"package"`
+	Version     string `json:"version"`
+	Fixed       string `json:"fixed_version"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+}
+
+func newHTTPScanner(endpoint string) *httpScanner {
+	return &httpScanner{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+func (h *httpScanner) Submit(img *Image) (string, error) {
+	data, err := json.Marshal(img)
+	if err != nil {
+		return "", err
+	}
+	resp, err := h.client.Post(h.endpoint+"/api/v1/scan", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("unexpected status code: %d, text: %s", resp.StatusCode, string(b))
+	}
+	res := &httpScanResponse{}
+	if err = json.Unmarshal(b, res); err != nil {
+		return "", err
+	}
+	if len(res.ID) == 0 {
+		return "", errors.New("no ID of the report returned by the scanner")
+	}
+	return res.ID, nil
+}
+
+func (h *httpScanner) GetReport(key string) (*Report, error) {
+	resp, err := h.client.Get(fmt.Sprintf("%s/api/v1/scan/%s/report", h.endpoint, url.PathEscape(key)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		return nil, ErrReportNotReady
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d, text: %s", resp.StatusCode, string(b))
+	}
+	res := &httpReport{}
+	if err = json.Unmarshal(b, res); err != nil {
+		return nil, err
+	}
+	report := &Report{
+		Components:      res.Components,
+		Vulnerabilities: []*models.VulnerabilityItem{},
+	}
+	for _, v := range res.Vulnerabilities {
+		report.Vulnerabilities = append(report.Vulnerabilities, &models.VulnerabilityItem{
+			ID:          v.ID,
+			Package:     v.Package,
+			Version:     v.Version,
+			Fixed:       v.Fixed,
+			Severity:    parseSeverity(v.Severity),
+			Description: v.Description,
+			Link:        v.Link,
+		})
+	}
+	models.SortVulnerabilities(report.Vulnerabilities)
+	return report, nil
+}
+
+// parseSeverity parses the severity of the generic HTTP scanner protocol, the
+// unrecognized ones are unknown
+func parseSeverity(sev string) models.Severity {
+	switch strings.ToLower(sev) {
+	case "negligible":
+		return models.SevNone
+	case "low":
+		return models.SevLow
+	case "medium":
+		return models.SevMedium
+	case "high", "critical":
+		return models.SevHigh
+	default:
+		return models.SevUnknown
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestHTTPScanner(t *testing.T) {
+	var submitted *Image
+	ready := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/scan", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		submitted = &Image{}
+		if err := json.NewDecoder(r.Body).Decode(submitted); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id": "report-1"}`))
+	})
+	mux.HandleFunc("/api/v1/scan/report-1/report", func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"components": 3, "vulnerabilities": [
+			{"id": "CVE-2015-8776", "package": "glibc", "version": "2.19-18", "severity": "Low"},
+			{"id": "CVE-2016-2177", "package": "openssl", "version": "1.0.1t-1",
+			"fixed_version": "1.0.1t-1+deb8u1", "severity": "critical"}]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newHTTPScanner(server.URL)
+	img := &Image{
+		ID:          "1",
+		Repository:  "library/hello-world",
+		Digest:      "sha256:digest",
+		ManifestURL: "http://registry:5000/v2/library/hello-world/manifests/sha256:digest",
+		Layers: []*Layer{
+			{
+				Digest: "sha256:layer",
+				URL:    "http://registry:5000/v2/library/hello-world/blobs/sha256:layer",
+			},
+		},
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	key, err := s.Submit(img)
+	require.Nil(t, err)
+	assert.Equal(t, "report-1", key)
+	assert.Equal(t, img, submitted)
+
+	_, err = s.GetReport(key)
+	assert.Equal(t, ErrReportNotReady, err)
+
+	ready = true
+	report, err := s.GetReport(key)
+	require.Nil(t, err)
+	assert.Equal(t, 3, report.Components)
+	require.Equal(t, 2, len(report.Vulnerabilities))
+	assert.Equal(t, &models.VulnerabilityItem{
+		ID:       "CVE-2016-2177",
+		Package:  "openssl",
+		Version:  "1.0.1t-1",
+		Fixed:    "1.0.1t-1+deb8u1",
+		Severity: models.SevHigh,
+	}, report.Vulnerabilities[0])
+	assert.Equal(t, models.SevLow, report.Vulnerabilities[1].Severity)
+
+	_, err = s.GetReport("unknown")
+	assert.NotNil(t, err)
+}
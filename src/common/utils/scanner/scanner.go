@@ -0,0 +1,131 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// ErrReportNotReady is returned by GetReport when the image is still being scanned
+var ErrReportNotReady = errors.New("the report is not ready")
+
+// Scanner scans the images for vulnerabilities
+type Scanner interface {
+	// Submit submits the image to the scanner and returns the key of the report,
+	// which can be used to get the report via GetReport
+	Submit(img *Image) (string, error)
+	// GetReport returns the report of the image, ErrReportNotReady is returned
+	// if the scanning is in progress
+	GetReport(key string) (*Report, error)
+}
+
+// Image is the image to be scanned, the scanner pulls the manifest and the
+// layers from the registry with the headers
+type Image struct {
+	// ID identifies the request of scanning, e.g. the ID of the scan job
+	ID          string            `json:"id"`
+	Repository  string            `json:"repository"`
+	Digest      string            `json:"digest"`
+	ManifestURL string            `json:"manifest_url"`
+	Layers      []*Layer          `json:"layers"`
+	Headers     map[string]string `json:"headers"`
+}
+
+// Layer is a layer of the image, the layers are ordered from the base one
+type Layer struct {
+	Digest string `json:"digest"`
+	URL    string `json:"url"`
+}
+
+// Report is the result of scanning an image
+type Report struct {
+	// Components is the number of the components found in the image
+	Components int
+	// Vulnerabilities are sorted by severity in descending order
+	Vulnerabilities []*models.VulnerabilityItem
+}
+
+// New returns a scanner according to the kind, which is one of clair and http
+func New(kind, endpoint string, logger *log.Logger) (Scanner, error) {
+	switch kind {
+	case common.ScannerClair:
+		return newClairScanner(endpoint, logger), nil
+	case common.ScannerHTTP:
+		return newHTTPScanner(endpoint), nil
+	default:
+		return nil, fmt.Errorf("unsupported scanner: %s", kind)
+	}
+}
+
+// Overview returns the overall severity of the image and the number of the
+// components in each severity, the severity of a component is the highest one
+// of its vulnerabilities
+func (r *Report) Overview() (models.Severity, *models.ComponentsOverview) {
+	components := map[string]models.Severity{}
+	for _, v := range r.Vulnerabilities {
+		key := v.Package + ":" + v.Version
+		if sev, ok := components[key]; !ok || v.Severity > sev {
+			components[key] = v.Severity
+		}
+	}
+
+	total := r.Components
+	if total < len(components) {
+		total = len(components)
+	}
+	vulnMap := map[models.Severity]int{}
+	if total > len(components) {
+		vulnMap[models.SevNone] = total - len(components)
+	}
+	for _, sev := range components {
+		vulnMap[sev]++
+	}
+
+	overallSev := models.SevNone
+	summary := []*models.ComponentsOverviewEntry{}
+	for sev, count := range vulnMap {
+		if sev > overallSev {
+			overallSev = sev
+		}
+		summary = append(summary, &models.ComponentsOverviewEntry{
+			Sev:   int(sev),
+			Count: count,
+		})
+	}
+	return overallSev, &models.ComponentsOverview{
+		Total:   total,
+		Summary: summary,
+	}
+}
+
+// Severities returns the highest severity of each CVE found in the image, the
+// negligible ones are not included as they don't affect the overall severity
+func (r *Report) Severities() map[string]models.Severity {
+	sevs := map[string]models.Severity{}
+	for _, v := range r.Vulnerabilities {
+		if v.Severity <= models.SevNone {
+			continue
+		}
+		if sev, ok := sevs[v.ID]; !ok || v.Severity > sev {
+			sevs[v.ID] = v.Severity
+		}
+	}
+	return sevs
+}
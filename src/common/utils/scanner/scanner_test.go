@@ -0,0 +1,79 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestNew(t *testing.T) {
+	s, err := New(common.ScannerClair, "http://clair:6060", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, ok := s.(*clairScanner)
+	assert.True(t, ok)
+
+	s, err = New(common.ScannerHTTP, "http://scanner:8080/", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	h, ok := s.(*httpScanner)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "http://scanner:8080", h.endpoint)
+
+	_, err = New("unknown", "", nil)
+	assert.NotNil(t, err)
+}
+
+func TestOverview(t *testing.T) {
+	report := &Report{
+		Components: 4,
+		Vulnerabilities: []*models.VulnerabilityItem{
+			{ID: "CVE-2016-2177", Package: "openssl", Version: "1.0.1t-1", Severity: models.SevHigh},
+			{ID: "CVE-2016-2178", Package: "openssl", Version: "1.0.1t-1", Severity: models.SevLow},
+			{ID: "CVE-2015-8776", Package: "glibc", Version: "2.19-18", Severity: models.SevLow},
+		},
+	}
+	sev, overview := report.Overview()
+	assert.Equal(t, models.SevHigh, sev)
+	assert.Equal(t, 4, overview.Total)
+	counts := map[int]int{}
+	for _, entry := range overview.Summary {
+		counts[entry.Sev] = entry.Count
+	}
+	assert.Equal(t, map[int]int{
+		int(models.SevNone): 2,
+		int(models.SevLow):  1,
+		int(models.SevHigh): 1,
+	}, counts)
+
+	assert.Equal(t, map[string]models.Severity{
+		"CVE-2016-2177": models.SevHigh,
+		"CVE-2016-2178": models.SevLow,
+		"CVE-2015-8776": models.SevLow,
+	}, report.Severities())
+
+	sev, overview = (&Report{}).Overview()
+	assert.Equal(t, models.SevNone, sev)
+	assert.Equal(t, 0, overview.Total)
+	assert.Equal(t, 0, len(overview.Summary))
+}
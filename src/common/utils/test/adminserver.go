@@ -42,6 +42,11 @@ var adminServerDefaultConfig = map[string]interface{}{
 	common.LDAPFilter:                 "",
 	common.LDAPScope:                  3,
 	common.LDAPTimeout:                30,
+	common.LDAPGroupBaseDN:            "ou=groups,dc=mydomain,dc=com",
+	common.LDAPGroupFilter:            "objectclass=groupOfUniqueNames",
+	common.LDAPGroupNameAttribute:     "cn",
+	common.LDAPGroupScope:             3,
+	common.LDAPGroupMembershipAttr:    "memberof",
 	common.TokenServiceURL:            "http://token_service",
 	common.RegistryURL:                "http://registry",
 	common.EmailHost:                  "127.0.0.1",
@@ -60,6 +65,31 @@ var adminServerDefaultConfig = map[string]interface{}{
 	common.AdmiralEndpoint:            "http://www.vmware.com",
 	common.WithNotary:                 false,
 	common.WithClair:                  false,
+	common.RepRetryMaxAttempts:        10,
+	common.RepRetryInitialDelay:       300,
+	common.RepRetryMultiplier:         2,
+	common.RepRetryJitter:             0.2,
+	common.RepChunkSize:               50,
+	// the lockout is disabled so that the failed logins in tests don't
+	// lock the test users
+	common.LoginMaxFailures:         0,
+	common.LoginMaxFailuresPerIP:    0,
+	common.LoginLockDuration:        300,
+	common.PasswordMinLength:        8,
+	common.PasswordRequireUppercase: true,
+	common.PasswordRequireLowercase: true,
+	common.PasswordRequireNumber:    true,
+	common.PasswordRequireSpecial:   false,
+	common.TOTPRequiredForAdmin:     false,
+	common.ScanAllPolicy:            "none",
+	common.Scanner:                  "clair",
+	common.ScannerEndpoint:          "",
+	common.OIDCEndpoint:             "",
+	common.OIDCClientID:             "",
+	common.OIDCClientSecret:         "",
+	common.OIDCScope:                "openid,profile,email",
+	common.OIDCGroupsClaim:          "groups",
+	common.OIDCVerifyCert:           true,
 }
 
 // NewAdminserver returns a mock admin server
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the SHA1 test vectors in RFC 6238, truncated to 6 digits
+func TestCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for ts, expected := range cases {
+		code, err := Code(strings.TrimRight(secret, "="), StepOf(time.Unix(ts, 0)))
+		require.Nil(t, err)
+		assert.Equal(t, expected, code, "timestamp %d", ts)
+	}
+
+	_, err := Code("not base32!", 1)
+	assert.NotNil(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.Nil(t, err)
+
+	now := time.Now()
+	code, err := Code(secret, StepOf(now))
+	require.Nil(t, err)
+
+	step, err := Validate(secret, code, now)
+	require.Nil(t, err)
+	assert.Equal(t, StepOf(now), step)
+
+	// the clock drift of one step is tolerated
+	step, err = Validate(secret, code, now.Add(Step*time.Second))
+	require.Nil(t, err)
+	assert.Equal(t, StepOf(now), step)
+
+	step, err = Validate(secret, code, now.Add(3*Step*time.Second))
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), step)
+
+	step, err = Validate(secret, "123", now)
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), step)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Harbor", "alice", "ABCDEF")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Harbor:alice?"))
+	assert.Contains(t, uri, "secret=ABCDEF")
+	assert.Contains(t, uri, "issuer=Harbor")
+}
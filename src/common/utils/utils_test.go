@@ -107,6 +107,40 @@ func TestEncrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptPassword(t *testing.T) {
+	password := "Harbor12345"
+	salt := "salt"
+
+	hash, err := EncryptPassword(password, salt, PasswordVersionSHA1)
+	if err != nil {
+		t.Fatalf("failed to encrypt password: %v", err)
+	}
+	if hash != Encrypt(password, salt) {
+		t.Errorf("unexpected legacy hash: %s", hash)
+	}
+
+	hash, err = EncryptPassword(password, salt, CurrentPasswordVersion)
+	if err != nil {
+		t.Fatalf("failed to encrypt password: %v", err)
+	}
+	if len(hash) != 64 {
+		t.Errorf("unexpected length of hash: %d", len(hash))
+	}
+	if !MatchPassword(password, salt, CurrentPasswordVersion, hash) {
+		t.Errorf("password should match the hash")
+	}
+	if MatchPassword("wrong", salt, CurrentPasswordVersion, hash) {
+		t.Errorf("wrong password should not match the hash")
+	}
+	if MatchPassword(password, salt, PasswordVersionSHA1, hash) {
+		t.Errorf("password should not match the hash of another version")
+	}
+
+	if _, err = EncryptPassword(password, salt, "unknown"); err == nil {
+		t.Errorf("an error is expected for unsupported version")
+	}
+}
+
 func TestReversibleEncrypt(t *testing.T) {
 	password := "password"
 	key := "1234567890123456"
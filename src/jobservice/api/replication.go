@@ -25,8 +25,10 @@ import (
 	"github.com/vmware/harbor/src/common/models"
 	u "github.com/vmware/harbor/src/common/utils"
 	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/common/utils/registry/auth"
 	"github.com/vmware/harbor/src/jobservice/config"
 	"github.com/vmware/harbor/src/jobservice/job"
+	"github.com/vmware/harbor/src/jobservice/utils"
 )
 
 // ReplicationJob handles /api/replicationJobs /api/replicationJobs/:id/log
@@ -65,21 +67,11 @@ func (rj *ReplicationJob) Post() {
 		return
 	}
 	if len(data.Repo) == 0 { // sync all repositories
-		repoList, err := getRepoList(p.ProjectID)
-		if err != nil {
-			log.Errorf("Failed to get repository list, project id: %d, error: %v", p.ProjectID, err)
+		if err := SyncPolicy(p); err != nil {
+			log.Errorf("Failed to sync policy %d, error: %v", p.ID, err)
 			rj.RenderError(http.StatusInternalServerError, err.Error())
 			return
 		}
-		log.Debugf("repo list: %v", repoList)
-		for _, repo := range repoList {
-			err := rj.addJob(repo, data.PolicyID, models.RepOpTransfer)
-			if err != nil {
-				log.Errorf("Failed to insert job record, error: %v", err)
-				rj.RenderError(http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
 	} else { // sync a single repository
 		var op string
 		if len(data.Operation) > 0 {
@@ -87,7 +79,7 @@ func (rj *ReplicationJob) Post() {
 		} else {
 			op = models.RepOpTransfer
 		}
-		err := rj.addJob(data.Repo, data.PolicyID, op, data.TagList...)
+		err := addJob(data.Repo, data.PolicyID, op, data.TagList...)
 		if err != nil {
 			log.Errorf("Failed to insert job record, error: %v", err)
 			rj.RenderError(http.StatusInternalServerError, err.Error())
@@ -96,7 +88,37 @@ func (rj *ReplicationJob) Post() {
 	}
 }
 
-func (rj *ReplicationJob) addJob(repo string, policyID int64, operation string, tags ...string) error {
+// SyncPolicy creates transfer jobs for all the repositories under the project
+// of the policy, or all the repositories in the target if the policy pulls,
+// it is used by both the API and the replication scheduler.
+func SyncPolicy(p *models.RepPolicy) error {
+	var repoList []string
+	var err error
+	if p.IsPull() {
+		repoList, err = getRemoteRepoList(p.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to get repository list, target id: %d, error: %v", p.TargetID, err)
+		}
+	} else {
+		repoList, err = getRepoList(p.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to get repository list, project id: %d, error: %v", p.ProjectID, err)
+		}
+	}
+	log.Debugf("repo list: %v", repoList)
+	for _, repo := range repoList {
+		if !p.MatchRepository(repo) {
+			log.Debugf("repository %s is filtered out by policy %d", repo, p.ID)
+			continue
+		}
+		if err := addJob(repo, p.ID, models.RepOpTransfer); err != nil {
+			return fmt.Errorf("failed to insert job record, error: %v", err)
+		}
+	}
+	return nil
+}
+
+func addJob(repo string, policyID int64, operation string, tags ...string) error {
 	j := models.RepJob{
 		Repository: repo,
 		PolicyID:   policyID,
@@ -210,3 +232,39 @@ func getRepoList(projectID int64) ([]string, error) {
 
 	return repositories, nil
 }
+
+// getRemoteRepoList lists the repositories in the catalog of the target
+func getRemoteRepoList(targetID int64) ([]string, error) {
+	target, err := dao.GetRepTarget(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("target %d does not exist", targetID)
+	}
+
+	pwd := target.Password
+	if len(pwd) != 0 {
+		key, err := config.SecretKey()
+		if err != nil {
+			return nil, err
+		}
+		if pwd, err = u.ReversibleDecrypt(pwd, key); err != nil {
+			return nil, fmt.Errorf("failed to decrypt password: %v", err)
+		}
+	}
+
+	verify, err := config.VerifyRemoteCert()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := utils.NewRegistryClient(target.URL, !verify,
+		auth.NewBasicAuthCredential(target.Username, pwd),
+		"", "registry", "catalog", "*")
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Catalog()
+}
@@ -17,9 +17,12 @@ package api
 import (
 	"net/http"
 
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
+	registry_error "github.com/vmware/harbor/src/common/utils/error"
 	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/common/utils/registry"
 	"github.com/vmware/harbor/src/common/utils/registry/auth"
 	"github.com/vmware/harbor/src/jobservice/config"
 	"github.com/vmware/harbor/src/jobservice/job"
@@ -37,7 +40,8 @@ func (isj *ImageScanJob) Prepare() {
 	//	isj.authenticate()
 }
 
-// Post creates a scanner job and hand it to statemachine.
+// Post creates a scanner job and hand it to statemachine. If the tag references
+// a manifest list, a job is created for each image in the list.
 func (isj *ImageScanJob) Post() {
 	var data models.ImageScanReq
 	isj.DecodeJSONReq(&data)
@@ -56,31 +60,69 @@ func (isj *ImageScanJob) Post() {
 		isj.RenderError(http.StatusInternalServerError, "Failed to repository client")
 		return
 	}
-	digest, exist, err := repoClient.ManifestExist(data.Tag)
+	digests, err := getImageDigests(repoClient, data.Tag)
 	if err != nil {
+		if regErr, ok := err.(*registry_error.Error); ok && regErr.StatusCode == http.StatusNotFound {
+			log.Errorf("The repository based on request: %+v does not exist", data)
+			isj.RenderError(http.StatusNotFound, "")
+			return
+		}
 		log.Errorf("Failed to get manifest, error: %v", err)
 		isj.RenderError(http.StatusInternalServerError, "Failed to get manifest")
 		return
 	}
-	if !exist {
-		log.Errorf("The repository based on request: %+v does not exist", data)
-		isj.RenderError(http.StatusNotFound, "")
-		return
+	for _, digest := range digests {
+		if err := addScanJob(data.Repo, data.Tag, digest); err != nil {
+			log.Errorf("Failed to add scan job to DB, error: %v", err)
+			isj.RenderError(http.StatusInternalServerError, "Failed to insert scan job data.")
+			return
+		}
 	}
-	//Insert job into DB
+}
+
+// addScanJob inserts the job scanning the image into DB and hands it to the scheduler
+func addScanJob(repository, tag, digest string) error {
 	j := models.ScanJob{
-		Repository: data.Repo,
-		Tag:        data.Tag,
+		Repository: repository,
+		Tag:        tag,
 		Digest:     digest,
 	}
 	jid, err := dao.AddScanJob(j)
 	if err != nil {
-		log.Errorf("Failed to add scan job to DB, error: %v", err)
-		isj.RenderError(http.StatusInternalServerError, "Failed to insert scan job data.")
-		return
+		return err
 	}
 	log.Debugf("Scan job id: %d", jid)
 	sj := job.NewScanJob(jid)
 	log.Debugf("Sent job to scheduler, job: %v", sj)
 	job.Schedule(sj)
+	return nil
+}
+
+// getImageDigests returns the digest of the image the tag references, or the
+// digests of the images in the manifest list if the tag references a list
+func getImageDigests(client *registry.Repository, tag string) ([]string, error) {
+	digest, mediaType, payload, err := client.PullManifest(tag,
+		[]string{schema2.MediaTypeManifest, registry.MediaTypeManifestList})
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaType != registry.MediaTypeManifestList {
+		return []string{digest}, nil
+	}
+
+	list := &registry.DeserializedManifestList{}
+	if err = list.UnmarshalJSON(payload); err != nil {
+		return nil, err
+	}
+	digests := []string{}
+	for _, m := range list.Manifests {
+		if m.MediaType != schema2.MediaTypeManifest {
+			log.Warningf("manifest %s with media type %s in manifest list of %s:%s can not be scanned",
+				m.Digest, m.MediaType, client.Name, tag)
+			continue
+		}
+		digests = append(digests, m.Digest.String())
+	}
+	return digests, nil
 }
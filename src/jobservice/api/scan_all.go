@@ -0,0 +1,208 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/common/utils/registry/auth"
+	"github.com/vmware/harbor/src/jobservice/config"
+	"github.com/vmware/harbor/src/jobservice/job"
+	"github.com/vmware/harbor/src/jobservice/utils"
+)
+
+// the interval to check whether there is a free scan worker
+const scanWorkerCheckInterval = 10 * time.Second
+
+// the length of column error in table scan_all_job
+const maxScanAllErrorLen = 1024
+
+// ErrScanAllRunning is returned when scanning all images is requested while
+// the images are being scanned
+var ErrScanAllRunning = errors.New("all images are being scanned")
+
+var (
+	scanAllLock    sync.Mutex
+	scanAllRunning bool
+)
+
+// ScanAllJob handles /api/jobs/scan/all
+type ScanAllJob struct {
+	jobBaseAPI
+}
+
+// Prepare ...
+func (s *ScanAllJob) Prepare() {
+	s.authenticate()
+}
+
+// Post starts to scan all images in background
+func (s *ScanAllJob) Post() {
+	id, err := StartScanAll(models.ScanAllTriggerManual)
+	if err == ErrScanAllRunning {
+		s.RenderError(http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to start scanning all images, error: %v", err)
+		s.RenderError(http.StatusInternalServerError, "Failed to start scanning all images")
+		return
+	}
+	log.Debugf("Scan all job id: %d", id)
+}
+
+// StartScanAll records a scan all job and scans all images in background, it
+// is used by both the API and the scan all scheduler.
+func StartScanAll(trigger string) (int64, error) {
+	scanAllLock.Lock()
+	defer scanAllLock.Unlock()
+	if scanAllRunning {
+		return 0, ErrScanAllRunning
+	}
+
+	id, err := dao.AddScanAllJob(trigger)
+	if err != nil {
+		return 0, err
+	}
+	scanAllRunning = true
+
+	go func() {
+		defer func() {
+			scanAllLock.Lock()
+			scanAllRunning = false
+			scanAllLock.Unlock()
+		}()
+
+		errMsg := ""
+		if err := scanAll(id); err != nil {
+			log.Errorf("Failed to scan all images, scan all job id: %d, error: %v", id, err)
+			errMsg = err.Error()
+			if len(errMsg) > maxScanAllErrorLen {
+				errMsg = errMsg[:maxScanAllErrorLen]
+			}
+		}
+		if err := dao.FinishScanAllJob(id, errMsg); err != nil {
+			log.Errorf("Failed to update status of scan all job %d, error: %v", id, err)
+		}
+	}()
+	return id, nil
+}
+
+// image is an image referenced by a tag
+type image struct {
+	repository string
+	tag        string
+	digest     string
+}
+
+// scanAll creates a scan job for every image, the scan jobs are created no
+// faster than the scan workers handle them, so that the jobs triggered by
+// users are not queued behind all the images
+func scanAll(id int64) error {
+	images, err := listImages()
+	if err != nil {
+		return err
+	}
+	log.Infof("%d images will be scanned, scan all job id: %d", len(images), id)
+	if err = dao.UpdateScanAllJobProgress(id, len(images), 0); err != nil {
+		return err
+	}
+
+	for i, img := range images {
+		if err = waitForScanWorker(); err != nil {
+			return err
+		}
+		if err = addScanJob(img.repository, img.tag, img.digest); err != nil {
+			return fmt.Errorf("failed to add scan job for %s:%s: %v", img.repository, img.tag, err)
+		}
+		if err = dao.UpdateScanAllJobProgress(id, len(images), i+1); err != nil {
+			log.Errorf("Failed to update progress of scan all job %d, error: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// listImages walks through the tags of all repositories and returns the
+// images referenced by them, the image referenced by more than one tag is
+// only returned once. The repositories and tags which can not be read are
+// skipped.
+func listImages() ([]*image, error) {
+	repos, err := dao.GetAllRepositories()
+	if err != nil {
+		return nil, err
+	}
+	regURL, err := config.LocalRegURL()
+	if err != nil {
+		return nil, err
+	}
+	c := &http.Cookie{Name: models.UISecretCookie, Value: config.JobserviceSecret()}
+
+	images := []*image{}
+	digests := map[string]bool{}
+	for _, repo := range repos {
+		client, err := utils.NewRepositoryClient(regURL, false, auth.NewCookieCredential(c),
+			config.InternalTokenServiceEndpoint(), repo.Name, "pull")
+		if err != nil {
+			log.Errorf("Failed to create repository client for %s, error: %v", repo.Name, err)
+			continue
+		}
+		tags, err := client.ListTag()
+		if err != nil {
+			log.Errorf("Failed to list tags of %s, error: %v", repo.Name, err)
+			continue
+		}
+		for _, tag := range tags {
+			ds, err := getImageDigests(client, tag)
+			if err != nil {
+				log.Errorf("Failed to get manifest of %s:%s, error: %v", repo.Name, tag, err)
+				continue
+			}
+			for _, digest := range ds {
+				if digests[digest] {
+					continue
+				}
+				digests[digest] = true
+				images = append(images, &image{
+					repository: repo.Name,
+					tag:        tag,
+					digest:     digest,
+				})
+			}
+		}
+	}
+	return images, nil
+}
+
+// waitForScanWorker blocks until the number of the pending and running scan
+// jobs is less than the number of the scan workers
+func waitForScanWorker() error {
+	for {
+		n, err := dao.CountScanJobsByStatus(models.JobPending, models.JobRunning)
+		if err != nil {
+			return err
+		}
+		if n < int64(job.MaxScanWorkers()) {
+			return nil
+		}
+		time.Sleep(scanWorkerCheckInterval)
+	}
+}
@@ -30,6 +30,12 @@ const (
 	defaultKeyPath   string = "/etc/jobservice/key"
 	defaultLogDir    string = "/var/log/jobs"
 	secretCookieName string = "secret"
+
+	defaultRepRetryMaxAttempts  = 10
+	defaultRepRetryInitialDelay = 300
+	defaultRepRetryMultiplier   = 2.0
+	defaultRepRetryJitter       = 0.2
+	defaultRepChunkSize         = 50
 )
 
 var (
@@ -114,6 +120,69 @@ func MaxJobWorkers() (int, error) {
 	return int(cfg[common.MaxJobWorkers].(float64)), nil
 }
 
+// ScanAllPolicy returns the policy of scanning all images periodically,
+// it is one of none, daily and weekly. It is always none if no scanner is
+// available, that is Harbor is not deployed with Clair and the endpoint of
+// an external scanner is not configured
+func ScanAllPolicy() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	if !scanEnabled(cfg) {
+		return common.ScanAllPolicyNone, nil
+	}
+	// the configuration is missing if it is upgraded from an older version
+	policy, ok := cfg[common.ScanAllPolicy].(string)
+	if !ok || len(policy) == 0 {
+		return common.ScanAllPolicyNone, nil
+	}
+	return policy, nil
+}
+
+// RepChunkSize returns the size in bytes of the chunks in which the blobs
+// are uploaded by replication jobs, 0 means uploading the blob in one request
+func RepChunkSize() (int64, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return 0, err
+	}
+	size := defaultRepChunkSize
+	if v, ok := cfg[common.RepChunkSize].(float64); ok && v >= 0 {
+		size = int(v)
+	}
+	return int64(size) * 1024 * 1024, nil
+}
+
+// RepRetrySetting returns the global retry settings of replication jobs, the
+// defaults are used for the settings which are not configured
+func RepRetrySetting() (*models.RetrySetting, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	setting := &models.RetrySetting{
+		MaxAttempts:  defaultRepRetryMaxAttempts,
+		InitialDelay: defaultRepRetryInitialDelay,
+		Multiplier:   defaultRepRetryMultiplier,
+		Jitter:       defaultRepRetryJitter,
+	}
+	if v, ok := cfg[common.RepRetryMaxAttempts].(float64); ok {
+		setting.MaxAttempts = int(v)
+	}
+	if v, ok := cfg[common.RepRetryInitialDelay].(float64); ok && v > 0 {
+		setting.InitialDelay = int(v)
+	}
+	if v, ok := cfg[common.RepRetryMultiplier].(float64); ok && v >= 1 {
+		setting.Multiplier = v
+	}
+	if v, ok := cfg[common.RepRetryJitter].(float64); ok && v >= 0 && v <= 1 {
+		setting.Jitter = v
+	}
+	return setting, nil
+}
+
 // LocalUIURL returns the local ui url, job service will use this URL to call API hosted on ui process
 func LocalUIURL() string {
 	return "http://ui"
@@ -172,3 +241,33 @@ func InternalTokenServiceEndpoint() string {
 func ClairEndpoint() string {
 	return "http://clair:6060"
 }
+
+func scanEnabled(cfg map[string]interface{}) bool {
+	kind, _ := cfg[common.Scanner].(string)
+	endpoint, _ := cfg[common.ScannerEndpoint].(string)
+	if kind == common.ScannerHTTP {
+		return len(endpoint) > 0
+	}
+	withClair, _ := cfg[common.WithClair].(bool)
+	return withClair || len(endpoint) > 0
+}
+
+// Scanner returns the kind and the endpoint of the scanner used to scan images,
+// the kind is one of clair and http. Clair deployed within Harbor is used if
+// the scanner is not configured
+func Scanner() (string, string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", "", err
+	}
+	// the configurations are missing if it is upgraded from an older version
+	kind, _ := cfg[common.Scanner].(string)
+	endpoint, _ := cfg[common.ScannerEndpoint].(string)
+	if len(kind) == 0 {
+		kind = common.ScannerClair
+	}
+	if kind == common.ScannerClair && len(endpoint) == 0 {
+		endpoint = ClairEndpoint()
+	}
+	return kind, endpoint, nil
+}
@@ -61,6 +61,22 @@ func TestConfig(t *testing.T) {
 		t.Fatalf("failed to get max job workers: %v", err)
 	}
 
+	setting, err := RepRetrySetting()
+	if err != nil {
+		t.Fatalf("failed to get retry setting: %v", err)
+	}
+	if setting.MaxAttempts != 10 || setting.InitialDelay != 300 {
+		t.Errorf("unexpected retry setting: %+v", setting)
+	}
+
+	chunkSize, err := RepChunkSize()
+	if err != nil {
+		t.Fatalf("failed to get chunk size: %v", err)
+	}
+	if chunkSize != 50*1024*1024 {
+		t.Errorf("unexpected chunk size: %d", chunkSize)
+	}
+
 	if _, err := LocalRegURL(); err != nil {
 		t.Fatalf("failed to get registry URL: %v", err)
 	}
@@ -80,4 +96,12 @@ func TestConfig(t *testing.T) {
 	if _, err := ExtEndpoint(); err != nil {
 		t.Fatalf("failed to get ext endpoint: %v", err)
 	}
+
+	kind, endpoint, err := Scanner()
+	if err != nil {
+		t.Fatalf("failed to get scanner: %v", err)
+	}
+	if kind != "clair" || endpoint != ClairEndpoint() {
+		t.Errorf("unexpected scanner: %s, %s", kind, endpoint)
+	}
 }
@@ -16,11 +16,13 @@ package job
 
 import (
 	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
 	uti "github.com/vmware/harbor/src/common/utils"
 	"github.com/vmware/harbor/src/jobservice/config"
 
 	"fmt"
 	"path/filepath"
+	"time"
 )
 
 // Type is for job Type
@@ -50,6 +52,9 @@ type Job interface {
 	Type() Type
 	LogPath() string
 	UpdateStatus(status string) error
+	//ScheduleRetry marks the job as retrying and persists the time when it should be run again,
+	//it returns false without scheduling if the job has used up its attempts
+	ScheduleRetry() (bool, error)
 	Init() error
 	//Parm() interface{}
 }
@@ -61,16 +66,25 @@ type RepJobParm struct {
 	TargetUsername string
 	TargetPassword string
 	Repository     string
-	Tags           []string
-	Enabled        int
-	Operation      string
-	Insecure       bool
+	// Pull is true if the repository is pulled from the target into the
+	// local registry as LocalRepository
+	Pull            bool
+	LocalRepository string
+	Tags            []string
+	Filters         []*models.RepFilter
+	Enabled         int
+	Operation       string
+	Insecure        bool
+	// ChunkSize is the size in bytes of the chunks in which the blobs are uploaded
+	ChunkSize int64
 }
 
 // RepJob implements Job interface, represents a replication job.
 type RepJob struct {
-	id   int64
-	parm *RepJobParm
+	id       int64
+	parm     *RepJobParm
+	attempts int
+	retry    *models.RetrySetting
 }
 
 // ID returns the ID of the replication job
@@ -93,6 +107,14 @@ func (rj *RepJob) UpdateStatus(status string) error {
 	return dao.UpdateRepJobStatus(rj.id, status)
 }
 
+// ScheduleRetry schedules the next attempt according to the retry settings of the policy
+func (rj *RepJob) ScheduleRetry() (bool, error) {
+	if rj.retry == nil || exhausted(rj.retry, rj.attempts) {
+		return false, nil
+	}
+	return true, dao.ScheduleRepJobRetry(rj.id, nextRetryTime(rj.retry, rj.attempts))
+}
+
 // String ...
 func (rj *RepJob) String() string {
 	return fmt.Sprintf("{JobID: %d, JobType: %v}", rj.ID(), rj.Type())
@@ -128,6 +150,7 @@ func (rj *RepJob) Init() error {
 		LocalRegURL: regURL,
 		Repository:  job.Repository,
 		Tags:        job.TagList,
+		Filters:     policy.FilterList,
 		Enabled:     policy.Enabled,
 		Operation:   job.Operation,
 		Insecure:    !verify,
@@ -136,6 +159,17 @@ func (rj *RepJob) Init() error {
 		//worker will cancel this job
 		return nil
 	}
+	if policy.IsPull() {
+		project, err := dao.GetProjectByID(policy.ProjectID)
+		if err != nil {
+			return fmt.Errorf("Failed to get project, error: %v", err)
+		}
+		if project == nil {
+			return fmt.Errorf("The project doesn't exist in DB, project id: %d", policy.ProjectID)
+		}
+		rj.parm.Pull = true
+		rj.parm.LocalRepository = project.Name + "/" + job.Repository
+	}
 	target, err := dao.GetRepTarget(policy.TargetID)
 	if err != nil {
 		return fmt.Errorf("Failed to get target, error: %v", err)
@@ -159,6 +193,22 @@ func (rj *RepJob) Init() error {
 	}
 
 	rj.parm.TargetPassword = pwd
+
+	if rj.parm.ChunkSize, err = config.RepChunkSize(); err != nil {
+		return err
+	}
+
+	global, err := config.RepRetrySetting()
+	if err != nil {
+		return err
+	}
+	rj.retry = mergeRetrySetting(global, policy)
+
+	// every time the job is handled by a worker counts as an attempt
+	if err = dao.IncreaseRepJobAttempts(rj.id); err != nil {
+		return fmt.Errorf("failed to increase attempts of job: %v", err)
+	}
+	rj.attempts = job.Attempts + 1
 	return nil
 }
 
@@ -206,6 +256,11 @@ func (sj *ScanJob) UpdateStatus(status string) error {
 	return dao.UpdateScanJobStatus(sj.id, status)
 }
 
+//ScheduleRetry schedules the scan job to be run again after a fixed delay, the attempts of scan jobs are not limited.
+func (sj *ScanJob) ScheduleRetry() (bool, error) {
+	return true, dao.ScheduleScanJobRetry(sj.id, time.Now().Add(scanRetryDelay))
+}
+
 //Init query the DB and populate the information of the image to scan in the parm of this job.
 func (sj *ScanJob) Init() error {
 	job, err := dao.GetScanJob(sj.id)
@@ -0,0 +1,74 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// maxRetryDelay caps the exponential growth of the delay, so that the jobs which
+// are retried forever still get a chance to run every day
+const maxRetryDelay = 24 * time.Hour
+
+// mergeRetrySetting overrides the global retry settings with the ones of the policy,
+// the zero values of the policy are ignored
+func mergeRetrySetting(global *models.RetrySetting, policy *models.RepPolicy) *models.RetrySetting {
+	setting := *global
+	if policy.RetryMaxAttempts > 0 {
+		setting.MaxAttempts = policy.RetryMaxAttempts
+	}
+	if policy.RetryInitialDelay > 0 {
+		setting.InitialDelay = policy.RetryInitialDelay
+	}
+	if policy.RetryMultiplier >= 1 {
+		setting.Multiplier = policy.RetryMultiplier
+	}
+	if policy.RetryJitter > 0 {
+		setting.Jitter = policy.RetryJitter
+	}
+	return &setting
+}
+
+// exhausted returns true if no more attempt is allowed after the given number of attempts
+func exhausted(setting *models.RetrySetting, attempts int) bool {
+	return setting.MaxAttempts > 0 && attempts >= setting.MaxAttempts
+}
+
+// backoff returns the delay before the next attempt, attempts is the number of attempts
+// that have been made. The delay is InitialDelay*Multiplier^(attempts-1), randomized by
+// +/- Jitter and capped by maxRetryDelay. random returns a number in [0, 1).
+func backoff(setting *models.RetrySetting, attempts int, random func() float64) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	multiplier := setting.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(setting.InitialDelay) * math.Pow(multiplier, float64(attempts-1))
+	delay = math.Min(delay, maxRetryDelay.Seconds())
+	if setting.Jitter > 0 {
+		delay += delay * setting.Jitter * (2*random() - 1)
+	}
+	return time.Duration(delay * float64(time.Second))
+}
+
+func nextRetryTime(setting *models.RetrySetting, attempts int) time.Time {
+	return time.Now().Add(backoff(setting, attempts, rand.Float64))
+}
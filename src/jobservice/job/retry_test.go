@@ -0,0 +1,76 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestMergeRetrySetting(t *testing.T) {
+	global := &models.RetrySetting{
+		MaxAttempts:  10,
+		InitialDelay: 300,
+		Multiplier:   2,
+		Jitter:       0.2,
+	}
+
+	setting := mergeRetrySetting(global, &models.RepPolicy{})
+	assert.Equal(t, *global, *setting)
+
+	setting = mergeRetrySetting(global, &models.RepPolicy{
+		RetryMaxAttempts:  3,
+		RetryInitialDelay: 60,
+		RetryMultiplier:   1.5,
+		RetryJitter:       0.5,
+	})
+	assert.Equal(t, models.RetrySetting{
+		MaxAttempts:  3,
+		InitialDelay: 60,
+		Multiplier:   1.5,
+		Jitter:       0.5,
+	}, *setting)
+	assert.Equal(t, 10, global.MaxAttempts)
+}
+
+func TestExhausted(t *testing.T) {
+	assert.False(t, exhausted(&models.RetrySetting{MaxAttempts: 3}, 2))
+	assert.True(t, exhausted(&models.RetrySetting{MaxAttempts: 3}, 3))
+	assert.False(t, exhausted(&models.RetrySetting{MaxAttempts: 0}, 100))
+}
+
+func TestBackoff(t *testing.T) {
+	setting := &models.RetrySetting{
+		InitialDelay: 60,
+		Multiplier:   2,
+	}
+	middle := func() float64 { return 0.5 }
+	assert.Equal(t, 60*time.Second, backoff(setting, 1, middle))
+	assert.Equal(t, 120*time.Second, backoff(setting, 2, middle))
+	assert.Equal(t, 480*time.Second, backoff(setting, 4, middle))
+	assert.Equal(t, maxRetryDelay, backoff(setting, 100, middle))
+
+	setting.Jitter = 0.5
+	assert.Equal(t, 60*time.Second, backoff(setting, 1, middle))
+	assert.Equal(t, 30*time.Second, backoff(setting, 1, func() float64 { return 0 }))
+	assert.Equal(t, 90*time.Second, backoff(setting, 1, func() float64 { return 1 }))
+
+	setting.Jitter = 0
+	setting.Multiplier = 0
+	assert.Equal(t, 60*time.Second, backoff(setting, 5, middle))
+}
@@ -15,21 +15,63 @@
 package job
 
 import (
-	"github.com/vmware/harbor/src/common/utils/log"
 	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+const (
+	// scanRetryDelay is the interval between a scan job entering "retrying" and being run again
+	scanRetryDelay = 5 * time.Minute
+	// retryCheckInterval is how often the DB is checked for the jobs to retry
+	retryCheckInterval = 30 * time.Second
 )
 
 var jobQueue = make(chan Job)
 
-// Schedule put a job id into job queue.
+// Schedule put a job into the job queue, the job should have been persisted in DB with status "pending",
+// so that it can be resumed if the job service is restarted before the job is handled.
 func Schedule(j Job) {
 	jobQueue <- j
 }
 
-// Reschedule is called by statemachine to retry a job
-func Reschedule(j Job) {
-	log.Debugf("Job %v will be rescheduled in 5 minutes", j)
-	time.Sleep(5 * time.Minute)
-	log.Debugf("Rescheduling job %v", j)
-	Schedule(j)
+// ScheduleRetries checks the DB periodically, moves the retrying jobs whose retry time has come back
+// to "pending" and schedules them. It never returns.
+func ScheduleRetries() {
+	for {
+		scheduleRetries(time.Now())
+		time.Sleep(retryCheckInterval)
+	}
+}
+
+func scheduleRetries(now time.Time) {
+	repJobs, err := dao.GetRepJobsToRetry(now)
+	if err != nil {
+		log.Errorf("Failed to get replication jobs to retry, error: %v", err)
+	}
+	for _, j := range repJobs {
+		if err := dao.UpdateRepJobStatus(j.ID, models.JobPending); err != nil {
+			log.Errorf("Failed to update status of job %d to pending, error: %v", j.ID, err)
+			continue
+		}
+		rj := NewRepJob(j.ID)
+		log.Debugf("Rescheduling job %v", rj)
+		Schedule(rj)
+	}
+
+	scanJobs, err := dao.GetScanJobsToRetry(now)
+	if err != nil {
+		log.Errorf("Failed to get scan jobs to retry, error: %v", err)
+	}
+	for _, j := range scanJobs {
+		if err := dao.UpdateScanJobStatus(j.ID, models.JobPending); err != nil {
+			log.Errorf("Failed to update status of scan job %d to pending, error: %v", j.ID, err)
+			continue
+		}
+		sj := NewScanJob(j.ID)
+		log.Debugf("Rescheduling job %v", sj)
+		Schedule(sj)
+	}
 }
@@ -58,20 +58,26 @@ func (su StatusUpdater) Exit() error {
 	return nil
 }
 
-// Retry handles a special "retrying" in which case it will update the status in DB and reschedule the job
-// via scheduler
+// Retry handles a special "retrying" in which case it will update the status and the retry time in DB,
+// the job will be picked up again by the scheduler once the retry time has come. If the job has used
+// up its attempts, it enters the "error" state instead.
 type Retry struct {
 	Job Job
 }
 
 // Enter ...
 func (jr Retry) Enter() (string, error) {
-	err := jr.Job.UpdateStatus(models.JobRetrying)
+	scheduled, err := jr.Job.ScheduleRetry()
 	if err != nil {
 		log.Errorf("Failed to update state of job: %v to Retrying, error: %v", jr.Job, err)
+		return "", err
 	}
-	go Reschedule(jr.Job)
-	return "", err
+	if !scheduled {
+		log.Warningf("Job: %v has used up its attempts, entering error state", jr.Job)
+		return models.JobError, nil
+	}
+	log.Debugf("Job: %v will be retried", jr.Job)
+	return "", nil
 }
 
 // Exit ...
@@ -231,9 +231,11 @@ func (sm *SM) initTransitions() error {
 			return fmt.Errorf("The job: %v is not a type of RepJob", sm.CurrentJob)
 		}
 		jobParm := repJob.parm
-		if jobParm.Operation == models.RepOpTransfer {
+		if jobParm.Operation == models.RepOpTransfer && jobParm.Pull {
+			addImgPullTransition(sm, jobParm)
+		} else if jobParm.Operation == models.RepOpTransfer {
 			addImgTransferTransition(sm, jobParm)
-		} else if jobParm.Operation == models.RepOpDelete {
+		} else if jobParm.Operation == models.RepOpDelete && !jobParm.Pull {
 			addImgDeleteTransition(sm, jobParm)
 		} else {
 			return fmt.Errorf("unsupported operation: %s", jobParm.Operation)
@@ -269,23 +271,35 @@ func addImgScanTransition(sm *SM, parm *ScanJobParm) {
 		Logger:     sm.Logger,
 	}
 
-	layerScanHandler := &scan.LayerScanHandler{Context: ctx}
+	poller := &scan.ReportPoller{Context: ctx}
 	sm.AddTransition(models.JobRunning, scan.StateInitialize, &scan.Initializer{Context: ctx})
-	sm.AddTransition(scan.StateInitialize, scan.StateScanLayer, layerScanHandler)
-	sm.AddTransition(scan.StateScanLayer, scan.StateScanLayer, layerScanHandler)
-	sm.AddTransition(scan.StateScanLayer, scan.StateSummarize, &scan.SummarizeHandler{Context: ctx})
+	sm.AddTransition(scan.StateInitialize, scan.StateSubmit, &scan.Submitter{Context: ctx})
+	sm.AddTransition(scan.StateSubmit, scan.StatePoll, poller)
+	sm.AddTransition(scan.StatePoll, scan.StatePoll, poller)
+	sm.AddTransition(scan.StatePoll, scan.StateSummarize, &scan.SummarizeHandler{Context: ctx})
 	sm.AddTransition(scan.StateSummarize, models.JobFinished, &StatusUpdater{sm.CurrentJob, models.JobFinished})
 }
 
 func addImgTransferTransition(sm *SM, parm *RepJobParm) {
 	base := replication.InitBaseHandler(parm.Repository, parm.LocalRegURL, config.JobserviceSecret(),
 		parm.TargetURL, parm.TargetUsername, parm.TargetPassword,
-		parm.Insecure, parm.Tags, sm.Logger)
+		parm.Insecure, parm.Tags, parm.Filters, sm.Logger)
+	addTransferTransitions(sm, base, parm.ChunkSize)
+}
+
+func addImgPullTransition(sm *SM, parm *RepJobParm) {
+	base := replication.InitPullBaseHandler(parm.Repository, parm.LocalRepository,
+		parm.TargetURL, parm.TargetUsername, parm.TargetPassword,
+		parm.LocalRegURL, config.JobserviceSecret(),
+		parm.Insecure, parm.Tags, parm.Filters, sm.Logger)
+	addTransferTransitions(sm, base, parm.ChunkSize)
+}
 
+func addTransferTransitions(sm *SM, base *replication.BaseHandler, chunkSize int64) {
 	sm.AddTransition(models.JobRunning, replication.StateInitialize, &replication.Initializer{BaseHandler: base})
 	sm.AddTransition(replication.StateInitialize, replication.StateCheck, &replication.Checker{BaseHandler: base})
 	sm.AddTransition(replication.StateCheck, replication.StatePullManifest, &replication.ManifestPuller{BaseHandler: base})
-	sm.AddTransition(replication.StatePullManifest, replication.StateTransferBlob, &replication.BlobTransfer{BaseHandler: base})
+	sm.AddTransition(replication.StatePullManifest, replication.StateTransferBlob, &replication.BlobTransfer{BaseHandler: base, ChunkSize: chunkSize})
 	sm.AddTransition(replication.StatePullManifest, models.JobFinished, &StatusUpdater{sm.CurrentJob, models.JobFinished})
 	sm.AddTransition(replication.StateTransferBlob, replication.StatePushManifest, &replication.ManifestPusher{BaseHandler: base})
 	sm.AddTransition(replication.StatePushManifest, replication.StatePullManifest, &replication.ManifestPuller{BaseHandler: base})
@@ -36,6 +36,11 @@ var WorkerPools map[Type]*workerPool
 //TODO: remove the hard code?
 const maxScanWorker = 3
 
+// MaxScanWorkers returns the number of the workers handling scan jobs
+func MaxScanWorkers() int {
+	return maxScanWorker
+}
+
 // StopJobs accepts a list of jobs and will try to stop them if any of them is being executed by the worker.
 func (wp *workerPool) StopJobs(jobs []Job) {
 	log.Debugf("Works working on jobs: %v will be stopped", jobs)
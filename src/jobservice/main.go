@@ -16,13 +16,16 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/astaxie/beego"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/jobservice/api"
 	"github.com/vmware/harbor/src/jobservice/config"
 	"github.com/vmware/harbor/src/jobservice/job"
+	"github.com/vmware/harbor/src/jobservice/scheduler"
 )
 
 func main() {
@@ -45,26 +48,85 @@ func main() {
 	job.InitWorkerPools()
 	go job.Dispatch()
 	resumeJobs()
+	go job.ScheduleRetries()
+	go scheduler.NewScheduler(time.Minute, api.SyncPolicy).Start()
+	go scheduler.NewScanAllScheduler(time.Minute, config.ScanAllPolicy,
+		lastScanAllTime, scheduledScanAll).Start()
 	beego.Run()
 }
 
+// resumeJobs re-dispatches the jobs which were queued or being handled when the job service stopped,
+// the retrying jobs are left to the scheduler, which picks them up once their retry time has come.
 func resumeJobs() {
-	//TODO: may need to resume scan jobs also?
 	log.Debugf("Trying to resume halted jobs...")
-	err := dao.ResetRunningJobs()
-	if err != nil {
-		log.Warningf("Failed to reset all running jobs to pending, error: %v", err)
+	recoverRunningRepJobs()
+	if err := dao.ResetRunningScanJobs(); err != nil {
+		log.Warningf("Failed to reset running scan jobs to pending, error: %v", err)
+	}
+	if err := dao.StopRunningScanAllJobs(); err != nil {
+		log.Warningf("Failed to stop running scan all jobs, error: %v", err)
 	}
-	jobs, err := dao.GetRepJobByStatus(models.JobPending, models.JobRetrying)
+
+	repJobs, err := dao.GetRepJobByStatus(models.JobPending)
 	if err == nil {
-		for _, j := range jobs {
+		for _, j := range repJobs {
 			rj := job.NewRepJob(j.ID)
 			log.Debugf("Resuming job: %v", rj)
 			job.Schedule(rj)
 		}
 	} else {
-		log.Warningf("Failed to jobs to resume, error: %v", err)
+		log.Warningf("Failed to get replication jobs to resume, error: %v", err)
 	}
+
+	scanJobs, err := dao.GetScanJobsByStatus(models.JobPending)
+	if err == nil {
+		for _, j := range scanJobs {
+			sj := job.NewScanJob(j.ID)
+			log.Debugf("Resuming job: %v", sj)
+			job.Schedule(sj)
+		}
+	} else {
+		log.Warningf("Failed to get scan jobs to resume, error: %v", err)
+	}
+}
+
+// recoverRunningRepJobs handles the replication jobs left in "running" status, they are set back to
+// "pending" to be run again, or to "error" if the policy they belong to no longer exists.
+func recoverRunningRepJobs() {
+	jobs, err := dao.GetRepJobByStatus(models.JobRunning)
+	if err != nil {
+		log.Warningf("Failed to get running jobs to recover, error: %v", err)
+		return
+	}
+	for _, j := range jobs {
+		policy, err := dao.GetRepPolicy(j.PolicyID)
+		if err != nil {
+			log.Warningf("Failed to get policy %d of job %d, error: %v", j.PolicyID, j.ID, err)
+			continue
+		}
+		status := models.JobPending
+		if policy == nil || policy.Deleted == 1 {
+			status = models.JobError
+		}
+		log.Debugf("Recovering running job %d to %s", j.ID, status)
+		if err := dao.UpdateRepJobStatus(j.ID, status); err != nil {
+			log.Warningf("Failed to update status of job %d to %s, error: %v", j.ID, status, err)
+		}
+	}
+}
+
+// lastScanAllTime returns the start time of the latest scan all job
+func lastScanAllTime() (time.Time, error) {
+	j, err := dao.GetLatestScanAllJob("")
+	if err != nil || j == nil {
+		return time.Time{}, err
+	}
+	return j.StartTime, nil
+}
+
+func scheduledScanAll() error {
+	_, err := api.StartScanAll(models.ScanAllTriggerSchedule)
+	return err
 }
 
 func init() {
@@ -0,0 +1,114 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	// the maximum number of blobs whose locations are recorded
+	maxRecordedBlobs = 100000
+	// the maximum number of repositories recorded for one blob
+	maxRepositoriesPerBlob = 5
+)
+
+// blobLocations records the repositories on the destination registries which
+// are known to contain a blob. It is shared by all the replication jobs, so a
+// blob transferred by one job can be mounted by the others instead of being
+// transferred again.
+type blobLocations struct {
+	sync.Mutex
+	// key: registry URL and digest of blob, value: repositories
+	locations map[string][]string
+}
+
+var locations = newBlobLocations()
+
+func newBlobLocations() *blobLocations {
+	return &blobLocations{
+		locations: make(map[string][]string),
+	}
+}
+
+func locationKey(registryURL, digest string) string {
+	return strings.TrimRight(registryURL, "/") + "@" + digest
+}
+
+// add records that the blob exists in the repository of the registry
+func (b *blobLocations) add(registryURL, digest, repository string) {
+	b.Lock()
+	defer b.Unlock()
+
+	key := locationKey(registryURL, digest)
+	repositories, exist := b.locations[key]
+	if !exist && len(b.locations) >= maxRecordedBlobs {
+		// evict an arbitrary blob, the records are only hints
+		for k := range b.locations {
+			delete(b.locations, k)
+			break
+		}
+	}
+
+	for i, repo := range repositories {
+		if repo == repository {
+			repositories = append(repositories[:i], repositories[i+1:]...)
+			break
+		}
+	}
+	// the latest one is put at the beginning
+	repositories = append([]string{repository}, repositories...)
+	if len(repositories) > maxRepositoriesPerBlob {
+		repositories = repositories[:maxRepositoriesPerBlob]
+	}
+	b.locations[key] = repositories
+}
+
+// remove removes the record of the blob in the repository, it is called when
+// the blob can not be mounted from the repository
+func (b *blobLocations) remove(registryURL, digest, repository string) {
+	b.Lock()
+	defer b.Unlock()
+
+	key := locationKey(registryURL, digest)
+	repositories := b.locations[key]
+	for i, repo := range repositories {
+		if repo == repository {
+			repositories = append(repositories[:i:i], repositories[i+1:]...)
+			break
+		}
+	}
+	if len(repositories) == 0 {
+		delete(b.locations, key)
+		return
+	}
+	b.locations[key] = repositories
+}
+
+// get returns the repositories other than the excluded one in which the
+// blob exists
+func (b *blobLocations) get(registryURL, digest, exclude string) []string {
+	b.Lock()
+	defer b.Unlock()
+
+	result := []string{}
+	for _, repo := range b.locations[locationKey(registryURL, digest)] {
+		if repo != exclude {
+			result = append(result, repo)
+		}
+	}
+	return result
+}
@@ -14,9 +14,295 @@
 package replication
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/common/utils/registry"
+	"github.com/vmware/harbor/src/common/utils/test"
 )
 
 func TestMain(t *testing.T) {
 }
 
+func TestInitPullBaseHandler(t *testing.T) {
+	base := InitPullBaseHandler("library/ubuntu", "edge/library/ubuntu",
+		"https://registry.example.com", "user", "password",
+		"http://registry:5000", "secret", false, nil, nil, nil)
+	assert.True(t, base.pull)
+	assert.Equal(t, "edge", base.project)
+	assert.Equal(t, "library/ubuntu", base.repository)
+	assert.Equal(t, "edge/library/ubuntu", base.dstRepository)
+
+	base = InitBaseHandler("library/ubuntu", "http://registry:5000", "secret",
+		"https://registry.example.com", "user", "password", false, nil, nil, nil)
+	assert.False(t, base.pull)
+	assert.Equal(t, "library", base.project)
+	assert.Equal(t, "library/ubuntu", base.dstRepository)
+}
+
+
+func TestTransferInChunks(t *testing.T) {
+	blob := []byte("0123456789")
+	digest := "sha256:0123"
+	repository := "library/hello-world"
+	srcServer := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/%s", repository, digest),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				var offset int
+				fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset)
+				if offset == 0 {
+					w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+					w.Write(blob)
+					return
+				}
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(blob)-1, len(blob)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write(blob[offset:])
+			},
+		})
+	defer srcServer.Close()
+
+	// the destination registry fails the second chunk once
+	uploaded := []byte{}
+	patches := 0
+	location := fmt.Sprintf("/v2/%s/blobs/uploads/uuid", repository)
+	uploadHandler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PATCH":
+			b, _ := ioutil.ReadAll(r.Body)
+			patches++
+			if patches == 2 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			uploaded = append(uploaded, b...)
+			w.Header().Set("Location", location)
+			w.WriteHeader(http.StatusAccepted)
+		case "GET":
+			w.Header().Set("Range", fmt.Sprintf("0-%d", len(uploaded)-1))
+			w.WriteHeader(http.StatusNoContent)
+		case "PUT":
+			w.WriteHeader(http.StatusCreated)
+		}
+	}
+	mappings := []*test.RequestHandlerMapping{
+		&test.RequestHandlerMapping{
+			Method:  "POST",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/", repository),
+			Handler: test.Handler(&test.Response{
+				StatusCode: http.StatusAccepted,
+				Headers: map[string]string{
+					"Location": location,
+				},
+			}),
+		},
+	}
+	for _, method := range []string{"PATCH", "GET", "PUT"} {
+		mappings = append(mappings, &test.RequestHandlerMapping{
+			Method:  method,
+			Pattern: location,
+			Handler: uploadHandler,
+		})
+	}
+	dstServer := test.NewServer(mappings...)
+	defer dstServer.Close()
+
+	srcClient, err := registry.NewRepository(repository, srcServer.URL, &http.Client{})
+	require.Nil(t, err)
+	dstClient, err := registry.NewRepository(repository, dstServer.URL, &http.Client{})
+	require.Nil(t, err)
+
+	transfer := &BlobTransfer{
+		BaseHandler: &BaseHandler{
+			srcClient: srcClient,
+			dstClient: dstClient,
+			logger:    log.DefaultLogger(),
+		},
+		ChunkSize: 4,
+	}
+	require.Nil(t, transfer.transferInChunks(digest))
+	assert.Equal(t, string(blob), string(uploaded))
+	assert.Equal(t, 4, patches)
+}
+
+func TestBlobLocations(t *testing.T) {
+	l := newBlobLocations()
+	url := "https://registry.example.com/"
+	digest := "sha256:0123"
+
+	assert.Equal(t, 0, len(l.get(url, digest, "")))
+
+	l.add(url, digest, "library/ubuntu")
+	l.add(url, digest, "library/debian")
+	l.add(url, digest, "library/ubuntu")
+	assert.Equal(t, []string{"library/ubuntu", "library/debian"}, l.get(url, digest, ""))
+	assert.Equal(t, []string{"library/debian"}, l.get("https://registry.example.com", digest, "library/ubuntu"))
+	assert.Equal(t, 0, len(l.get(url, "sha256:4567", "")))
+
+	for i := 0; i < maxRepositoriesPerBlob+1; i++ {
+		l.add(url, digest, fmt.Sprintf("library/repo%d", i))
+	}
+	assert.Equal(t, maxRepositoriesPerBlob, len(l.get(url, digest, "")))
+
+	for _, repo := range l.get(url, digest, "") {
+		l.remove(url, digest, repo)
+	}
+	assert.Equal(t, 0, len(l.locations))
+}
+
+func TestMount(t *testing.T) {
+	digest := "sha256:4567"
+	repository := "library/hello-world"
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "POST",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/", repository),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("from") == "library/busybox" {
+					w.WriteHeader(http.StatusCreated)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			},
+		})
+	defer server.Close()
+
+	dstClient, err := registry.NewRepository(repository, server.URL, &http.Client{})
+	require.Nil(t, err)
+
+	transfer := &BlobTransfer{
+		BaseHandler: &BaseHandler{
+			dstURL:        server.URL,
+			dstRepository: repository,
+			dstClient:     dstClient,
+			logger:        log.DefaultLogger(),
+		},
+	}
+	assert.False(t, transfer.mount(digest))
+
+	locations.add(server.URL, digest, "library/busybox")
+	locations.add(server.URL, digest, "library/alpine")
+	assert.True(t, transfer.mount(digest))
+	// the repository which can not mount the blob is removed
+	assert.Equal(t, []string{"library/busybox"}, locations.get(server.URL, digest, ""))
+}
+
+func TestReplicateManifestList(t *testing.T) {
+	repository := "library/hello-world"
+	children := []string{
+		"sha256:e692418e4cbaf90ca69d05a66403747baa33ee08806650b51fab815ad7fc331f",
+		"sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+	}
+	list := fmt.Sprintf(`{
+   "schemaVersion": 2,
+   "mediaType": "%s",
+   "manifests": [
+      {"mediaType": "%s", "size": 527, "digest": "%s", "platform": {"architecture": "amd64", "os": "linux"}},
+      {"mediaType": "%s", "size": 527, "digest": "%s", "platform": {"architecture": "arm64", "os": "linux"}}
+   ]
+}`, registry.MediaTypeManifestList, schema2.MediaTypeManifest, children[0], schema2.MediaTypeManifest, children[1])
+	manifest := fmt.Sprintf(`{
+   "schemaVersion": 2,
+   "mediaType": "%s",
+   "config": {"mediaType": "application/vnd.docker.container.image.v1+json", "size": 1528, "digest": "sha256:c54a2cc56cbb2f04003c1cd4507e118af7c0d340fe7e2720f70976c4b75237dc"},
+   "layers": [
+      {"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "size": 972, "digest": "sha256:b04784fba78d739b526e27edc02a5a8cd07b1052e9283f5fc155828f4b614c28"}
+   ]
+}`, schema2.MediaTypeManifest)
+
+	srcServer := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "GET",
+			Pattern: fmt.Sprintf("/v2/%s/manifests/", repository),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				reference := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+				if reference == "latest" {
+					w.Header().Set("Content-Type", registry.MediaTypeManifestList)
+					w.Header().Set("Docker-Content-Digest", "sha256:list")
+					w.Write([]byte(list))
+					return
+				}
+				w.Header().Set("Content-Type", schema2.MediaTypeManifest)
+				w.Header().Set("Docker-Content-Digest", reference)
+				w.Write([]byte(manifest))
+			},
+		})
+	defer srcServer.Close()
+
+	pushed := []string{}
+	dstServer := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "HEAD",
+			Pattern: fmt.Sprintf("/v2/%s/manifests/", repository),
+			Handler: test.Handler(&test.Response{
+				StatusCode: http.StatusNotFound,
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  "HEAD",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/", repository),
+			Handler: test.Handler(nil),
+		},
+		&test.RequestHandlerMapping{
+			Method:  "PUT",
+			Pattern: fmt.Sprintf("/v2/%s/manifests/", repository),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				reference := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+				pushed = append(pushed, reference+" "+r.Header.Get("Content-Type"))
+				w.WriteHeader(http.StatusCreated)
+			},
+		})
+	defer dstServer.Close()
+
+	srcClient, err := registry.NewRepository(repository, srcServer.URL, &http.Client{})
+	require.Nil(t, err)
+	dstClient, err := registry.NewRepository(repository, dstServer.URL, &http.Client{})
+	require.Nil(t, err)
+
+	base := &BaseHandler{
+		repository:     repository,
+		dstRepository:  repository,
+		tags:           []string{"latest"},
+		srcURL:         srcServer.URL,
+		dstURL:         dstServer.URL,
+		srcClient:      srcClient,
+		dstClient:      dstClient,
+		blobsExistence: make(map[string]bool),
+		logger:         log.DefaultLogger(),
+	}
+	puller := &ManifestPuller{BaseHandler: base}
+	pusher := &ManifestPusher{BaseHandler: base}
+
+	for i := 0; i < len(children); i++ {
+		state, err := puller.enter()
+		require.Nil(t, err)
+		require.Equal(t, StateTransferBlob, state)
+		assert.Equal(t, children[i], base.digest)
+		assert.Equal(t, 0, len(base.blobs))
+
+		state, err = pusher.enter()
+		require.Nil(t, err)
+		require.Equal(t, StatePullManifest, state)
+	}
+
+	state, err := puller.enter()
+	require.Nil(t, err)
+	assert.Equal(t, models.JobFinished, state)
+
+	assert.Equal(t, []string{
+		children[0] + " " + schema2.MediaTypeManifest,
+		children[1] + " " + schema2.MediaTypeManifest,
+		"latest " + registry.MediaTypeManifestList,
+	}, pushed)
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -49,6 +50,10 @@ const (
 	StatePushManifest = "push_manifest"
 )
 
+// the maximum times the transfer of a blob is resumed after it is interrupted,
+// the job is retried if the transfer still fails
+const maxBlobTransferResumes = 5
+
 var (
 	// ErrConflict represents http 409 error
 	ErrConflict = errors.New("conflict")
@@ -56,16 +61,26 @@ var (
 
 // BaseHandler holds informations shared by other state handlers
 type BaseHandler struct {
-	project    string // project_name
-	repository string // prject_name/repo_name
-	tags       []string
+	project       string // project_name of the destination repository
+	repository    string // prject_name/repo_name in the source registry
+	dstRepository string // name of the repository in the destination registry
+	tags          []string
+	filters       []*models.RepFilter // filters of the policy applied on tags
+
+	// pull is true when the images are pulled from a remote registry into
+	// the local one, in this case the source registry is accessed with
+	// srcUsr and srcPwd and the destination registry with dstSecret
+	pull bool
 
 	srcURL    string // url of source registry
 	srcSecret string
+	srcUsr    string
+	srcPwd    string
 
-	dstURL string // url of target registry
-	dstUsr string // username ...
-	dstPwd string // password ...
+	dstURL    string // url of target registry
+	dstUsr    string // username ...
+	dstPwd    string // password ...
+	dstSecret string
 
 	insecure bool // whether skip secure check when using https
 
@@ -76,6 +91,13 @@ type BaseHandler struct {
 	digest   string                //digest of tags[0]'s manifest
 	blobs    []string              // blobs need to be transferred for tags[0]
 
+	// when tags[0] references a manifest list, the manifests it references
+	// are replicated one by one before the list itself is pushed, in this
+	// case manifest, digest and blobs above are of children[0]
+	manifestList distribution.Manifest
+	listDigest   string
+	children     []string // digests of the manifests not replicated yet
+
 	blobsExistence map[string]bool //key: digest of blob, value: existence
 
 	logger *log.Logger
@@ -83,11 +105,14 @@ type BaseHandler struct {
 
 // InitBaseHandler initializes a BaseHandler.
 func InitBaseHandler(repository, srcURL, srcSecret,
-	dstURL, dstUsr, dstPwd string, insecure bool, tags []string, logger *log.Logger) *BaseHandler {
+	dstURL, dstUsr, dstPwd string, insecure bool, tags []string,
+	filters []*models.RepFilter, logger *log.Logger) *BaseHandler {
 
 	base := &BaseHandler{
 		repository:     repository,
+		dstRepository:  repository,
 		tags:           tags,
+		filters:        filters,
 		srcURL:         srcURL,
 		srcSecret:      srcSecret,
 		dstURL:         dstURL,
@@ -98,7 +123,34 @@ func InitBaseHandler(repository, srcURL, srcSecret,
 		logger:         logger,
 	}
 
-	base.project = getProjectName(base.repository)
+	base.project = getProjectName(base.dstRepository)
+
+	return base
+}
+
+// InitPullBaseHandler initializes a BaseHandler which pulls the repository
+// from a remote registry and pushes it to the local registry as dstRepository.
+func InitPullBaseHandler(repository, dstRepository, srcURL, srcUsr, srcPwd,
+	dstURL, dstSecret string, insecure bool, tags []string,
+	filters []*models.RepFilter, logger *log.Logger) *BaseHandler {
+
+	base := &BaseHandler{
+		repository:     repository,
+		dstRepository:  dstRepository,
+		tags:           tags,
+		filters:        filters,
+		pull:           true,
+		srcURL:         srcURL,
+		srcUsr:         srcUsr,
+		srcPwd:         srcPwd,
+		dstURL:         dstURL,
+		dstSecret:      dstSecret,
+		insecure:       insecure,
+		blobsExistence: make(map[string]bool, 10),
+		logger:         logger,
+	}
+
+	base.project = getProjectName(base.dstRepository)
 
 	return base
 }
@@ -111,11 +163,14 @@ func (b *BaseHandler) Exit() error {
 func getProjectName(repository string) string {
 	repository = strings.TrimSpace(repository)
 	repository = strings.TrimRight(repository, "/")
-	return repository[:strings.LastIndex(repository, "/")]
+	// the project is the first component of the repository name, the
+	// repositories pulled from remote registries may contain more than one "/"
+	return repository[:strings.Index(repository, "/")]
 }
 
 // Initializer creates clients for source and destination registry,
-// lists tags of the repository if parameter tags is nil.
+// lists tags of the repository if parameter tags is nil and removes
+// the tags which are filtered out by the policy.
 type Initializer struct {
 	*BaseHandler
 }
@@ -135,19 +190,35 @@ func (i *Initializer) Enter() (string, error) {
 }
 
 func (i *Initializer) enter() (string, error) {
-	c := &http.Cookie{Name: models.UISecretCookie, Value: i.srcSecret}
-	srcCred := auth.NewCookieCredential(c)
-	srcClient, err := utils.NewRepositoryClient(i.srcURL, i.insecure, srcCred,
-		config.InternalTokenServiceEndpoint(), i.repository, "pull", "push", "*")
+	var srcClient, dstClient *registry.Repository
+	var err error
+	if i.pull {
+		// the remote registry is only required to grant the pull permission
+		srcCred := auth.NewBasicAuthCredential(i.srcUsr, i.srcPwd)
+		srcClient, err = utils.NewRepositoryClient(i.srcURL, i.insecure, srcCred,
+			"", i.repository, "pull")
+	} else {
+		c := &http.Cookie{Name: models.UISecretCookie, Value: i.srcSecret}
+		srcCred := auth.NewCookieCredential(c)
+		srcClient, err = utils.NewRepositoryClient(i.srcURL, i.insecure, srcCred,
+			config.InternalTokenServiceEndpoint(), i.repository, "pull", "push", "*")
+	}
 	if err != nil {
 		i.logger.Errorf("an error occurred while creating source repository client: %v", err)
 		return "", err
 	}
 	i.srcClient = srcClient
 
-	dstCred := auth.NewBasicAuthCredential(i.dstUsr, i.dstPwd)
-	dstClient, err := utils.NewRepositoryClient(i.dstURL, i.insecure, dstCred,
-		"", i.repository, "pull", "push", "*")
+	if i.pull {
+		c := &http.Cookie{Name: models.UISecretCookie, Value: i.dstSecret}
+		dstCred := auth.NewCookieCredential(c)
+		dstClient, err = utils.NewRepositoryClient(i.dstURL, i.insecure, dstCred,
+			config.InternalTokenServiceEndpoint(), i.dstRepository, "pull", "push", "*")
+	} else {
+		dstCred := auth.NewBasicAuthCredential(i.dstUsr, i.dstPwd)
+		dstClient, err = utils.NewRepositoryClient(i.dstURL, i.insecure, dstCred,
+			"", i.dstRepository, "pull", "push", "*")
+	}
 	if err != nil {
 		i.logger.Errorf("an error occurred while creating destination repository client: %v", err)
 		return "", err
@@ -163,6 +234,14 @@ func (i *Initializer) enter() (string, error) {
 		i.tags = tags
 	}
 
+	if len(i.filters) > 0 {
+		tags := models.FilterTags(i.filters, i.tags)
+		if len(tags) != len(i.tags) {
+			i.logger.Infof("tags filtered by the policy: %v -> %v", i.tags, tags)
+		}
+		i.tags = tags
+	}
+
 	i.logger.Infof("initialization completed: project: %s, repository: %s, tags: %v, source URL: %s, destination URL: %s, insecure: %v, destination user: %s",
 		i.project, i.repository, i.tags, i.srcURL, i.dstURL, i.insecure, i.dstUsr)
 
@@ -176,6 +255,7 @@ type Checker struct {
 
 // Enter check existence of project, if it does not exist, create it,
 // if it exists, check whether the user has write privilege to it.
+// When pulling, it only checks the existence of the local project.
 func (c *Checker) Enter() (string, error) {
 	state, err := c.enter()
 	if err != nil && retry(err) {
@@ -193,6 +273,15 @@ func (c *Checker) enter() (string, error) {
 		return "", err
 	}
 
+	// when pulling, the destination is the local project which must exist
+	if c.pull {
+		if project == nil {
+			c.logger.Errorf("project %s does not exist", c.project)
+			return "", fmt.Errorf("project %s does not exist", c.project)
+		}
+		return StatePullManifest, nil
+	}
+
 	err = c.createProject(project.Public)
 	if err == nil {
 		c.logger.Infof("project %s is created on %s with user %s", c.project, c.dstURL, c.dstUsr)
@@ -295,14 +384,45 @@ func (m *ManifestPuller) enter() (string, error) {
 	name := m.repository
 	tag := m.tags[0]
 
+	// replicating the manifests referenced by a manifest list
+	if m.manifestList != nil {
+		if len(m.children) == 0 {
+			// nothing to transfer, the pusher pushes the list directly
+			return StateTransferBlob, nil
+		}
+		child := m.children[0]
+		_, exist, err := m.dstClient.ManifestExist(child)
+		if err != nil {
+			m.logger.Errorf("an error occurred while checking the existence of manifest %s of %s:%s on %s: %v", child, name, tag, m.dstURL, err)
+			return "", err
+		}
+		if exist {
+			m.logger.Infof("manifest %s of %s:%s already exists in %s", child, name, tag, m.dstURL)
+			m.children = m.children[1:]
+			return m.enter()
+		}
+		return m.pull(child, false)
+	}
+
+	return m.pull(tag, true)
+}
+
+// pull pulls the manifest of the reference, a manifest list is only accepted
+// when the reference is the tag
+func (m *ManifestPuller) pull(reference string, acceptList bool) (string, error) {
+	name := m.repository
+	tag := m.tags[0]
+
 	acceptMediaTypes := []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest}
-	digest, mediaType, payload, err := m.srcClient.PullManifest(tag, acceptMediaTypes)
+	if acceptList {
+		acceptMediaTypes = append(acceptMediaTypes, registry.MediaTypeManifestList)
+	}
+	digest, mediaType, payload, err := m.srcClient.PullManifest(reference, acceptMediaTypes)
 	if err != nil {
-		m.logger.Errorf("an error occurred while pulling manifest of %s:%s from %s: %v", name, tag, m.srcURL, err)
+		m.logger.Errorf("an error occurred while pulling manifest of %s:%s from %s: %v", name, reference, m.srcURL, err)
 		return "", err
 	}
-	m.digest = digest
-	m.logger.Infof("manifest of %s:%s pulled successfully from %s: %s", name, tag, m.srcURL, digest)
+	m.logger.Infof("manifest of %s:%s pulled successfully from %s: %s", name, reference, m.srcURL, digest)
 
 	if strings.Contains(mediaType, "application/json") {
 		mediaType = schema1.MediaTypeManifest
@@ -310,10 +430,15 @@ func (m *ManifestPuller) enter() (string, error) {
 
 	manifest, _, err := registry.UnMarshal(mediaType, payload)
 	if err != nil {
-		m.logger.Errorf("an error occurred while parsing manifest of %s:%s from %s: %v", name, tag, m.srcURL, err)
+		m.logger.Errorf("an error occurred while parsing manifest of %s:%s from %s: %v", name, reference, m.srcURL, err)
 		return "", err
 	}
 
+	if mediaType == registry.MediaTypeManifestList {
+		return m.enterList(digest, manifest)
+	}
+
+	m.digest = digest
 	m.manifest = manifest
 
 	// all blobs(layers and config)
@@ -323,7 +448,7 @@ func (m *ManifestPuller) enter() (string, error) {
 		blobs = append(blobs, discriptor.Digest.String())
 	}
 
-	m.logger.Infof("all blobs of %s:%s from %s: %v", name, tag, m.srcURL, blobs)
+	m.logger.Infof("all blobs of %s:%s from %s: %v", name, reference, m.srcURL, blobs)
 
 	for _, blob := range blobs {
 		exist, ok := m.blobsExistence[blob]
@@ -339,17 +464,49 @@ func (m *ManifestPuller) enter() (string, error) {
 		if !exist {
 			m.blobs = append(m.blobs, blob)
 		} else {
+			locations.add(m.dstURL, blob, m.dstRepository)
 			m.logger.Infof("blob %s of %s:%s already exists in %s", blob, name, tag, m.dstURL)
 		}
 	}
-	m.logger.Infof("blobs of %s:%s need to be transferred to %s: %v", name, tag, m.dstURL, m.blobs)
+	m.logger.Infof("blobs of %s:%s need to be transferred to %s: %v", name, reference, m.dstURL, m.blobs)
 
 	return StateTransferBlob, nil
 }
 
+// enterList records the manifest list of tags[0] and starts replicating the
+// manifests it references
+func (m *ManifestPuller) enterList(digest string, list distribution.Manifest) (string, error) {
+	name := m.repository
+	tag := m.tags[0]
+
+	dstDigest, exist, err := m.dstClient.ManifestExist(tag)
+	if err != nil {
+		m.logger.Errorf("an error occurred while checking the existence of manifest of %s:%s on %s: %v", name, tag, m.dstURL, err)
+		return "", err
+	}
+	if exist && dstDigest == digest {
+		m.logger.Infof("manifest list of %s:%s exists on destination registry %s, skip it", name, tag, m.dstURL)
+		m.tags = m.tags[1:]
+		return m.enter()
+	}
+
+	m.manifestList = list
+	m.listDigest = digest
+	m.children = []string{}
+	for _, descriptor := range list.References() {
+		m.children = append(m.children, descriptor.Digest.String())
+	}
+	m.logger.Infof("%s:%s references a manifest list, manifests in it: %v", name, tag, m.children)
+
+	return m.enter()
+}
+
 // BlobTransfer transfers blobs of a tag
 type BlobTransfer struct {
 	*BaseHandler
+	// ChunkSize is the size in bytes of the chunks in which the blobs are
+	// uploaded, the blob is uploaded in one request if it is not positive
+	ChunkSize int64
 }
 
 // Enter pulls blobs and then pushs them to destination registry.
@@ -368,25 +525,127 @@ func (b *BlobTransfer) enter() (string, error) {
 	name := b.repository
 	tag := b.tags[0]
 	for _, blob := range b.blobs {
-		b.logger.Infof("transferring blob %s of %s:%s to %s ...", blob, name, tag, b.dstURL)
-		size, data, err := b.srcClient.PullBlob(blob)
-		if err != nil {
-			b.logger.Errorf("an error occurred while pulling blob %s of %s:%s from %s: %v", blob, name, tag, b.srcURL, err)
-			return "", err
+		if b.mount(blob) {
+			locations.add(b.dstURL, blob, b.dstRepository)
+			continue
 		}
-		if data != nil {
-			defer data.Close()
+
+		b.logger.Infof("transferring blob %s of %s:%s to %s ...", blob, name, tag, b.dstURL)
+		var err error
+		if b.ChunkSize > 0 {
+			err = b.transferInChunks(blob)
+		} else {
+			err = b.transfer(blob)
 		}
-		if err = b.dstClient.PushBlob(blob, size, data); err != nil {
-			b.logger.Errorf("an error occurred while pushing blob %s of %s:%s to %s : %v", blob, name, tag, b.dstURL, err)
+		if err != nil {
 			return "", err
 		}
+		locations.add(b.dstURL, blob, b.dstRepository)
 		b.logger.Infof("blob %s of %s:%s transferred to %s completed", blob, name, tag, b.dstURL)
 	}
 
 	return StatePushManifest, nil
 }
 
+// mount tries to mount the blob from the other repositories on the destination
+// registry which are known to contain it, the blob needs to be transferred if
+// it returns false
+func (b *BlobTransfer) mount(blob string) bool {
+	for _, from := range locations.get(b.dstURL, blob, b.dstRepository) {
+		mounted, err := b.dstClient.MountBlob(blob, from)
+		if err != nil {
+			b.logger.Warningf("an error occurred while mounting blob %s from %s on %s: %v", blob, from, b.dstURL, err)
+			continue
+		}
+		if mounted {
+			b.logger.Infof("blob %s mounted from %s on %s", blob, from, b.dstURL)
+			return true
+		}
+		locations.remove(b.dstURL, blob, from)
+	}
+	return false
+}
+
+// transfer pulls the blob and pushes it in one request
+func (b *BlobTransfer) transfer(blob string) error {
+	size, data, err := b.srcClient.PullBlob(blob)
+	if err != nil {
+		b.logger.Errorf("an error occurred while pulling blob %s from %s: %v", blob, b.srcURL, err)
+		return err
+	}
+	if data != nil {
+		defer data.Close()
+	}
+	if err = b.dstClient.PushBlob(blob, size, data); err != nil {
+		b.logger.Errorf("an error occurred while pushing blob %s to %s : %v", blob, b.dstURL, err)
+		return err
+	}
+	return nil
+}
+
+// transferInChunks uploads the blob in chunks, when the transfer is interrupted,
+// it gets the offset the destination registry has received and resumes both
+// the download and the upload from the offset
+func (b *BlobTransfer) transferInChunks(blob string) error {
+	location, err := b.dstClient.InitiateBlobUpload()
+	if err != nil {
+		b.logger.Errorf("an error occurred while initiating upload of blob %s to %s: %v", blob, b.dstURL, err)
+		return err
+	}
+
+	var offset int64
+	for resumes := 0; ; resumes++ {
+		location, offset, err = b.pullAndPushChunks(blob, location, offset)
+		if err == nil {
+			break
+		}
+		if resumes >= maxBlobTransferResumes {
+			b.logger.Errorf("an error occurred while transferring blob %s to %s: %v", blob, b.dstURL, err)
+			return err
+		}
+		b.logger.Warningf("transfer of blob %s interrupted at offset %d: %v, resuming...", blob, offset, err)
+
+		location, offset, err = b.dstClient.BlobUploadStatus(location)
+		if err != nil {
+			b.logger.Errorf("an error occurred while getting upload status of blob %s from %s: %v", blob, b.dstURL, err)
+			return err
+		}
+	}
+
+	if err = b.dstClient.CompleteBlobUpload(location, blob); err != nil {
+		b.logger.Errorf("an error occurred while completing upload of blob %s to %s: %v", blob, b.dstURL, err)
+		return err
+	}
+	return nil
+}
+
+// pullAndPushChunks pulls the blob starting at offset and pushes it chunk by
+// chunk, it returns the location of the upload session and the offset of the
+// last chunk received by the destination registry
+func (b *BlobTransfer) pullAndPushChunks(blob, location string, offset int64) (string, int64, error) {
+	size, data, err := b.srcClient.PullBlobFrom(blob, offset)
+	if err != nil {
+		return location, offset, err
+	}
+	defer data.Close()
+
+	for offset < size {
+		length := b.ChunkSize
+		if size-offset < length {
+			length = size - offset
+		}
+		next, nextOffset, err := b.dstClient.PushBlobChunk(location, offset,
+			length, io.LimitReader(data, length))
+		if err != nil {
+			return location, offset, err
+		}
+		location, offset = next, nextOffset
+		b.logger.Infof("%d/%d bytes of blob %s transferred", offset, size, blob)
+	}
+
+	return location, offset, nil
+}
+
 // ManifestPusher pushs the manifest to destination registry
 type ManifestPusher struct {
 	*BaseHandler
@@ -407,6 +666,10 @@ func (m *ManifestPusher) Enter() (string, error) {
 }
 
 func (m *ManifestPusher) enter() (string, error) {
+	if m.manifestList != nil {
+		return m.enterList()
+	}
+
 	name := m.repository
 	tag := m.tags[0]
 	_, exist, err := m.srcClient.ManifestExist(tag)
@@ -451,3 +714,50 @@ func (m *ManifestPusher) enter() (string, error) {
 
 	return StatePullManifest, nil
 }
+
+// enterList pushes the manifest referenced by the manifest list by its digest,
+// and pushes the list by tag after all the manifests it references are pushed
+func (m *ManifestPusher) enterList() (string, error) {
+	name := m.repository
+	tag := m.tags[0]
+
+	if m.manifest != nil {
+		mediaType, data, err := m.manifest.Payload()
+		if err != nil {
+			m.logger.Errorf("an error occurred while getting payload of manifest %s of %s:%s : %v", m.digest, name, tag, err)
+			return "", err
+		}
+		if _, err = m.dstClient.PushManifest(m.digest, mediaType, data); err != nil {
+			m.logger.Errorf("an error occurred while pushing manifest %s of %s:%s to %s : %v", m.digest, name, tag, m.dstURL, err)
+			return "", err
+		}
+		m.logger.Infof("manifest %s of %s:%s has been pushed to %s", m.digest, name, tag, m.dstURL)
+
+		m.children = m.children[1:]
+		m.manifest = nil
+		m.digest = ""
+		m.blobs = nil
+	}
+
+	if len(m.children) > 0 {
+		return StatePullManifest, nil
+	}
+
+	mediaType, data, err := m.manifestList.Payload()
+	if err != nil {
+		m.logger.Errorf("an error occurred while getting payload of manifest list for %s:%s : %v", name, tag, err)
+		return "", err
+	}
+	if _, err = m.dstClient.PushManifest(tag, mediaType, data); err != nil {
+		m.logger.Errorf("an error occurred while pushing manifest list of %s:%s to %s : %v", name, tag, m.dstURL, err)
+		return "", err
+	}
+	m.logger.Infof("manifest list of %s:%s has been pushed to %s", name, tag, m.dstURL)
+
+	m.tags = m.tags[1:]
+	m.manifestList = nil
+	m.listDigest = ""
+	m.children = nil
+
+	return StatePullManifest, nil
+}
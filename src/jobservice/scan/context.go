@@ -15,17 +15,20 @@
 package scan
 
 import (
-	"github.com/vmware/harbor/src/common/models"
-	"github.com/vmware/harbor/src/common/utils/clair"
+	"time"
+
 	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/common/utils/scanner"
 )
 
 const (
 	// StateInitialize in this state the handler will initialize the job context.
 	StateInitialize = "initialize"
-	// StateScanLayer in this state the handler will POST layer  of clair to scan layer by layer of the image.
-	StateScanLayer = "scanlayer"
-	// StateSummarize in this state, the layers are scanned by clair it will call clair API to update vulnerability overview in Harbor DB. After this state, the job is finished.
+	// StateSubmit in this state the handler will submit the image to the scanner.
+	StateSubmit = "submit"
+	// StatePoll in this state the handler will poll the scanner until the report of the image is ready.
+	StatePoll = "poll"
+	// StateSummarize in this state, the image is scanned and the handler will update vulnerability overview in Harbor DB. After this state, the job is finished.
 	StateSummarize = "summarize"
 )
 
@@ -35,11 +38,14 @@ type JobContext struct {
 	Repository string
 	Tag        string
 	Digest     string
-	//The array of data object to set as request body for layer scan.
-	layers  []models.ClairLayer
-	current int
-	//token for accessing the registry
-	token       string
-	clairClient *clair.Client
-	Logger      *log.Logger
+	//The image to submit to the scanner.
+	image *scanner.Image
+	//The scanner and its kind which is recorded with the key of the report.
+	scanner     scanner.Scanner
+	scannerKind string
+	//The key of the report returned by the scanner and the time when the image was submitted.
+	reportKey string
+	submitted time.Time
+	report    *scanner.Report
+	Logger    *log.Logger
 }
@@ -19,13 +19,21 @@ import (
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
-	"github.com/vmware/harbor/src/common/utils/clair"
 	"github.com/vmware/harbor/src/common/utils/registry/auth"
+	"github.com/vmware/harbor/src/common/utils/scanner"
 	"github.com/vmware/harbor/src/jobservice/config"
 	"github.com/vmware/harbor/src/jobservice/utils"
 
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// the interval between polling the report and how long to wait for it
+	pollInterval = 5 * time.Second
+	pollTimeout  = 30 * time.Minute
 )
 
 // Initializer will handle the initialise state pull the manifest, prepare token.
@@ -65,30 +73,40 @@ func (iz *Initializer) Enter() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	iz.Context.token = tk
-	iz.Context.clairClient = clair.NewClient(config.ClairEndpoint(), logger)
-	iz.prepareLayers(regURL, manifest.References())
-	return StateScanLayer, nil
+	kind, endpoint, err := config.Scanner()
+	if err != nil {
+		logger.Errorf("Failed to read the configuration of scanner, error: %v", err)
+		return "", err
+	}
+	logger.Infof("Scanner: %s, endpoint: %s", kind, endpoint)
+	iz.Context.scannerKind = kind
+	iz.Context.scanner, err = scanner.New(kind, endpoint, logger)
+	if err != nil {
+		logger.Errorf("Failed to create the scanner, error: %v", err)
+		return "", err
+	}
+	iz.Context.image = iz.buildImage(regURL, tk, manifest.References())
+	return StateSubmit, nil
 }
 
-func (iz *Initializer) prepareLayers(registryEndpoint string, descriptors []distribution.Descriptor) {
-	//	logger := iz.Context.Logger
-	tokenHeader := map[string]string{"Authorization": fmt.Sprintf("Bearer %s", iz.Context.token)}
+func (iz *Initializer) buildImage(registryEndpoint, token string, descriptors []distribution.Descriptor) *scanner.Image {
+	img := &scanner.Image{
+		ID:          strconv.FormatInt(iz.Context.JobID, 10),
+		Repository:  iz.Context.Repository,
+		Digest:      iz.Context.Digest,
+		ManifestURL: utils.BuildManifestURL(registryEndpoint, iz.Context.Repository, iz.Context.Digest),
+		Headers:     map[string]string{"Authorization": fmt.Sprintf("Bearer %s", token)},
+	}
 	for _, d := range descriptors {
 		if d.MediaType == schema2.MediaTypeConfig {
 			continue
 		}
-		l := models.ClairLayer{
-			Name:    fmt.Sprintf("%d-%s", iz.Context.JobID, d.Digest),
-			Headers: tokenHeader,
-			Format:  "Docker",
-			Path:    utils.BuildBlobURL(registryEndpoint, iz.Context.Repository, string(d.Digest)),
-		}
-		if len(iz.Context.layers) > 0 {
-			l.ParentName = iz.Context.layers[len(iz.Context.layers)-1].Name
-		}
-		iz.Context.layers = append(iz.Context.layers, l)
+		img.Layers = append(img.Layers, &scanner.Layer{
+			Digest: string(d.Digest),
+			URL:    utils.BuildBlobURL(registryEndpoint, iz.Context.Repository, string(d.Digest)),
+		})
 	}
+	return img
 }
 
 // Exit ...
@@ -96,35 +114,62 @@ func (iz *Initializer) Exit() error {
 	return nil
 }
 
-//LayerScanHandler will call clair API to trigger scanning.
-type LayerScanHandler struct {
+// Submitter submits the image to the scanner.
+type Submitter struct {
 	Context *JobContext
 }
 
 // Enter ...
-func (ls *LayerScanHandler) Enter() (string, error) {
-	logger := ls.Context.Logger
-	currentLayer := ls.Context.layers[ls.Context.current]
-	logger.Infof("Entered scan layer handler, current: %d, layer name: %s", ls.Context.current, currentLayer.Name)
-	err := ls.Context.clairClient.ScanLayer(currentLayer)
+func (sm *Submitter) Enter() (string, error) {
+	logger := sm.Context.Logger
+	logger.Infof("Entered submitter, layers: %d", len(sm.Context.image.Layers))
+	key, err := sm.Context.scanner.Submit(sm.Context.image)
 	if err != nil {
-		logger.Errorf("Unexpected error: %v", err)
+		logger.Errorf("Failed to submit the image to the scanner, error: %v", err)
 		return "", err
 	}
-	ls.Context.current++
-	if ls.Context.current == len(ls.Context.layers) {
-		return StateSummarize, nil
+	logger.Infof("Submitted, the key of the report: %s", key)
+	sm.Context.reportKey = key
+	sm.Context.submitted = time.Now()
+	return StatePoll, nil
+}
+
+// Exit ...
+func (sm *Submitter) Exit() error {
+	return nil
+}
+
+// ReportPoller polls the scanner until the report of the image is ready.
+type ReportPoller struct {
+	Context *JobContext
+}
+
+// Enter ...
+func (rp *ReportPoller) Enter() (string, error) {
+	logger := rp.Context.Logger
+	report, err := rp.Context.scanner.GetReport(rp.Context.reportKey)
+	if err == scanner.ErrReportNotReady {
+		if time.Since(rp.Context.submitted) > pollTimeout {
+			logger.Errorf("The report is not ready after %v", pollTimeout)
+			return "", fmt.Errorf("timeout waiting for the report %s", rp.Context.reportKey)
+		}
+		time.Sleep(pollInterval)
+		return StatePoll, nil
+	}
+	if err != nil {
+		logger.Errorf("Failed to get the report from the scanner, error: %v", err)
+		return "", err
 	}
-	logger.Infof("After scanning, return with next state: %s", StateScanLayer)
-	return StateScanLayer, nil
+	rp.Context.report = report
+	return StateSummarize, nil
 }
 
 // Exit ...
-func (ls *LayerScanHandler) Exit() error {
+func (rp *ReportPoller) Exit() error {
 	return nil
 }
 
-// SummarizeHandler will summarize the vulnerability and feature information of Clair, and store into Harbor's DB.
+// SummarizeHandler will summarize the vulnerability and component information of the report, and store into Harbor's DB.
 type SummarizeHandler struct {
 	Context *JobContext
 }
@@ -133,46 +178,14 @@ type SummarizeHandler struct {
 func (sh *SummarizeHandler) Enter() (string, error) {
 	logger := sh.Context.Logger
 	logger.Infof("Entered summarize handler")
-	layerName := sh.Context.layers[len(sh.Context.layers)-1].Name
-	logger.Infof("Top layer's name: %s, will use it to get the vulnerability result of image", layerName)
-	res, err := sh.Context.clairClient.GetResult(layerName)
-	if err != nil {
-		logger.Errorf("Failed to get result from Clair, error: %v", err)
+	report := sh.Context.report
+	logger.Infof("total components: %d, vulnerabilities: %d", report.Components, len(report.Vulnerabilities))
+	overallSev, compOverview := report.Overview()
+	if err := dao.UpdateImgScanOverview(sh.Context.Digest, sh.Context.scannerKind, sh.Context.reportKey, overallSev, compOverview,
+		report.Severities()); err != nil {
+		logger.Errorf("Failed to update the scan overview, error: %v", err)
 		return "", err
 	}
-	vulnMap := make(map[models.Severity]int)
-	features := res.Layer.Features
-	totalComponents := len(features)
-	logger.Infof("total features: %d", totalComponents)
-	var temp models.Severity
-	for _, f := range features {
-		sev := models.SevNone
-		for _, v := range f.Vulnerabilities {
-			temp = clair.ParseClairSev(v.Severity)
-			if temp > sev {
-				sev = temp
-			}
-		}
-		logger.Infof("Feature: %s, Severity: %d", f.Name, sev)
-		vulnMap[sev]++
-	}
-	overallSev := models.SevNone
-	compSummary := []*models.ComponentsOverviewEntry{}
-	for k, v := range vulnMap {
-		if k > overallSev {
-			overallSev = k
-		}
-		entry := &models.ComponentsOverviewEntry{
-			Sev:   int(k),
-			Count: v,
-		}
-		compSummary = append(compSummary, entry)
-	}
-	compOverview := &models.ComponentsOverview{
-		Total:   totalComponents,
-		Summary: compSummary,
-	}
-	err = dao.UpdateImgScanOverview(sh.Context.Digest, layerName, overallSev, compOverview)
 	return models.JobFinished, nil
 }
 
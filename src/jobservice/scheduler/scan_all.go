@@ -0,0 +1,119 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"time"
+
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/utils/cron"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// the cron strings of the scan all policies
+var scanAllSpecs = map[string]string{
+	common.ScanAllPolicyDaily:  "@daily",
+	common.ScanAllPolicyWeekly: "@weekly",
+}
+
+// PolicyFunc returns the policy of scanning all images
+type PolicyFunc func() (string, error)
+
+// LastRunFunc returns the start time of the last run of scanning all images,
+// it is zero if the images have never been scanned
+type LastRunFunc func() (time.Time, error)
+
+// ScanAllFunc starts to scan all images
+type ScanAllFunc func() error
+
+// ScanAllScheduler checks the scan all policy periodically and scans all
+// images when they are due. The policy is read every time, so the change of
+// it takes effect without restarting the job service.
+type ScanAllScheduler struct {
+	interval time.Duration
+	policy   PolicyFunc
+	lastRun  LastRunFunc
+	scanAll  ScanAllFunc
+	// current is the policy read in the last check and since is the time
+	// when it was read for the first time, the runs scheduled before it
+	// are skipped
+	current string
+	since   time.Time
+}
+
+// NewScanAllScheduler returns an instance of ScanAllScheduler which checks
+// the policy every interval
+func NewScanAllScheduler(interval time.Duration, policy PolicyFunc,
+	lastRun LastRunFunc, scanAll ScanAllFunc) *ScanAllScheduler {
+	return &ScanAllScheduler{
+		interval: interval,
+		policy:   policy,
+		lastRun:  lastRun,
+		scanAll:  scanAll,
+	}
+}
+
+// Start checks the policy periodically, it never returns
+func (s *ScanAllScheduler) Start() {
+	log.Infof("scan all scheduler started, interval: %v", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.check(time.Now())
+		<-ticker.C
+	}
+}
+
+func (s *ScanAllScheduler) check(now time.Time) {
+	policy, err := s.policy()
+	if err != nil {
+		log.Errorf("failed to get scan all policy: %v", err)
+		return
+	}
+	if policy != s.current {
+		s.current = policy
+		s.since = now
+	}
+
+	spec, ok := scanAllSpecs[policy]
+	if !ok {
+		return
+	}
+	sched, err := cron.Parse(spec)
+	if err != nil {
+		log.Errorf("failed to parse cron string %q of scan all policy %s: %v", spec, policy, err)
+		return
+	}
+
+	last, err := s.lastRun()
+	if err != nil {
+		log.Errorf("failed to get the last run of scanning all images: %v", err)
+		return
+	}
+	if last.Before(s.since) {
+		last = s.since
+	}
+	if now.Before(sched.Next(last)) {
+		return
+	}
+
+	log.Infof("triggering scheduled scan of all images, policy: %s", policy)
+	if err := s.scanAll(); err != nil {
+		log.Errorf("failed to trigger scheduled scan of all images: %v", err)
+	}
+	// the run is not retried if it fails, e.g. the images are being scanned
+	// when it is due, the next one is scheduled according to the policy
+	s.since = now
+}
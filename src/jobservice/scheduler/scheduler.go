@@ -0,0 +1,121 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/cron"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// the length of column schedule_error in table replication_policy
+const maxScheduleErrorLen = 1024
+
+// SyncFunc replicates all the repositories of the project the policy belongs to
+type SyncFunc func(*models.RepPolicy) error
+
+// Scheduler checks the cron strings of the enabled replication policies
+// periodically and triggers a full sync of the project when a policy is due.
+// The next run time is persisted in DB, so the schedule survives restarts
+// of the job service.
+type Scheduler struct {
+	interval time.Duration
+	sync     SyncFunc
+}
+
+// NewScheduler returns an instance of Scheduler which checks the policies
+// every interval
+func NewScheduler(interval time.Duration, sync SyncFunc) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		sync:     sync,
+	}
+}
+
+// Start checks the policies periodically, it never returns
+func (s *Scheduler) Start() {
+	log.Infof("replication scheduler started, interval: %v", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.check(time.Now())
+		<-ticker.C
+	}
+}
+
+func (s *Scheduler) check(now time.Time) {
+	policies, err := dao.GetScheduledRepPolicies()
+	if err != nil {
+		log.Errorf("failed to get scheduled replication policies: %v", err)
+		return
+	}
+
+	for _, policy := range policies {
+		last, next, schedErr := s.schedule(policy, now)
+		if last.Equal(policy.LastRunTime) && next.Equal(policy.NextRunTime) &&
+			schedErr == policy.ScheduleError {
+			continue
+		}
+		if err := dao.UpdateRepPolicySchedule(policy.ID, last, next, schedErr); err != nil {
+			log.Errorf("failed to update schedule of policy %d: %v", policy.ID, err)
+		}
+	}
+}
+
+// schedule triggers the sync if the policy is due and returns the last run
+// time, the next run time and the scheduling error of the policy
+func (s *Scheduler) schedule(policy *models.RepPolicy, now time.Time) (time.Time, time.Time, string) {
+	last := policy.LastRunTime
+	sched, err := cron.Parse(policy.CronStr)
+	if err != nil {
+		return last, time.Time{}, truncate(fmt.Sprintf("invalid cron string %q: %v", policy.CronStr, err))
+	}
+
+	next := policy.NextRunTime
+	// the next run time is computed for the first time, or it was computed
+	// before the policy is disabled and enabled again, in the later case the
+	// replication has been triggered by the enablement
+	if next.IsZero() || next.Before(policy.StartTime) {
+		next = sched.Next(now)
+		if next.IsZero() {
+			return last, next, truncate(fmt.Sprintf("cron string %q never matches", policy.CronStr))
+		}
+		return last, next, policy.ScheduleError
+	}
+
+	if now.Before(next) {
+		return last, next, policy.ScheduleError
+	}
+
+	log.Infof("triggering scheduled replication of policy %d, cron string: %s", policy.ID, policy.CronStr)
+	schedErr := ""
+	if err := s.sync(policy); err != nil {
+		log.Errorf("failed to trigger scheduled replication of policy %d: %v", policy.ID, err)
+		schedErr = truncate(err.Error())
+	}
+
+	return now, sched.Next(now), schedErr
+}
+
+func truncate(msg string) string {
+	if len(msg) > maxScheduleErrorLen {
+		return msg[:maxScheduleErrorLen]
+	}
+	return msg
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestSchedule(t *testing.T) {
+	triggered := 0
+	var syncErr error
+	s := NewScheduler(time.Minute, func(*models.RepPolicy) error {
+		triggered++
+		return syncErr
+	})
+
+	now := time.Date(2017, 8, 1, 10, 30, 20, 0, time.UTC)
+	policy := &models.RepPolicy{
+		ID:        1,
+		CronStr:   "0 * * * *",
+		StartTime: now.Add(-time.Hour),
+	}
+
+	// the next run time is computed for the first time
+	last, next, schedErr := s.schedule(policy, now)
+	assert.True(t, last.IsZero())
+	assert.Equal(t, time.Date(2017, 8, 1, 11, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, "", schedErr)
+	assert.Equal(t, 0, triggered)
+
+	// not due yet
+	policy.NextRunTime = next
+	_, next, _ = s.schedule(policy, now.Add(10*time.Minute))
+	assert.Equal(t, policy.NextRunTime, next)
+	assert.Equal(t, 0, triggered)
+
+	// due
+	due := time.Date(2017, 8, 1, 11, 0, 5, 0, time.UTC)
+	last, next, schedErr = s.schedule(policy, due)
+	assert.Equal(t, due, last)
+	assert.Equal(t, time.Date(2017, 8, 1, 12, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, "", schedErr)
+	assert.Equal(t, 1, triggered)
+
+	// the sync fails
+	syncErr = errors.New("unreachable")
+	policy.NextRunTime = next
+	due = next.Add(time.Second)
+	last, _, schedErr = s.schedule(policy, due)
+	assert.Equal(t, due, last)
+	assert.Equal(t, "unreachable", schedErr)
+	assert.Equal(t, 2, triggered)
+
+	// the policy was enabled again after the next run time was computed
+	policy.StartTime = due.Add(time.Hour)
+	_, next, _ = s.schedule(policy, due.Add(time.Hour))
+	assert.Equal(t, time.Date(2017, 8, 1, 14, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, 2, triggered)
+
+	// invalid cron string
+	policy.CronStr = "invalid"
+	_, next, schedErr = s.schedule(policy, now)
+	assert.True(t, next.IsZero())
+	assert.NotEqual(t, "", schedErr)
+}
+
+func TestScanAllSchedule(t *testing.T) {
+	policy := "none"
+	var lastRun time.Time
+	triggered := 0
+	var scanErr error
+	s := NewScanAllScheduler(time.Minute,
+		func() (string, error) {
+			return policy, nil
+		},
+		func() (time.Time, error) {
+			return lastRun, nil
+		},
+		func() error {
+			triggered++
+			return scanErr
+		})
+
+	// no policy
+	now := time.Date(2017, 8, 1, 23, 30, 0, 0, time.UTC)
+	s.check(now)
+	assert.Equal(t, 0, triggered)
+
+	// the policy is set, the images are scanned at midnight
+	policy = "daily"
+	s.check(now)
+	assert.Equal(t, 0, triggered)
+	s.check(time.Date(2017, 8, 1, 23, 59, 0, 0, time.UTC))
+	assert.Equal(t, 0, triggered)
+	midnight := time.Date(2017, 8, 2, 0, 0, 10, 0, time.UTC)
+	s.check(midnight)
+	assert.Equal(t, 1, triggered)
+
+	// the run is recorded, not due until the next midnight
+	lastRun = midnight
+	s.check(time.Date(2017, 8, 2, 0, 1, 0, 0, time.UTC))
+	s.check(time.Date(2017, 8, 2, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, 1, triggered)
+
+	// the failed run is not retried
+	scanErr = errors.New("all images are being scanned")
+	s.check(time.Date(2017, 8, 3, 0, 0, 10, 0, time.UTC))
+	assert.Equal(t, 2, triggered)
+	s.check(time.Date(2017, 8, 3, 0, 1, 10, 0, time.UTC))
+	assert.Equal(t, 2, triggered)
+
+	// weekly, the next Sunday is 2017-08-06
+	scanErr = nil
+	policy = "weekly"
+	s.check(time.Date(2017, 8, 4, 0, 0, 10, 0, time.UTC))
+	s.check(time.Date(2017, 8, 5, 23, 59, 0, 0, time.UTC))
+	assert.Equal(t, 2, triggered)
+	s.check(time.Date(2017, 8, 6, 0, 0, 10, 0, time.UTC))
+	assert.Equal(t, 3, triggered)
+}
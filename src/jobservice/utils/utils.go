@@ -49,6 +49,28 @@ func NewRepositoryClient(endpoint string, insecure bool, credential auth.Credent
 	return client, nil
 }
 
+//NewRegistryClient create a registry client with the scope type and scope name specified.
+func NewRegistryClient(endpoint string, insecure bool, credential auth.Credential,
+	tokenServiceEndpoint, scopeType, scopeName string, actions ...string) (*registry.Registry, error) {
+	authorizer := auth.NewStandardTokenAuthorizer(credential, insecure,
+		tokenServiceEndpoint, scopeType, scopeName, actions...)
+
+	store, err := auth.NewAuthorizerStore(endpoint, insecure, authorizer)
+	if err != nil {
+		return nil, err
+	}
+
+	uam := &userAgentModifier{
+		userAgent: "harbor-registry-client",
+	}
+
+	client, err := registry.NewRegistryWithModifiers(endpoint, insecure, store, uam)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 type userAgentModifier struct {
 	userAgent string
 }
@@ -64,6 +86,11 @@ func BuildBlobURL(endpoint, repository, digest string) string {
 	return fmt.Sprintf("%s/v2/%s/blobs/%s", endpoint, repository, digest)
 }
 
+// BuildManifestURL ...
+func BuildManifestURL(endpoint, repository, reference string) string {
+	return fmt.Sprintf("%s/v2/%s/manifests/%s", endpoint, repository, reference)
+}
+
 //GetTokenForRepo is a temp solution for job handler to get a token for clair.
 func GetTokenForRepo(repository string) (string, error) {
 	u, err := url.Parse(config.InternalTokenServiceEndpoint())
@@ -0,0 +1,203 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/security/accesstoken"
+	"github.com/vmware/harbor/src/common/utils"
+)
+
+// AccessTokenAPI handles request to /api/users/{}/tokens/{}
+type AccessTokenAPI struct {
+	BaseController
+	userID int
+	token  *models.AccessToken
+}
+
+type accessTokenReq struct {
+	Name      string `json:"name"`
+	ReadOnly  bool   `json:"read_only"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// accessTokenResp contains the token which is only returned once when it
+// is created
+type accessTokenResp struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+// Prepare validates the URL and the user, users can manage their own
+// tokens and system admins can list and revoke the tokens of others. The
+// tokens can not be managed by the requests authenticated by tokens
+func (a *AccessTokenAPI) Prepare() {
+	a.BaseController.Prepare()
+
+	if !a.SecurityCtx.IsAuthenticated() {
+		a.HandleUnauthorized()
+		return
+	}
+	if _, ok := a.SecurityCtx.(*accesstoken.SecurityContext); ok {
+		a.RenderError(http.StatusForbidden, "access tokens can not be used to manage access tokens")
+		return
+	}
+
+	user, err := dao.GetUser(models.User{
+		Username: a.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v",
+			a.SecurityCtx.GetUsername(), err))
+		return
+	}
+	if user == nil {
+		a.HandleUnauthorized()
+		return
+	}
+
+	id := a.GetStringFromPath(":id")
+	if id == "current" {
+		a.userID = user.UserID
+	} else {
+		a.userID, err = strconv.Atoi(id)
+		if err != nil || a.userID <= 0 {
+			a.HandleBadRequest(fmt.Sprintf("invalid user ID: %s", id))
+			return
+		}
+	}
+
+	if a.userID != user.UserID {
+		if !a.SecurityCtx.IsSysAdmin() || a.Ctx.Request.Method == http.MethodPost {
+			a.HandleForbidden(a.SecurityCtx.GetUsername())
+			return
+		}
+		u, err := dao.GetUser(models.User{
+			UserID: a.userID,
+		})
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get user %d: %v", a.userID, err))
+			return
+		}
+		if u == nil {
+			a.HandleNotFound(fmt.Sprintf("user %d not found", a.userID))
+			return
+		}
+	}
+
+	if len(a.GetStringFromPath(":tid")) != 0 {
+		tid, err := a.GetInt64FromPath(":tid")
+		if err != nil || tid <= 0 {
+			a.HandleBadRequest(fmt.Sprintf("invalid token ID: %s", a.GetStringFromPath(":tid")))
+			return
+		}
+
+		token, err := dao.GetAccessToken(tid)
+		if err != nil {
+			a.HandleInternalServerError(fmt.Sprintf("failed to get access token %d: %v", tid, err))
+			return
+		}
+		if token == nil || token.UserID != a.userID {
+			a.HandleNotFound(fmt.Sprintf("access token %d not found", tid))
+			return
+		}
+		a.token = token
+	}
+}
+
+// Get returns the token specified by ID or all tokens of the user, the
+// secrets are never returned
+func (a *AccessTokenAPI) Get() {
+	if a.token != nil {
+		a.Data["json"] = a.token
+		a.ServeJSON()
+		return
+	}
+
+	tokens, err := dao.ListAccessTokens(a.userID)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to list access tokens of user %d: %v",
+			a.userID, err))
+		return
+	}
+	a.Data["json"] = tokens
+	a.ServeJSON()
+}
+
+// Post creates a token and returns it, the token can not be got again
+// after this
+func (a *AccessTokenAPI) Post() {
+	req := &accessTokenReq{}
+	a.DecodeJSONReq(req)
+
+	token := &models.AccessToken{
+		UserID:    a.userID,
+		Name:      req.Name,
+		ReadOnly:  req.ReadOnly,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := token.Valid(); err != nil {
+		a.HandleBadRequest(err.Error())
+		return
+	}
+
+	tk, err := dao.GetAccessTokenByName(a.userID, token.Name)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to get access token %s: %v", token.Name, err))
+		return
+	}
+	if tk != nil {
+		a.RenderError(http.StatusConflict, fmt.Sprintf("access token %s already exists", token.Name))
+		return
+	}
+
+	secret := utils.GenerateRandomString()
+	token.Salt = utils.GenerateRandomString()
+	token.Secret = utils.Encrypt(secret, token.Salt)
+
+	id, err := dao.AddAccessToken(token)
+	if err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to add access token %s: %v", token.Name, err))
+		return
+	}
+
+	a.Ctx.Output.Header("Location", a.Ctx.Request.RequestURI+"/"+strconv.FormatInt(id, 10))
+	a.Ctx.Output.SetStatus(http.StatusCreated)
+	a.Data["json"] = &accessTokenResp{
+		ID:    id,
+		Name:  token.Name,
+		Token: models.FormatAccessToken(id, secret),
+	}
+	a.ServeJSON()
+}
+
+// Delete revokes the token immediately
+func (a *AccessTokenAPI) Delete() {
+	if a.token == nil {
+		a.HandleBadRequest("token ID is required")
+		return
+	}
+
+	if err := dao.DeleteAccessToken(a.token.ID); err != nil {
+		a.HandleInternalServerError(fmt.Sprintf("failed to delete access token %d: %v", a.token.ID, err))
+		return
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessTokenAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+	token := &accessTokenReq{
+		Name:     "token_for_test_api",
+		ReadOnly: true,
+	}
+
+	// 401
+	code, _, err := apiTest.AddAccessToken(*unknownUsr, token)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 400
+	code, _, err = apiTest.AddAccessToken(*testUser, &accessTokenReq{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 201
+	code, body, err := apiTest.AddAccessToken(*testUser, token)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	resp := &accessTokenResp{}
+	require.Nil(t, json.Unmarshal(body, resp))
+	require.True(t, len(resp.Token) > 0)
+	id := strconv.FormatInt(resp.ID, 10)
+
+	// 409
+	code, _, err = apiTest.AddAccessToken(*testUser, token)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// the token can be used to read
+	code, _, err = apiTest.RequestWithAccessToken(http.MethodGet, "/api/projects/", resp.Token)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	// the token is read-only
+	code, _, err = apiTest.RequestWithAccessToken(http.MethodPost, "/api/projects/", resp.Token)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// the token can not be used to manage tokens
+	code, _, err = apiTest.RequestWithAccessToken(http.MethodGet, "/api/users/current/tokens", resp.Token)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// the token can not be used to get the CLI secret or manage two-factor authentication
+	for _, path := range []string{"/api/users/current/cli_secret", "/api/users/current/totp"} {
+		code, _, err = apiTest.RequestWithAccessToken(http.MethodGet, path, resp.Token)
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusForbidden, code)
+	}
+
+	// list, the last used time is recorded
+	code, tokens, err := apiTest.ListAccessTokens(*testUser)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, len(tokens))
+	assert.Equal(t, token.Name, tokens[0].Name)
+	assert.True(t, tokens[0].ReadOnly)
+	assert.True(t, tokens[0].LastUsedAt > 0)
+
+	// the tokens of other users can not be revoked
+	code, err = apiTest.DeleteAccessToken(*admin, id)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// revoke
+	code, err = apiTest.DeleteAccessToken(*testUser, id)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _, err = apiTest.RequestWithAccessToken(http.MethodGet, "/api/users/current", resp.Token)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
@@ -51,3 +51,12 @@ func (b *BaseController) Prepare() {
 	}
 	b.ProjectMgr = pm
 }
+
+// sessionID returns the ID of the session of UI the request is sent with,
+// it is empty if the session is not started
+func (b *BaseController) sessionID() string {
+	if b.Ctx.Input.CruSession == nil {
+		return ""
+	}
+	return b.Ctx.Input.CruSession.SessionID()
+}
@@ -17,7 +17,9 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/dao"
@@ -46,6 +48,11 @@ var (
 		common.LDAPFilter,
 		common.LDAPScope,
 		common.LDAPTimeout,
+		common.LDAPGroupBaseDN,
+		common.LDAPGroupFilter,
+		common.LDAPGroupNameAttribute,
+		common.LDAPGroupScope,
+		common.LDAPGroupMembershipAttr,
 		common.TokenServiceURL,
 		common.RegistryURL,
 		common.EmailHost,
@@ -62,22 +69,62 @@ var (
 		common.CfgExpiration,
 		common.JobLogDir,
 		common.AdminInitialPassword,
+		common.RepRetryMaxAttempts,
+		common.RepRetryInitialDelay,
+		common.RepRetryMultiplier,
+		common.RepRetryJitter,
+		common.OIDCEndpoint,
+		common.OIDCClientID,
+		common.OIDCClientSecret,
+		common.OIDCScope,
+		common.OIDCGroupsClaim,
+		common.OIDCVerifyCert,
+		common.LoginMaxFailures,
+		common.LoginMaxFailuresPerIP,
+		common.LoginLockDuration,
+		common.PasswordMinLength,
+		common.PasswordRequireUppercase,
+		common.PasswordRequireLowercase,
+		common.PasswordRequireNumber,
+		common.PasswordRequireSpecial,
+		common.TOTPRequiredForAdmin,
+		common.ScanAllPolicy,
+		common.Scanner,
+		common.ScannerEndpoint,
 	}
 
 	numKeys = []string{
 		common.EmailPort,
 		common.LDAPScope,
 		common.LDAPTimeout,
+		common.LDAPGroupScope,
 		common.MySQLPort,
 		common.MaxJobWorkers,
 		common.TokenExpiration,
 		common.CfgExpiration,
+		common.RepRetryMaxAttempts,
+		common.RepRetryInitialDelay,
+		common.LoginMaxFailures,
+		common.LoginMaxFailuresPerIP,
+		common.LoginLockDuration,
+		common.PasswordMinLength,
+	}
+
+	floatKeys = []string{
+		common.RepRetryMultiplier,
+		common.RepRetryJitter,
 	}
 
 	boolKeys = []string{
 		common.EmailSSL,
 		common.SelfRegistration,
 		common.VerifyRemoteCert,
+		common.OIDCVerifyCert,
+		common.PasswordRequireUppercase,
+		common.PasswordRequireLowercase,
+		common.PasswordRequireNumber,
+		common.PasswordRequireSpecial,
+		common.TOTPRequiredForAdmin,
 	}
 
 	passwordKeys = []string{
@@ -85,6 +132,7 @@ var (
 		common.EmailPassword,
 		common.LDAPSearchPwd,
 		common.MySQLPassword,
+		common.OIDCClientSecret,
 	}
 )
 
@@ -209,8 +257,9 @@ func validateCfg(c map[string]string) (bool, error) {
 	}
 
 	if value, ok := c[common.AUTHMode]; ok {
-		if value != common.DBAuth && value != common.LDAPAuth {
-			return isSysErr, fmt.Errorf("invalid %s, shoud be %s or %s", common.AUTHMode, common.DBAuth, common.LDAPAuth)
+		if value != common.DBAuth && value != common.LDAPAuth && value != common.OIDCAuth {
+			return isSysErr, fmt.Errorf("invalid %s, shoud be %s, %s or %s", common.AUTHMode,
+				common.DBAuth, common.LDAPAuth, common.OIDCAuth)
 		}
 		mode = value
 	}
@@ -245,6 +294,39 @@ func validateCfg(c map[string]string) (bool, error) {
 		}
 	}
 
+	if mode == common.OIDCAuth {
+		oidc, err := config.OIDCSetting()
+		if err != nil {
+			isSysErr = true
+			return isSysErr, err
+		}
+
+		if len(oidc.Endpoint) == 0 {
+			if _, ok := c[common.OIDCEndpoint]; !ok {
+				return isSysErr, fmt.Errorf("%s is missing", common.OIDCEndpoint)
+			}
+		}
+		if len(oidc.ClientID) == 0 {
+			if _, ok := c[common.OIDCClientID]; !ok {
+				return isSysErr, fmt.Errorf("%s is missing", common.OIDCClientID)
+			}
+		}
+		if len(oidc.ClientSecret) == 0 {
+			if _, ok := c[common.OIDCClientSecret]; !ok {
+				return isSysErr, fmt.Errorf("%s is missing", common.OIDCClientSecret)
+			}
+		}
+	}
+
+	for _, k := range []string{common.OIDCEndpoint, common.OIDCClientID, common.OIDCClientSecret} {
+		if v, ok := c[k]; ok && len(v) == 0 {
+			return isSysErr, fmt.Errorf("%s is empty", k)
+		}
+	}
+	if scope, ok := c[common.OIDCScope]; ok && !strings.Contains(scope, "openid") {
+		return isSysErr, fmt.Errorf("%s must contain openid", common.OIDCScope)
+	}
+
 	if ldapURL, ok := c[common.LDAPURL]; ok && len(ldapURL) == 0 {
 		return isSysErr, fmt.Errorf("%s is empty", common.LDAPURL)
 	}
@@ -254,15 +336,17 @@ func validateCfg(c map[string]string) (bool, error) {
 	if uID, ok := c[common.LDAPUID]; ok && len(uID) == 0 {
 		return isSysErr, fmt.Errorf("%s is empty", common.LDAPUID)
 	}
-	if scope, ok := c[common.LDAPScope]; ok &&
-		scope != common.LDAPScopeBase &&
-		scope != common.LDAPScopeOnelevel &&
-		scope != common.LDAPScopeSubtree {
-		return isSysErr, fmt.Errorf("invalid %s, should be %s, %s or %s",
-			common.LDAPScope,
-			common.LDAPScopeBase,
-			common.LDAPScopeOnelevel,
-			common.LDAPScopeSubtree)
+	for _, k := range []string{common.LDAPScope, common.LDAPGroupScope} {
+		if scope, ok := c[k]; ok &&
+			scope != common.LDAPScopeBase &&
+			scope != common.LDAPScopeOnelevel &&
+			scope != common.LDAPScopeSubtree {
+			return isSysErr, fmt.Errorf("invalid %s, should be %s, %s or %s",
+				k,
+				common.LDAPScopeBase,
+				common.LDAPScopeOnelevel,
+				common.LDAPScopeSubtree)
+		}
 	}
 
 	for _, k := range boolKeys {
@@ -294,6 +378,52 @@ func validateCfg(c map[string]string) (bool, error) {
 		}
 	}
 
+	for _, k := range floatKeys {
+		v, ok := c[k]
+		if !ok {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return isSysErr, fmt.Errorf("invalid %s: %s", k, v)
+		}
+
+		if k == common.RepRetryMultiplier && f < 1 {
+			return isSysErr, fmt.Errorf("invalid %s, should not be less than 1: %s", k, v)
+		}
+
+		if k == common.RepRetryJitter && f > 1 {
+			return isSysErr, fmt.Errorf("invalid %s, should be between 0 and 1: %s", k, v)
+		}
+	}
+
+	if policy, ok := c[common.ScanAllPolicy]; ok &&
+		policy != common.ScanAllPolicyNone &&
+		policy != common.ScanAllPolicyDaily &&
+		policy != common.ScanAllPolicyWeekly {
+		return isSysErr, fmt.Errorf("invalid %s, should be %s, %s or %s", common.ScanAllPolicy,
+			common.ScanAllPolicyNone, common.ScanAllPolicyDaily, common.ScanAllPolicyWeekly)
+	}
+
+	if scanner, ok := c[common.Scanner]; ok &&
+		scanner != common.ScannerClair &&
+		scanner != common.ScannerHTTP {
+		return isSysErr, fmt.Errorf("invalid %s, should be %s or %s", common.Scanner,
+			common.ScannerClair, common.ScannerHTTP)
+	}
+
+	if endpoint, ok := c[common.ScannerEndpoint]; ok {
+		if len(endpoint) == 0 && c[common.Scanner] == common.ScannerHTTP {
+			return isSysErr, fmt.Errorf("%s is required when %s is %s", common.ScannerEndpoint,
+				common.Scanner, common.ScannerHTTP)
+		}
+		if u, err := url.Parse(endpoint); len(endpoint) > 0 &&
+			(err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0) {
+			return isSysErr, fmt.Errorf("invalid %s: %s", common.ScannerEndpoint, endpoint)
+		}
+	}
+
 	if crt, ok := c[common.ProjectCreationRestriction]; ok &&
 		crt != common.ProCrtRestrEveryone &&
 		crt != common.ProCrtRestrAdmOnly {
@@ -326,6 +456,18 @@ func convertForPut(m map[string]string) (map[string]interface{}, error) {
 		cfg[k] = v
 	}
 
+	for _, k := range floatKeys {
+		if _, ok := cfg[k]; !ok {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(cfg[k].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+		cfg[k] = v
+	}
+
 	for _, k := range boolKeys {
 		if _, ok := cfg[k]; !ok {
 			continue
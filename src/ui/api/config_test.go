@@ -61,7 +61,11 @@ func TestPutConfig(t *testing.T) {
 	apiTest := newHarborAPI()
 
 	cfg := map[string]string{
-		common.VerifyRemoteCert: "0",
+		common.VerifyRemoteCert:     "0",
+		common.RepRetryMaxAttempts:  "5",
+		common.RepRetryMultiplier:   "1.5",
+		common.RepRetryJitter:       "0.1",
+		common.RepRetryInitialDelay: "60",
 	}
 
 	code, err := apiTest.PutConfig(*admin, cfg)
@@ -77,6 +81,15 @@ func TestPutConfig(t *testing.T) {
 		t.Logf("failed to get system configurations: %v", err)
 	}
 	t.Logf("%v", ccc)
+
+	cfg = map[string]string{
+		common.RepRetryJitter: "1.5",
+	}
+	code, err = apiTest.PutConfig(*admin, cfg)
+	if err != nil {
+		t.Fatalf("failed to put configurations: %v", err)
+	}
+	assert.Equal(400, code, "the status code of modifying configurations with invalid jitter should be 400")
 }
 
 func TestResetConfig(t *testing.T) {
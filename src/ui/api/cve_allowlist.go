@@ -0,0 +1,117 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// CVEAllowlistAPI handles request to /api/system/cve_allowlist and
+// /api/projects/{}/cve_allowlist
+type CVEAllowlistAPI struct {
+	BaseController
+	// projectID is 0 for the system level allowlist
+	projectID int64
+}
+
+// Prepare validates the URL and the user, all the authenticated users can get
+// the system level allowlist while only the system admins can update it, the
+// project level one can be got by the members and updated by the members who
+// can manage the policies of the project
+func (c *CVEAllowlistAPI) Prepare() {
+	c.BaseController.Prepare()
+
+	if !c.SecurityCtx.IsAuthenticated() {
+		c.HandleUnauthorized()
+		return
+	}
+
+	if len(c.GetStringFromPath(":pid")) == 0 {
+		if c.Ctx.Request.Method != http.MethodGet && !c.SecurityCtx.IsSysAdmin() {
+			c.HandleForbidden(c.SecurityCtx.GetUsername())
+		}
+		return
+	}
+
+	pid, err := c.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		c.HandleBadRequest(fmt.Sprintf("invalid project ID: %s", c.GetStringFromPath(":pid")))
+		return
+	}
+	project, err := c.ProjectMgr.Get(pid)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to get project %d: %v", pid, err))
+		return
+	}
+	if project == nil {
+		c.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	c.projectID = pid
+
+	perm := models.PermProjectRead
+	if c.Ctx.Request.Method != http.MethodGet {
+		perm = models.PermPolicyManage
+	}
+	if !c.SecurityCtx.Can(perm, pid) {
+		c.HandleForbidden(c.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// Get returns the CVE allowlist
+func (c *CVEAllowlistAPI) Get() {
+	items, err := dao.GetCVEAllowlist(c.projectID)
+	if err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to get the CVE allowlist of project %d: %v",
+			c.projectID, err))
+		return
+	}
+	c.Data["json"] = items
+	c.ServeJSON()
+}
+
+// Put replaces the CVE allowlist with the one in the request body
+func (c *CVEAllowlistAPI) Put() {
+	items := []*models.CVEAllowlistItem{}
+	c.DecodeJSONReq(&items)
+
+	cves := map[string]bool{}
+	for _, item := range items {
+		if item == nil {
+			c.HandleBadRequest("empty item")
+			return
+		}
+		if err := item.Valid(); err != nil {
+			c.HandleBadRequest(err.Error())
+			return
+		}
+		if cves[item.CVEID] {
+			c.HandleBadRequest(fmt.Sprintf("duplicate CVE ID: %s", item.CVEID))
+			return
+		}
+		cves[item.CVEID] = true
+	}
+
+	if err := dao.SetCVEAllowlist(c.projectID, items); err != nil {
+		c.HandleInternalServerError(fmt.Sprintf("failed to update the CVE allowlist of project %d: %v",
+			c.projectID, err))
+		return
+	}
+}
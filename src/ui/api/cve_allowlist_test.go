@@ -0,0 +1,94 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestCVEAllowlistAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+	items := []*models.CVEAllowlistItem{
+		{CVEID: "CVE-2016-2177"},
+		{CVEID: "CVE-2016-2178", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	}
+	defer func() {
+		for _, pid := range []string{"", "1"} {
+			code, err := apiTest.PutCVEAllowlist(*admin, pid, []*models.CVEAllowlistItem{})
+			require.Nil(t, err)
+			assert.Equal(t, http.StatusOK, code)
+		}
+	}()
+
+	// 401
+	code, _, err := apiTest.GetCVEAllowlist(*unknownUsr, "")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 403, only system admins can update the system level allowlist
+	code, err = apiTest.PutCVEAllowlist(*testUser, "", items)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// 400, invalid CVE ID
+	code, err = apiTest.PutCVEAllowlist(*admin, "", []*models.CVEAllowlistItem{{CVEID: ""}})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 400, duplicate CVE ID
+	code, err = apiTest.PutCVEAllowlist(*admin, "", []*models.CVEAllowlistItem{
+		{CVEID: "CVE-2016-2177"},
+		{CVEID: "CVE-2016-2177"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 200
+	code, err = apiTest.PutCVEAllowlist(*admin, "", items)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	code, list, err := apiTest.GetCVEAllowlist(*testUser, "")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 2, len(list))
+	assert.Equal(t, "CVE-2016-2177", list[0].CVEID)
+	assert.Equal(t, items[1].ExpiresAt, list[1].ExpiresAt)
+
+	// 404
+	code, _, err = apiTest.GetCVEAllowlist(*admin, "1000000")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// 403, the policy.manage permission is required to update the project level allowlist
+	code, err = apiTest.PutCVEAllowlist(*testUser, "1", items[:1])
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// 200
+	code, err = apiTest.PutCVEAllowlist(*admin, "1", items[:1])
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	code, list, err = apiTest.GetCVEAllowlist(*testUser, "1")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, len(list))
+	assert.Equal(t, "CVE-2016-2177", list[0].CVEID)
+}
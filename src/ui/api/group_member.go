@@ -0,0 +1,309 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	ldapUtils "github.com/vmware/harbor/src/common/utils/ldap"
+	"github.com/vmware/harbor/src/ui/config"
+)
+
+// ProjectGroupMemberAPI handles request to /api/projects/{}/group_members/{}
+type ProjectGroupMemberAPI struct {
+	BaseController
+	project *models.Project
+	member  *models.ProjectGroupMember
+}
+
+// groupMemberReq specifies the group either by the ID of an imported group,
+// by the DN of an LDAP group or by the name of an OIDC group, which is
+// imported if it has not been
+type groupMemberReq struct {
+	GroupID       int    `json:"group_id"`
+	LdapGroupDN   string `json:"ldap_group_dn"`
+	OIDCGroupName string `json:"oidc_group_name"`
+	Role          int    `json:"role_id"`
+}
+
+// Prepare validates the URL and the user, all members can list the groups
+// of the project while only the project admins can manage them
+func (g *ProjectGroupMemberAPI) Prepare() {
+	g.BaseController.Prepare()
+
+	if !g.SecurityCtx.IsAuthenticated() {
+		g.HandleUnauthorized()
+		return
+	}
+
+	pid, err := g.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		text := "invalid project ID: "
+		if err != nil {
+			text += err.Error()
+		} else {
+			text += fmt.Sprintf("%d", pid)
+		}
+		g.HandleBadRequest(text)
+		return
+	}
+	project, err := g.ProjectMgr.Get(pid)
+	if err != nil {
+		g.HandleInternalServerError(
+			fmt.Sprintf("failed to get project %d: %v", pid, err))
+		return
+	}
+	if project == nil {
+		g.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	g.project = project
+
+	if g.Ctx.Input.IsGet() && !g.SecurityCtx.Can(models.PermProjectRead, pid) ||
+		!g.Ctx.Input.IsGet() && !g.SecurityCtx.Can(models.PermMemberManage, pid) {
+		g.HandleForbidden(g.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(g.GetStringFromPath(":gid")) != 0 {
+		gid, err := g.GetInt64FromPath(":gid")
+		if err != nil || gid <= 0 {
+			g.HandleBadRequest(fmt.Sprintf("invalid group ID: %s", g.GetStringFromPath(":gid")))
+			return
+		}
+
+		member, err := dao.GetProjectGroupMember(pid, int(gid))
+		if err != nil {
+			g.HandleInternalServerError(fmt.Sprintf("failed to get group member %d of project %d: %v",
+				gid, pid, err))
+			return
+		}
+		if member == nil {
+			g.HandleNotFound(fmt.Sprintf("group %d is not a member of project %d", gid, pid))
+			return
+		}
+		g.member = member
+	}
+}
+
+// Get returns the group member specified by group ID or all group members
+// of the project
+func (g *ProjectGroupMemberAPI) Get() {
+	if g.member != nil {
+		g.Data["json"] = g.member
+		g.ServeJSON()
+		return
+	}
+
+	members, err := dao.GetProjectGroupMembers(g.project.ProjectID)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to get group members of project %d: %v",
+			g.project.ProjectID, err))
+		return
+	}
+	g.Data["json"] = members
+	g.ServeJSON()
+}
+
+// Post adds a group to the project with the role
+func (g *ProjectGroupMemberAPI) Post() {
+	req := &groupMemberReq{}
+	g.DecodeJSONReq(req)
+
+	valid, err := validRole(req.Role)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to get role %d: %v", req.Role, err))
+		return
+	}
+	if !valid {
+		g.HandleBadRequest(fmt.Sprintf("invalid role: %d", req.Role))
+		return
+	}
+
+	var groupID int
+	switch {
+	case req.GroupID > 0:
+		group, err := dao.GetUserGroup(req.GroupID)
+		if err != nil {
+			g.HandleInternalServerError(fmt.Sprintf("failed to get group %d: %v", req.GroupID, err))
+			return
+		}
+		if group == nil {
+			g.HandleNotFound(fmt.Sprintf("group %d not found", req.GroupID))
+			return
+		}
+		groupID = group.ID
+	case len(req.LdapGroupDN) > 0:
+		id, found := g.importLdapGroup(req.LdapGroupDN)
+		if !found {
+			return
+		}
+		groupID = id
+	case len(req.OIDCGroupName) > 0:
+		id, ok := g.importOIDCGroup(req.OIDCGroupName)
+		if !ok {
+			return
+		}
+		groupID = id
+	default:
+		g.HandleBadRequest("group_id, ldap_group_dn or oidc_group_name is required")
+		return
+	}
+
+	member, err := dao.GetProjectGroupMember(g.project.ProjectID, groupID)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to get group member %d of project %d: %v",
+			groupID, g.project.ProjectID, err))
+		return
+	}
+	if member != nil {
+		g.RenderError(http.StatusConflict, fmt.Sprintf("group %d is already a member of project %d",
+			groupID, g.project.ProjectID))
+		return
+	}
+
+	if err = dao.AddProjectGroupMember(g.project.ProjectID, groupID, req.Role); err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to add group %d to project %d: %v",
+			groupID, g.project.ProjectID, err))
+		return
+	}
+
+	g.Ctx.Output.Header("Location", g.Ctx.Request.RequestURI+"/"+strconv.Itoa(groupID))
+	g.Ctx.Output.SetStatus(http.StatusCreated)
+}
+
+// importLdapGroup imports the LDAP group specified by the DN and returns
+// its ID, the error has been rendered if false is returned
+func (g *ProjectGroupMemberAPI) importLdapGroup(dn string) (int, bool) {
+	group, err := dao.GetUserGroupByLdapDN(dn)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to get LDAP group %s: %v", dn, err))
+		return 0, false
+	}
+	if group != nil {
+		return group.ID, true
+	}
+
+	ldapConfs, err := ldapUtils.GetSystemLdapConf()
+	if err != nil {
+		g.HandleBadRequest(fmt.Sprintf("can't load system configuration: %v", err))
+		return 0, false
+	}
+	ldapConfs, err = ldapUtils.ValidateLdapConf(ldapConfs)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("invalid ldap configuration: %v", err))
+		return 0, false
+	}
+
+	ldapGroups, err := ldapUtils.SearchGroup(ldapConfs, "", dn)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to search LDAP group %s: %v", dn, err))
+		return 0, false
+	}
+	if len(ldapGroups) == 0 {
+		g.HandleNotFound(fmt.Sprintf("LDAP group %s not found", dn))
+		return 0, false
+	}
+
+	id, err := ldapUtils.ImportGroup(ldapGroups[0])
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to import LDAP group %s: %v", dn, err))
+		return 0, false
+	}
+	return id, true
+}
+
+// importOIDCGroup adds the OIDC group specified by the name and returns its
+// ID, the error has been rendered if false is returned. The groups can't be
+// searched in the provider, users get the permissions of the group when the
+// group is in the groups claim of their ID tokens
+func (g *ProjectGroupMemberAPI) importOIDCGroup(name string) (int, bool) {
+	mode, err := config.AuthMode()
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to get auth mode: %v", err))
+		return 0, false
+	}
+	if mode != common.OIDCAuth {
+		g.HandleBadRequest("OIDC groups can only be added when the auth mode is " + common.OIDCAuth)
+		return 0, false
+	}
+	if len(name) > 255 {
+		g.HandleBadRequest("the name of OIDC group is longer than 255 characters")
+		return 0, false
+	}
+
+	group := &models.UserGroup{
+		GroupName: name,
+	}
+	if err := dao.OnboardOIDCGroup(group); err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to add OIDC group %s: %v", name, err))
+		return 0, false
+	}
+	return group.ID, true
+}
+
+// Put updates the role of the group in the project
+func (g *ProjectGroupMemberAPI) Put() {
+	if g.member == nil {
+		g.HandleBadRequest("group ID is required")
+		return
+	}
+
+	req := &groupMemberReq{}
+	g.DecodeJSONReq(req)
+	valid, err := validRole(req.Role)
+	if err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to get role %d: %v", req.Role, err))
+		return
+	}
+	if !valid {
+		g.HandleBadRequest(fmt.Sprintf("invalid role: %d", req.Role))
+		return
+	}
+
+	if err := dao.UpdateProjectGroupMember(g.project.ProjectID, g.member.GroupID, req.Role); err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to update the role of group %d in project %d: %v",
+			g.member.GroupID, g.project.ProjectID, err))
+		return
+	}
+}
+
+// Delete removes the group from the project
+func (g *ProjectGroupMemberAPI) Delete() {
+	if g.member == nil {
+		g.HandleBadRequest("group ID is required")
+		return
+	}
+
+	if err := dao.DeleteProjectGroupMember(g.project.ProjectID, g.member.GroupID); err != nil {
+		g.HandleInternalServerError(fmt.Sprintf("failed to remove group %d from project %d: %v",
+			g.member.GroupID, g.project.ProjectID, err))
+		return
+	}
+}
+
+// validRole returns whether the role exists
+func validRole(role int) (bool, error) {
+	r, err := dao.GetRoleByID(role)
+	if err != nil {
+		return false, err
+	}
+	return r != nil, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/tests/apitests/apilib"
+)
+
+func TestProjectGroupMemberAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+
+	groupID, err := dao.AddUserGroup(&models.UserGroup{
+		GroupName:   "group_for_test_api",
+		GroupType:   models.LdapGroupType,
+		LdapGroupDN: "cn=group_for_test_api,ou=groups,dc=example,dc=com",
+	})
+	require.Nil(t, err)
+	defer dao.DeleteUserGroup(groupID)
+	gid := strconv.Itoa(groupID)
+
+	member := apilib.ProjectGroupMember{
+		GroupID: int32(groupID),
+		RoleID:  int32(models.DEVELOPER),
+	}
+
+	// 401
+	code, err := apiTest.AddProjectGroupMember(*unknownUsr, "1", member)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 400, invalid role
+	code, err = apiTest.AddProjectGroupMember(*admin, "1", apilib.ProjectGroupMember{
+		GroupID: int32(groupID),
+		RoleID:  100,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 400, OIDC groups can't be added when the auth mode isn't OIDC
+	code, err = apiTest.AddProjectGroupMember(*admin, "1", apilib.ProjectGroupMember{
+		OIDCGroupName: "group_for_test_api",
+		RoleID:        int32(models.DEVELOPER),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 404, group not found
+	code, err = apiTest.AddProjectGroupMember(*admin, "1", apilib.ProjectGroupMember{
+		GroupID: 1000000,
+		RoleID:  int32(models.DEVELOPER),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// 201
+	code, err = apiTest.AddProjectGroupMember(*admin, "1", member)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	defer func() {
+		code, err := apiTest.DeleteProjectGroupMember(*admin, "1", gid)
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+	}()
+
+	// 409
+	code, err = apiTest.AddProjectGroupMember(*admin, "1", member)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// update
+	code, err = apiTest.PutProjectGroupMember(*admin, "1", gid, apilib.ProjectGroupMember{
+		RoleID: int32(models.GUEST),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	// list
+	code, members, err := apiTest.ListProjectGroupMembers(*admin, "1")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, len(members))
+	assert.Equal(t, "group_for_test_api", members[0].GroupName)
+	assert.Equal(t, int32(models.GUEST), members[0].RoleID)
+}
@@ -96,9 +96,19 @@ func init() {
 	beego.Router("/api/users", &UserAPI{}, "get:List;post:Post;delete:Delete;put:Put")
 	beego.Router("/api/users/:id([0-9]+)/password", &UserAPI{}, "put:ChangePassword")
 	beego.Router("/api/users/:id/sysadmin", &UserAPI{}, "put:ToggleUserAdminRole")
+	beego.Router("/api/users/:id/cli_secret", &UserAPI{}, "get:GetCLISecret;put:RegenerateCLISecret")
+	beego.Router("/api/users/:id/totp", &UserAPI{}, "get:GetTOTP;post:EnrollTOTP;put:ActivateTOTP;delete:DisableTOTP")
+	beego.Router("/api/users/:id/tokens/?:tid", &AccessTokenAPI{}, "get:Get;post:Post;delete:Delete")
+	beego.Router("/api/users/:id/sessions/?:sid", &UserSessionAPI{}, "get:Get;delete:Delete")
 	beego.Router("/api/projects/:id/publicity", &ProjectAPI{}, "put:ToggleProjectPublic")
 	beego.Router("/api/projects/:id([0-9]+)/logs", &ProjectAPI{}, "get:Logs")
 	beego.Router("/api/projects/:pid([0-9]+)/members/?:mid", &ProjectMemberAPI{}, "get:Get;post:Post;delete:Delete;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/robots/?:id", &RobotAPI{}, "get:Get;post:Post;delete:Delete;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/cve_allowlist", &CVEAllowlistAPI{}, "get:Get;put:Put")
+	beego.Router("/api/system/cve_allowlist", &CVEAllowlistAPI{}, "get:Get;put:Put")
+	beego.Router("/api/roles/?:id", &RoleAPI{}, "get:Get;post:Post;delete:Delete;put:Put")
+	beego.Router("/api/signing_keys/?:id", &SigningKeyAPI{}, "get:Get;post:Post;put:Put")
+	beego.Router("/api/projects/:pid([0-9]+)/group_members/?:gid", &ProjectGroupMemberAPI{}, "get:Get;post:Post;delete:Delete;put:Put")
 	beego.Router("/api/repositories", &RepositoryAPI{})
 	beego.Router("/api/statistics", &StatisticAPI{})
 	beego.Router("/api/users/?:id", &UserAPI{})
@@ -106,8 +116,10 @@ func init() {
 	beego.Router("/api/repositories/*/tags/:tag", &RepositoryAPI{}, "delete:Delete;get:GetTag")
 	beego.Router("/api/repositories/*/tags", &RepositoryAPI{}, "get:GetTags")
 	beego.Router("/api/repositories/*/tags/:tag/manifest", &RepositoryAPI{}, "get:GetManifests")
+	beego.Router("/api/repositories/*/tags/:tag/vulnerability/details", &RepositoryAPI{}, "get:VulnerabilityDetails")
 	beego.Router("/api/repositories/*/signatures", &RepositoryAPI{}, "get:GetSignatures")
 	beego.Router("/api/repositories/top", &RepositoryAPI{}, "get:GetTopRepos")
+	beego.Router("/api/repositories/scanAll", &RepositoryAPI{}, "get:ScanAllStatus;post:ScanAll")
 	beego.Router("/api/targets/", &TargetAPI{}, "get:List")
 	beego.Router("/api/targets/", &TargetAPI{}, "post:Post")
 	beego.Router("/api/targets/:id([0-9]+)", &TargetAPI{})
@@ -121,6 +133,8 @@ func init() {
 	beego.Router("/api/systeminfo", &SystemInfoAPI{}, "get:GetGeneralInfo")
 	beego.Router("/api/systeminfo/volumes", &SystemInfoAPI{}, "get:GetVolumeInfo")
 	beego.Router("/api/systeminfo/getcert", &SystemInfoAPI{}, "get:GetCert")
+	beego.Router("/api/login_locks", &LoginLockAPI{}, "get:List")
+	beego.Router("/api/login_locks/:id([0-9]+)", &LoginLockAPI{}, "delete:Delete")
 	beego.Router("/api/ldap/ping", &LdapAPI{}, "post:Ping")
 	beego.Router("/api/configurations", &ConfigAPI{})
 	beego.Router("/api/configurations/reset", &ConfigAPI{}, "post:Reset")
@@ -374,6 +388,15 @@ func (a testapi) ToggleProjectPublicity(prjUsr usrInfo, projectID string, ispubl
 
 }
 
+//Update the publicity and policies of a project.
+func (a testapi) ProjectsPut(prjUsr usrInfo, projectID string, project apilib.ProjectReq) (int, error) {
+	path := "/api/projects/" + projectID
+	_sling := sling.New().Put(a.basePath).Path(path).BodyJSON(project)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, prjUsr)
+	return httpStatusCode, err
+}
+
 //Get access logs accompany with a relevant project.
 func (a testapi) ProjectLogs(prjUsr usrInfo, projectID string, query *apilib.LogQuery) (int, []byte, error) {
 	_sling := sling.New().Get(a.basePath).
@@ -503,6 +526,33 @@ func (a testapi) GetTag(authInfo usrInfo, repository string, tag string) (int, *
 	return http.StatusOK, &result, nil
 }
 
+//Get the vulnerabilities of the image
+func (a testapi) GetVulnerabilityDetails(authInfo usrInfo, repository string, tag string) (int, []*models.VulnerabilityItem, error) {
+	_sling := sling.New().Get(a.basePath).Path(fmt.Sprintf("/api/repositories/%s/tags/%s/vulnerability/details", repository, tag))
+
+	var successPayload []*models.VulnerabilityItem
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Scan all images
+func (a testapi) ScanAll(authInfo usrInfo) (int, error) {
+	_sling := sling.New().Post(a.basePath).Path("/api/repositories/scanAll")
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Get the status of scanning all images
+func (a testapi) GetScanAllStatus(authInfo usrInfo) (int, []byte, error) {
+	_sling := sling.New().Get(a.basePath).Path("/api/repositories/scanAll")
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
 //Get tags of a relevant repository
 func (a testapi) GetReposTags(authInfo usrInfo, repoName string) (int, interface{}, error) {
 	_sling := sling.New().Get(a.basePath)
@@ -570,6 +620,119 @@ func (a testapi) GetReposTop(authInfo usrInfo, count string) (int, interface{},
 	return http.StatusOK, result, nil
 }
 
+//-------------------------Robots Test----------------------------------------//
+//Create a robot for the project
+func (a testapi) AddRobot(authInfo usrInfo, projectID string, robot apilib.RobotPost) (int, []byte, error) {
+	_sling := sling.New().Post(a.basePath)
+
+	path := "/api/projects/" + projectID + "/robots"
+
+	_sling = _sling.Path(path)
+	_sling = _sling.BodyJSON(robot)
+
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//List the robots of the project
+func (a testapi) ListRobots(authInfo usrInfo, projectID string) (int, []apilib.Robot, error) {
+	_sling := sling.New().Get(a.basePath)
+
+	path := "/api/projects/" + projectID + "/robots"
+
+	_sling = _sling.Path(path)
+
+	var successPayload []apilib.Robot
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Update the robot of the project
+func (a testapi) PutRobot(authInfo usrInfo, projectID, robotID string, robot apilib.Robot) (int, error) {
+	_sling := sling.New().Put(a.basePath)
+
+	path := "/api/projects/" + projectID + "/robots/" + robotID
+
+	_sling = _sling.Path(path)
+	_sling = _sling.BodyJSON(robot)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Delete the robot of the project
+func (a testapi) DeleteRobot(authInfo usrInfo, projectID, robotID string) (int, error) {
+	_sling := sling.New().Delete(a.basePath)
+
+	path := "/api/projects/" + projectID + "/robots/" + robotID
+
+	_sling = _sling.Path(path)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//-------------------------Roles Test-----------------------------------------//
+//Create a custom role
+func (a testapi) AddRole(authInfo usrInfo, role interface{}) (int, string, error) {
+	_sling := sling.New().Post(a.basePath)
+
+	path := "/api/roles"
+
+	_sling = _sling.Path(path)
+	_sling = _sling.BodyJSON(role)
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, string(body), err
+}
+
+//List all the roles
+func (a testapi) ListRoles(authInfo usrInfo) (int, []models.Role, error) {
+	_sling := sling.New().Get(a.basePath)
+
+	path := "/api/roles"
+
+	_sling = _sling.Path(path)
+
+	var successPayload []models.Role
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Update the role
+func (a testapi) PutRole(authInfo usrInfo, roleID string, role interface{}) (int, error) {
+	_sling := sling.New().Put(a.basePath)
+
+	path := "/api/roles/" + roleID
+
+	_sling = _sling.Path(path)
+	_sling = _sling.BodyJSON(role)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Delete the role
+func (a testapi) DeleteRole(authInfo usrInfo, roleID string) (int, error) {
+	_sling := sling.New().Delete(a.basePath)
+
+	path := "/api/roles/" + roleID
+
+	_sling = _sling.Path(path)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
 //-------------------------Targets Test---------------------------------------//
 //Create a new replication target
 func (a testapi) AddTargets(authInfo usrInfo, repTarget apilib.RepTargetPost) (int, string, error) {
@@ -1027,3 +1190,223 @@ func (a testapi) PingEmail(authInfo usrInfo, settings []byte) (int, string, erro
 
 	return code, string(body), err
 }
+
+//-------------------------Project Group Members Test--------------------------//
+//Add a group to the project
+func (a testapi) AddProjectGroupMember(authInfo usrInfo, projectID string, member apilib.ProjectGroupMember) (int, error) {
+	_sling := sling.New().Post(a.basePath)
+
+	path := "/api/projects/" + projectID + "/group_members"
+
+	_sling = _sling.Path(path)
+	_sling = _sling.BodyJSON(member)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//List the group members of the project
+func (a testapi) ListProjectGroupMembers(authInfo usrInfo, projectID string) (int, []apilib.ProjectGroupMember, error) {
+	_sling := sling.New().Get(a.basePath)
+
+	path := "/api/projects/" + projectID + "/group_members"
+
+	_sling = _sling.Path(path)
+
+	var successPayload []apilib.ProjectGroupMember
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Update the role of the group in the project
+func (a testapi) PutProjectGroupMember(authInfo usrInfo, projectID, groupID string, member apilib.ProjectGroupMember) (int, error) {
+	_sling := sling.New().Put(a.basePath)
+
+	path := "/api/projects/" + projectID + "/group_members/" + groupID
+
+	_sling = _sling.Path(path)
+	_sling = _sling.BodyJSON(member)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Remove the group from the project
+func (a testapi) DeleteProjectGroupMember(authInfo usrInfo, projectID, groupID string) (int, error) {
+	_sling := sling.New().Delete(a.basePath)
+
+	path := "/api/projects/" + projectID + "/group_members/" + groupID
+
+	_sling = _sling.Path(path)
+
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//-------------------------Login Locks Test------------------------------------//
+//List the locked usernames and source IPs
+func (a testapi) ListLoginLocks(authInfo usrInfo) (int, []byte, error) {
+	_sling := sling.New().Get(a.basePath).Path("/api/login_locks")
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//Unlock the username or source IP
+func (a testapi) DeleteLoginLock(authInfo usrInfo, id string) (int, error) {
+	_sling := sling.New().Delete(a.basePath).Path("/api/login_locks/" + id)
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Get the two-factor authentication status of the current user
+func (a testapi) GetTOTP(authInfo usrInfo) (int, []byte, error) {
+	_sling := sling.New().Get(a.basePath).Path("/api/users/current/totp")
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//Enroll the current user in two-factor authentication
+func (a testapi) EnrollTOTP(authInfo usrInfo) (int, []byte, error) {
+	_sling := sling.New().Post(a.basePath).Path("/api/users/current/totp")
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//Verify the enrollment of the current user with the one-time password
+func (a testapi) ActivateTOTP(authInfo usrInfo, code string) (int, []byte, error) {
+	_sling := sling.New().Put(a.basePath).Path("/api/users/current/totp").
+		BodyJSON(map[string]string{"code": code})
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//Disable two-factor authentication of the current user
+func (a testapi) DisableTOTP(authInfo usrInfo, code string) (int, error) {
+	_sling := sling.New().Delete(a.basePath).Path("/api/users/current/totp").
+		BodyJSON(map[string]string{"code": code})
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Create a personal access token for the current user
+func (a testapi) AddAccessToken(authInfo usrInfo, token interface{}) (int, []byte, error) {
+	_sling := sling.New().Post(a.basePath).Path("/api/users/current/tokens").
+		BodyJSON(token)
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//List the personal access tokens of the current user
+func (a testapi) ListAccessTokens(authInfo usrInfo) (int, []models.AccessToken, error) {
+	_sling := sling.New().Get(a.basePath).Path("/api/users/current/tokens")
+
+	var successPayload []models.AccessToken
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Revoke the personal access token of the current user
+func (a testapi) DeleteAccessToken(authInfo usrInfo, tokenID string) (int, error) {
+	_sling := sling.New().Delete(a.basePath).Path("/api/users/current/tokens/" + tokenID)
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//List the sessions of the user
+func (a testapi) ListUserSessions(authInfo usrInfo, userID string) (int, []models.UserSession, error) {
+	_sling := sling.New().Get(a.basePath).Path("/api/users/" + userID + "/sessions")
+
+	var successPayload []models.UserSession
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Revoke the session of the user, all sessions are revoked if the session ID is empty
+func (a testapi) DeleteUserSession(authInfo usrInfo, userID, sessionID string) (int, error) {
+	path := "/api/users/" + userID + "/sessions"
+	if len(sessionID) > 0 {
+		path += "/" + sessionID
+	}
+	_sling := sling.New().Delete(a.basePath).Path(path)
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//Send the request authenticated by the personal access token
+func (a testapi) RequestWithAccessToken(method, path, token string) (int, []byte, error) {
+	_sling := sling.New().Get(a.basePath)
+	if method == http.MethodPost {
+		_sling = sling.New().Post(a.basePath).BodyJSON(map[string]string{})
+	}
+	_sling = _sling.Path(path).Set("Authorization", "Bearer "+token)
+	return request(_sling, jsonAcceptHeader)
+}
+
+//Generate a token signing key
+func (a testapi) AddSigningKey(authInfo usrInfo, req interface{}) (int, []byte, error) {
+	_sling := sling.New().Post(a.basePath).Path("/api/signing_keys").
+		BodyJSON(req)
+	return request(_sling, jsonAcceptHeader, authInfo)
+}
+
+//Get the token signing key
+func (a testapi) GetSigningKey(authInfo usrInfo, keyID string) (int, *models.SigningKey, error) {
+	_sling := sling.New().Get(a.basePath).Path("/api/signing_keys/" + keyID)
+
+	successPayload := &models.SigningKey{}
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Activate the token signing key
+func (a testapi) ActivateSigningKey(authInfo usrInfo, keyID string, req interface{}) (int, error) {
+	_sling := sling.New().Put(a.basePath).Path("/api/signing_keys/" + keyID).
+		BodyJSON(req)
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
+
+//-------------------------CVE Allowlist Test----------------------------------//
+//Get the CVE allowlist of the project, the system level one is got if the project ID is empty
+func (a testapi) GetCVEAllowlist(authInfo usrInfo, projectID string) (int, []models.CVEAllowlistItem, error) {
+	path := "/api/system/cve_allowlist"
+	if len(projectID) > 0 {
+		path = "/api/projects/" + projectID + "/cve_allowlist"
+	}
+	_sling := sling.New().Get(a.basePath).Path(path)
+
+	var successPayload []models.CVEAllowlistItem
+
+	httpStatusCode, body, err := request(_sling, jsonAcceptHeader, authInfo)
+	if err == nil && httpStatusCode == 200 {
+		err = json.Unmarshal(body, &successPayload)
+	}
+
+	return httpStatusCode, successPayload, err
+}
+
+//Update the CVE allowlist of the project, the system level one is updated if the project ID is empty
+func (a testapi) PutCVEAllowlist(authInfo usrInfo, projectID string, items interface{}) (int, error) {
+	path := "/api/system/cve_allowlist"
+	if len(projectID) > 0 {
+		path = "/api/projects/" + projectID + "/cve_allowlist"
+	}
+	_sling := sling.New().Put(a.basePath).Path(path).BodyJSON(items)
+	httpStatusCode, _, err := request(_sling, jsonAcceptHeader, authInfo)
+	return httpStatusCode, err
+}
@@ -245,3 +245,116 @@ func importUsers(ldapConfs models.LdapConf, ldapImportUsers []string) ([]models.
 
 	return failedImportUser, nil
 }
+
+// SearchGroup searches the LDAP groups by name, or the group specified by
+// the DN
+func (l *LdapAPI) SearchGroup() {
+	ldapConfs, err := ldapUtils.GetSystemLdapConf()
+	if err != nil {
+		log.Errorf("Can't load system configuration, error: %v", err)
+		l.RenderError(http.StatusInternalServerError, fmt.Sprintf("can't load system configuration: %v", err))
+		return
+	}
+
+	ldapConfs, err = ldapUtils.ValidateLdapConf(ldapConfs)
+	if err != nil {
+		log.Errorf("Invalid ldap request, error: %v", err)
+		l.RenderError(http.StatusBadRequest, fmt.Sprintf("invalid ldap request: %v", err))
+		return
+	}
+
+	groupName := l.GetString("groupname")
+	for _, c := range metaChars {
+		if strings.ContainsRune(groupName, c) {
+			log.Errorf("the search group name contains meta char: %q", c)
+			l.RenderError(http.StatusBadRequest, fmt.Sprintf("the search group name contains meta char: %q", c))
+			return
+		}
+	}
+
+	ldapGroups, err := ldapUtils.SearchGroup(ldapConfs, groupName, l.GetString("groupdn"))
+	if err != nil {
+		log.Errorf("Ldap search group fail, error: %v", err)
+		l.RenderError(http.StatusBadRequest, fmt.Sprintf("ldap search group fail: %v", err))
+		return
+	}
+
+	l.Data["json"] = ldapGroups
+	l.ServeJSON()
+}
+
+// ImportGroup imports the LDAP groups specified by DNs, the imported groups
+// can be added to projects as members
+func (l *LdapAPI) ImportGroup() {
+	var ldapImportGroups models.LdapImportGroup
+	var ldapFailedImportGroups []models.LdapFailedImportGroup
+
+	ldapConfs, err := ldapUtils.GetSystemLdapConf()
+	if err != nil {
+		log.Errorf("Can't load system configuration, error: %v", err)
+		l.RenderError(http.StatusInternalServerError, fmt.Sprintf("can't load system configuration: %v", err))
+		return
+	}
+
+	l.DecodeJSONReqAndValidate(&ldapImportGroups)
+
+	ldapConfs, err = ldapUtils.ValidateLdapConf(ldapConfs)
+	if err != nil {
+		log.Errorf("Invalid ldap request, error: %v", err)
+		l.RenderError(http.StatusBadRequest, fmt.Sprintf("invalid ldap request: %v", err))
+		return
+	}
+
+	groups := []*models.UserGroup{}
+	for _, dn := range ldapImportGroups.LdapGroupDNList {
+		failed := models.LdapFailedImportGroup{
+			DN: dn,
+		}
+
+		if dn == "" {
+			failed.Error = "empty_dn"
+			ldapFailedImportGroups = append(ldapFailedImportGroups, failed)
+			continue
+		}
+
+		ldapGroups, err := ldapUtils.SearchGroup(ldapConfs, "", dn)
+		if err != nil {
+			failed.Error = "failed_search_group"
+			ldapFailedImportGroups = append(ldapFailedImportGroups, failed)
+			log.Errorf("Invalid ldap search request for group %s, error: %v", dn, err)
+			continue
+		}
+
+		if len(ldapGroups) == 0 {
+			failed.Error = "unknown_group"
+			ldapFailedImportGroups = append(ldapFailedImportGroups, failed)
+			continue
+		}
+
+		id, err := ldapUtils.ImportGroup(ldapGroups[0])
+		if err != nil {
+			failed.Error = err.Error()
+			ldapFailedImportGroups = append(ldapFailedImportGroups, failed)
+			log.Errorf("Can't import group %s, error: %s", dn, failed.Error)
+			continue
+		}
+
+		groups = append(groups, &models.UserGroup{
+			ID:          id,
+			GroupName:   ldapGroups[0].GroupName,
+			GroupType:   models.LdapGroupType,
+			LdapGroupDN: ldapGroups[0].GroupDN,
+		})
+	}
+
+	if len(ldapFailedImportGroups) > 0 {
+		log.Errorf("Import ldap group have internal error")
+		l.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		l.Data["json"] = ldapFailedImportGroups
+		l.ServeJSON()
+		return
+	}
+
+	l.Data["json"] = groups
+	l.ServeJSON()
+}
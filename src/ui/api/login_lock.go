@@ -0,0 +1,79 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// LoginLockAPI handles request to /api/login_locks/{}
+type LoginLockAPI struct {
+	BaseController
+}
+
+// Prepare validates the user, only system admins can list and unlock the
+// locked usernames and source IPs
+func (l *LoginLockAPI) Prepare() {
+	l.BaseController.Prepare()
+	if !l.SecurityCtx.IsAuthenticated() {
+		l.HandleUnauthorized()
+		return
+	}
+	if !l.SecurityCtx.IsSysAdmin() {
+		l.HandleForbidden(l.SecurityCtx.GetUsername())
+		return
+	}
+}
+
+// List returns the usernames and source IPs which are locked now
+func (l *LoginLockAPI) List() {
+	locks, err := dao.GetLockedLoginFailures(time.Now())
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get locked logins: %v", err))
+		return
+	}
+	l.Data["json"] = locks
+	l.ServeJSON()
+}
+
+// Delete unlocks the username or source IP, the duration of its next lockout
+// is reset as well
+func (l *LoginLockAPI) Delete() {
+	id, err := l.GetInt64FromPath(":id")
+	if err != nil || id <= 0 {
+		l.HandleBadRequest(fmt.Sprintf("invalid ID: %s", l.GetStringFromPath(":id")))
+		return
+	}
+
+	lf, err := dao.GetLoginFailureByID(id)
+	if err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to get login lock %d: %v", id, err))
+		return
+	}
+	if lf == nil {
+		l.HandleNotFound(fmt.Sprintf("login lock %d not found", id))
+		return
+	}
+
+	if err = dao.DeleteLoginFailure(lf.Type, lf.Name); err != nil {
+		l.HandleInternalServerError(fmt.Sprintf("failed to unlock %s %s: %v", lf.Type, lf.Name, err))
+		return
+	}
+	log.Infof("%s %s is unlocked by %s", lf.Type, lf.Name, l.SecurityCtx.GetUsername())
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestLoginLockAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+	now := time.Now()
+
+	lf, err := dao.IncreaseLoginFailure(models.LoginFailureTypeUser,
+		"user_for_test_login_lock", now, now.Add(-time.Minute), now.Add(-time.Hour))
+	require.Nil(t, err)
+	require.NotNil(t, lf)
+	locked, err := dao.LockLoginFailure(lf, now.Add(time.Hour))
+	require.Nil(t, err)
+	require.True(t, locked)
+	defer dao.DeleteLoginFailure(lf.Type, lf.Name)
+	id := strconv.FormatInt(lf.ID, 10)
+
+	// 401
+	code, _, err := apiTest.ListLoginLocks(*unknownUsr)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 403
+	code, _, err = apiTest.ListLoginLocks(*testUser)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// list
+	code, body, err := apiTest.ListLoginLocks(*admin)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	locks := []*models.LoginFailure{}
+	require.Nil(t, json.Unmarshal(body, &locks))
+	found := false
+	for _, lock := range locks {
+		if lock.ID == lf.ID {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	// unlock
+	code, err = apiTest.DeleteLoginLock(*admin, id)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	// 404
+	code, err = apiTest.DeleteLoginLock(*admin, id)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+}
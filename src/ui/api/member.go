@@ -77,8 +77,8 @@ func (pma *ProjectMemberAPI) Prepare() {
 	}
 	pma.project = project
 
-	if pma.Ctx.Input.IsGet() && !pma.SecurityCtx.HasReadPerm(pid) ||
-		!pma.SecurityCtx.HasAllPerm(pid) {
+	if pma.Ctx.Input.IsGet() && !pma.SecurityCtx.Can(models.PermProjectRead, pid) ||
+		!pma.Ctx.Input.IsGet() && !pma.SecurityCtx.Can(models.PermMemberManage, pid) {
 		pma.HandleForbidden(pma.SecurityCtx.GetUsername())
 		return
 	}
@@ -180,9 +180,12 @@ func (pma *ProjectMemberAPI) Post() {
 	}
 
 	rid := req.Roles[0]
-	if !(rid == models.PROJECTADMIN ||
-		rid == models.DEVELOPER ||
-		rid == models.GUEST) {
+	valid, err := validRole(rid)
+	if err != nil {
+		log.Errorf("Error occurred in GetRoleByID, error: %v", err)
+		pma.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+	if !valid {
 		pma.CustomAbort(http.StatusBadRequest, "invalid role")
 	}
 
@@ -18,8 +18,8 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 
-	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/utils"
@@ -37,8 +37,12 @@ type ProjectAPI struct {
 }
 
 type projectReq struct {
-	ProjectName string `json:"project_name"`
-	Public      int    `json:"public"`
+	ProjectName                                string `json:"project_name"`
+	Public                                     int    `json:"public"`
+	EnableContentTrust                         bool   `json:"enable_content_trust"`
+	PreventVulnerableImagesFromRunning         bool   `json:"prevent_vulnerable_images_from_running"`
+	PreventVulnerableImagesFromRunningSeverity string `json:"prevent_vulnerable_images_from_running_severity"`
+	AutomaticallyScanImagesOnPush              bool   `json:"automatically_scan_images_on_push"`
 }
 
 const projectNameMaxLen int = 30
@@ -46,6 +50,15 @@ const projectNameMinLen int = 2
 const restrictedNameChars = `[a-z0-9]+(?:[._-][a-z0-9]+)*`
 const dupProjectPattern = `Duplicate entry '\w+' for key 'name'`
 
+// the severities which can be set as the threshold of preventing vulnerable images from running
+var validSeverities = map[string]bool{
+	"negligible": true,
+	"unknown":    true,
+	"low":        true,
+	"medium":     true,
+	"high":       true,
+}
+
 // Prepare validates the URL and the user
 func (p *ProjectAPI) Prepare() {
 	p.BaseController.Prepare()
@@ -116,9 +129,13 @@ func (p *ProjectAPI) Post() {
 	}
 
 	projectID, err := p.ProjectMgr.Create(&models.Project{
-		Name:      pro.ProjectName,
-		Public:    pro.Public,
-		OwnerName: p.SecurityCtx.GetUsername(),
+		Name:                               pro.ProjectName,
+		Public:                             pro.Public,
+		OwnerName:                          p.SecurityCtx.GetUsername(),
+		EnableContentTrust:                 pro.EnableContentTrust,
+		PreventVulnerableImagesFromRunning: pro.PreventVulnerableImagesFromRunning,
+		PreventVulnerableImagesFromRunningSeverity: pro.PreventVulnerableImagesFromRunningSeverity,
+		AutomaticallyScanImagesOnPush:              pro.AutomaticallyScanImagesOnPush,
 	})
 	if err != nil {
 		log.Errorf("Failed to add project, error: %v", err)
@@ -177,7 +194,7 @@ func (p *ProjectAPI) Get() {
 			return
 		}
 
-		if !p.SecurityCtx.HasReadPerm(p.project.ProjectID) {
+		if !p.SecurityCtx.Can(models.PermProjectRead, p.project.ProjectID) {
 			p.HandleForbidden(p.SecurityCtx.GetUsername())
 			return
 		}
@@ -187,6 +204,54 @@ func (p *ProjectAPI) Get() {
 	p.ServeJSON()
 }
 
+// Put updates the publicity and policies of the project, the fields
+// which are absent in the request body keep their current values
+func (p *ProjectAPI) Put() {
+	if !p.SecurityCtx.IsAuthenticated() {
+		p.HandleUnauthorized()
+		return
+	}
+
+	if !p.SecurityCtx.Can(models.PermPolicyManage, p.project.ProjectID) {
+		p.HandleForbidden(p.SecurityCtx.GetUsername())
+		return
+	}
+
+	req := projectReq{
+		ProjectName:                        p.project.Name,
+		Public:                             p.project.Public,
+		EnableContentTrust:                 p.project.EnableContentTrust,
+		PreventVulnerableImagesFromRunning: p.project.PreventVulnerableImagesFromRunning,
+		PreventVulnerableImagesFromRunningSeverity: p.project.PreventVulnerableImagesFromRunningSeverity,
+		AutomaticallyScanImagesOnPush:              p.project.AutomaticallyScanImagesOnPush,
+	}
+	p.DecodeJSONReq(&req)
+	if req.ProjectName != p.project.Name {
+		p.HandleBadRequest("the name of project can not be modified")
+		return
+	}
+	if req.Public != 0 && req.Public != 1 {
+		p.HandleBadRequest("public should be 0 or 1")
+		return
+	}
+	if err := validateProjectPolicies(req); err != nil {
+		p.HandleBadRequest(fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	project := *p.project
+	project.Public = req.Public
+	project.EnableContentTrust = req.EnableContentTrust
+	project.PreventVulnerableImagesFromRunning = req.PreventVulnerableImagesFromRunning
+	project.PreventVulnerableImagesFromRunningSeverity = req.PreventVulnerableImagesFromRunningSeverity
+	project.AutomaticallyScanImagesOnPush = req.AutomaticallyScanImagesOnPush
+	if err := p.ProjectMgr.Update(p.project.ProjectID, &project); err != nil {
+		p.HandleInternalServerError(fmt.Sprintf("failed to update project %d: %v",
+			p.project.ProjectID, err))
+		return
+	}
+}
+
 // Delete ...
 func (p *ProjectAPI) Delete() {
 	if !p.SecurityCtx.IsAuthenticated() {
@@ -194,7 +259,7 @@ func (p *ProjectAPI) Delete() {
 		return
 	}
 
-	if !p.SecurityCtx.HasAllPerm(p.project.ProjectID) {
+	if !p.SecurityCtx.Can(models.PermProjectDelete, p.project.ProjectID) {
 		p.HandleForbidden(p.SecurityCtx.GetUsername())
 		return
 	}
@@ -317,8 +382,7 @@ func (p *ProjectAPI) List() {
 				project.Role = roles[0]
 			}
 
-			if project.Role == common.RoleProjectAdmin ||
-				p.SecurityCtx.IsSysAdmin() {
+			if p.SecurityCtx.Can(models.PermPolicyManage, project.ProjectID) {
 				project.Togglable = true
 			}
 		}
@@ -344,7 +408,7 @@ func (p *ProjectAPI) ToggleProjectPublic() {
 		return
 	}
 
-	if !p.SecurityCtx.HasAllPerm(p.project.ProjectID) {
+	if !p.SecurityCtx.Can(models.PermPolicyManage, p.project.ProjectID) {
 		p.HandleForbidden(p.SecurityCtx.GetUsername())
 		return
 	}
@@ -356,10 +420,10 @@ func (p *ProjectAPI) ToggleProjectPublic() {
 		return
 	}
 
-	if err := p.ProjectMgr.Update(p.project.ProjectID,
-		&models.Project{
-			Public: req.Public,
-		}); err != nil {
+	// keep the policies of the project unchanged
+	project := *p.project
+	project.Public = req.Public
+	if err := p.ProjectMgr.Update(p.project.ProjectID, &project); err != nil {
 		p.HandleInternalServerError(fmt.Sprintf("failed to update project %d: %v",
 			p.project.ProjectID, err))
 		return
@@ -373,7 +437,7 @@ func (p *ProjectAPI) Logs() {
 		return
 	}
 
-	if !p.SecurityCtx.HasReadPerm(p.project.ProjectID) {
+	if !p.SecurityCtx.Can(models.PermLogRead, p.project.ProjectID) {
 		p.HandleForbidden(p.SecurityCtx.GetUsername())
 		return
 	}
@@ -441,5 +505,19 @@ func validateProjectReq(req projectReq) error {
 	if !legal {
 		return fmt.Errorf("project name is not in lower case or contains illegal characters")
 	}
+	return validateProjectPolicies(req)
+}
+
+func validateProjectPolicies(req projectReq) error {
+	sev := req.PreventVulnerableImagesFromRunningSeverity
+	if len(sev) == 0 {
+		if req.PreventVulnerableImagesFromRunning {
+			return fmt.Errorf("prevent_vulnerable_images_from_running_severity is required when preventing vulnerable images from running")
+		}
+		return nil
+	}
+	if !validSeverities[strings.ToLower(sev)] {
+		return fmt.Errorf("invalid prevent_vulnerable_images_from_running_severity: %s", sev)
+	}
 	return nil
 }
@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/ui/config"
 	"github.com/vmware/harbor/tests/apitests/apilib"
 )
 
@@ -27,7 +29,7 @@ var addProject *apilib.ProjectReq
 var addPID int
 
 func InitAddPro() {
-	addProject = &apilib.ProjectReq{"add_project", 1}
+	addProject = &apilib.ProjectReq{ProjectName: "add_project", Public: 1}
 }
 
 func TestAddProject(t *testing.T) {
@@ -78,7 +80,7 @@ func TestAddProject(t *testing.T) {
 	//case 4: reponse code = 400 : Project name is illegal in length
 	fmt.Println("case 4 : reponse code = 400 : Project name is illegal in length ")
 
-	result, err = apiTest.ProjectsPost(*admin, apilib.ProjectReq{"t", 1})
+	result, err = apiTest.ProjectsPost(*admin, apilib.ProjectReq{ProjectName: "t", Public: 1})
 	if err != nil {
 		t.Error("Error while creat project", err.Error())
 		t.Log(err)
@@ -314,6 +316,72 @@ func TestToggleProjectPublicity(t *testing.T) {
 
 	fmt.Printf("\n")
 }
+func TestUpdateProject(t *testing.T) {
+	fmt.Println("\nTest for Project PUT API: Update publicity and policies of a project")
+	assert := assert.New(t)
+
+	apiTest := newHarborAPI()
+
+	projectID, err := config.GlobalProjectMgr.Create(&models.Project{
+		Name:    "project_for_test_update",
+		OwnerID: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to add project: %v", err)
+	}
+	defer config.GlobalProjectMgr.Delete(projectID)
+	id := strconv.FormatInt(projectID, 10)
+
+	//-------------------case1: Response Code=200------------------------------//
+	fmt.Println("case 1: respose code:200")
+	httpStatusCode, err := apiTest.ProjectsPut(*admin, id, apilib.ProjectReq{
+		ProjectName:                        "project_for_test_update",
+		Public:                             1,
+		EnableContentTrust:                 true,
+		PreventVulnerableImagesFromRunning: true,
+		PreventVulnerableImagesFromRunningSeverity: "medium",
+	})
+	if err != nil {
+		t.Fatalf("failed to update project: %v", err)
+	}
+	assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
+
+	httpStatusCode, project, err := apiTest.ProjectsGetByPID(id)
+	if err != nil {
+		t.Fatalf("failed to get project: %v", err)
+	}
+	assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
+	assert.True(project.EnableContentTrust)
+	assert.True(project.PreventVulnerableImagesFromRunning)
+	assert.Equal("medium", project.PreventVulnerableImagesFromRunningSeverity)
+	assert.False(project.AutomaticallyScanImagesOnPush)
+
+	//-------------------case2: Response Code=400 invalid severity------------------------------//
+	fmt.Println("case 2: respose code:400, invalid severity")
+	httpStatusCode, err = apiTest.ProjectsPut(*admin, id, apilib.ProjectReq{
+		ProjectName:                        "project_for_test_update",
+		Public:                             1,
+		PreventVulnerableImagesFromRunning: true,
+		PreventVulnerableImagesFromRunningSeverity: "invalid_severity",
+	})
+	if err != nil {
+		t.Fatalf("failed to update project: %v", err)
+	}
+	assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
+
+	//-------------------case3: Response Code=401 User need to log in first.------------------------------//
+	fmt.Println("case 3: respose code:401, User need to log in first.")
+	httpStatusCode, err = apiTest.ProjectsPut(*unknownUsr, id, apilib.ProjectReq{
+		ProjectName: "project_for_test_update",
+	})
+	if err != nil {
+		t.Fatalf("failed to update project: %v", err)
+	}
+	assert.Equal(int(401), httpStatusCode, "httpStatusCode should be 401")
+
+	fmt.Printf("\n")
+}
+
 func TestProjectLogsFilter(t *testing.T) {
 	fmt.Println("\nTest for search access logs filtered by operations and date time ranges..")
 	assert := assert.New(t)
@@ -19,6 +19,7 @@ import (
 
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
@@ -142,8 +143,8 @@ func (pa *RepPolicyAPI) Post() {
 		pa.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
 
-	if len(policies) > 0 {
-		pa.CustomAbort(http.StatusConflict, "policy already exists with the same project and target")
+	if containsDirection(policies, policy.Direction) {
+		pa.CustomAbort(http.StatusConflict, "policy already exists with the same project, target and direction")
 	}
 
 	pid, err := dao.AddRepPolicy(*policy)
@@ -166,7 +167,7 @@ func (pa *RepPolicyAPI) Post() {
 	pa.Redirect(http.StatusCreated, strconv.FormatInt(pid, 10))
 }
 
-// Put modifies name, description, target and enablement of policy
+// Put modifies name, description, target, cron string and enablement of policy
 func (pa *RepPolicyAPI) Put() {
 	id := pa.GetIDFromURL()
 	originalPolicy, err := dao.GetRepPolicy(id)
@@ -182,6 +183,8 @@ func (pa *RepPolicyAPI) Put() {
 	policy := &models.RepPolicy{}
 	pa.DecodeJSONReq(policy)
 	policy.ProjectID = originalPolicy.ProjectID
+	// the direction of policy can not be modified
+	policy.Direction = originalPolicy.Direction
 	pa.Validate(policy)
 
 	/*
@@ -223,8 +226,8 @@ func (pa *RepPolicyAPI) Put() {
 			pa.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		}
 
-		if len(policies) > 0 {
-			pa.CustomAbort(http.StatusConflict, "policy already exists with the same project and target")
+		if containsDirection(policies, policy.Direction) {
+			pa.CustomAbort(http.StatusConflict, "policy already exists with the same project, target and direction")
 		}
 	}
 
@@ -303,6 +306,15 @@ func (pa *RepPolicyAPI) Put() {
 		pa.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 	}
 
+	// clear the next run time so that the scheduler in job service
+	// recomputes it according to the new cron string
+	if policy.CronStr != originalPolicy.CronStr {
+		if err = dao.UpdateRepPolicySchedule(id, originalPolicy.LastRunTime, time.Time{}, ""); err != nil {
+			log.Errorf("failed to reset schedule of policy %d: %v", id, err)
+			pa.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+	}
+
 	if policy.Enabled != originalPolicy.Enabled && policy.Enabled == 1 {
 		go func() {
 			if err := TriggerReplication(id, "", nil, models.RepOpTransfer); err != nil {
@@ -404,3 +416,21 @@ func (pa *RepPolicyAPI) Delete() {
 		pa.CustomAbort(http.StatusInternalServerError, "")
 	}
 }
+
+// containsDirection returns whether there is a policy replicating in the
+// direction in the list, an empty direction is treated as "push"
+func containsDirection(policies []*models.RepPolicy, direction string) bool {
+	if len(direction) == 0 {
+		direction = models.RepDirectionPush
+	}
+	for _, p := range policies {
+		d := p.Direction
+		if len(d) == 0 {
+			d = models.RepDirectionPush
+		}
+		if d == direction {
+			return true
+		}
+	}
+	return false
+}
@@ -37,7 +37,7 @@ func TestPoliciesPost(t *testing.T) {
 	//add target
 	CommonAddTarget()
 	targetID := int64(CommonGetTarget())
-	repPolicy := &apilib.RepPolicyPost{int64(1), targetID, addPolicyName}
+	repPolicy := &apilib.RepPolicyPost{ProjectId: int64(1), TargetId: targetID, Name: addPolicyName}
 
 	fmt.Println("Testing Policies Post API")
 
@@ -119,6 +119,54 @@ func TestPoliciesPost(t *testing.T) {
 		assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
 	}
 
+	//-------------------case 8 : response code = 400------------------------//
+	fmt.Println("case 8 : response code = 400:cron string invalid.")
+
+	repPolicy = &apilib.RepPolicyPost{ProjectId: int64(1), TargetId: targetID, Name: addPolicyName, CronStr: "0 25 * * *"}
+	httpStatusCode, err = apiTest.AddPolicy(*admin, *repPolicy)
+	if err != nil {
+		t.Error("Error while add policy", err.Error())
+		t.Log(err)
+	} else {
+		assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
+	}
+
+	//-------------------case 9 : response code = 400------------------------//
+	fmt.Println("case 9 : response code = 400:retry jitter invalid.")
+
+	repPolicy = &apilib.RepPolicyPost{ProjectId: int64(1), TargetId: targetID, Name: addPolicyName, RetryJitter: 1.5}
+	httpStatusCode, err = apiTest.AddPolicy(*admin, *repPolicy)
+	if err != nil {
+		t.Error("Error while add policy", err.Error())
+		t.Log(err)
+	} else {
+		assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
+	}
+
+	//-------------------case 10 : response code = 400------------------------//
+	fmt.Println("case 10 : response code = 400:filter invalid.")
+
+	repPolicy = &apilib.RepPolicyPost{ProjectId: int64(1), TargetId: targetID, Name: addPolicyName,
+		Filters: []apilib.RepFilter{{Kind: "tag", Type: "include", Syntax: "regex", Pattern: "release-("}}}
+	httpStatusCode, err = apiTest.AddPolicy(*admin, *repPolicy)
+	if err != nil {
+		t.Error("Error while add policy", err.Error())
+		t.Log(err)
+	} else {
+		assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
+	}
+
+	//-------------------case 11 : response code = 400------------------------//
+	fmt.Println("case 11 : response code = 400:direction invalid.")
+
+	repPolicy = &apilib.RepPolicyPost{ProjectId: int64(1), TargetId: targetID, Name: addPolicyName, Direction: "both"}
+	httpStatusCode, err = apiTest.AddPolicy(*admin, *repPolicy)
+	if err != nil {
+		t.Error("Error while add policy", err.Error())
+		t.Log(err)
+	} else {
+		assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
+	}
 }
 
 func TestPoliciesList(t *testing.T) {
@@ -201,6 +249,18 @@ func TestPolicyUpdateInfo(t *testing.T) {
 	} else {
 		assert.Equal(int(200), httpStatusCode, "httpStatusCode should be 200")
 	}
+
+	//-------------------case 2 : response code = 400------------------------//
+	fmt.Println("case 2 : response code = 400:cron string invalid.")
+
+	policyInfo.CronStr = "* * *"
+	httpStatusCode, err = apiTest.PutPolicyInfoByID(*admin, policyID, *policyInfo)
+	if err != nil {
+		t.Error("Error while update policyInfo", err.Error())
+		t.Log(err)
+	} else {
+		assert.Equal(int(400), httpStatusCode, "httpStatusCode should be 400")
+	}
 }
 
 func TestPolicyUpdateEnablement(t *testing.T) {
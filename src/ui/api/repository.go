@@ -24,13 +24,16 @@ import (
 
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/security"
 	"github.com/vmware/harbor/src/common/utils"
 	registry_error "github.com/vmware/harbor/src/common/utils/error"
 	"github.com/vmware/harbor/src/common/utils/log"
 	"github.com/vmware/harbor/src/common/utils/notary"
 	"github.com/vmware/harbor/src/common/utils/registry"
+	"github.com/vmware/harbor/src/common/utils/scanner"
 	"github.com/vmware/harbor/src/ui/config"
 )
 
@@ -40,6 +43,14 @@ type RepositoryAPI struct {
 	BaseController
 }
 
+// scanAllStatus is the status of scanning all images, LastCompleted is nil if the
+// images have never been scanned successfully
+type scanAllStatus struct {
+	Policy        string             `json:"policy"`
+	Latest        *models.ScanAllJob `json:"latest"`
+	LastCompleted *time.Time         `json:"last_completed"`
+}
+
 type repoResp struct {
 	ID           int64     `json:"id"`
 	Name         string    `json:"name"`
@@ -60,6 +71,20 @@ type tag struct {
 	DockerVersion string    `json:"docker_version"`
 	Author        string    `json:"author"`
 	Created       time.Time `json:"created"`
+	// Platforms is only set when the tag references a manifest list
+	Platforms []*platform `json:"platforms,omitempty"`
+}
+
+// platform is an image referenced by a manifest list
+type platform struct {
+	Digest        string                  `json:"digest"`
+	Architecture  string                  `json:"architecture"`
+	OS            string                  `json:"os"`
+	Variant       string                  `json:"variant,omitempty"`
+	DockerVersion string                  `json:"docker_version"`
+	Author        string                  `json:"author"`
+	Created       time.Time               `json:"created"`
+	ScanOverview  *models.ImgScanOverview `json:"scan_overview,omitempty"`
 }
 
 type tagResp struct {
@@ -93,7 +118,7 @@ func (ra *RepositoryAPI) Get() {
 		return
 	}
 
-	if !ra.SecurityCtx.HasReadPerm(projectID) {
+	if !ra.SecurityCtx.Can(models.PermProjectRead, projectID) {
 		if !ra.SecurityCtx.IsAuthenticated() {
 			ra.HandleUnauthorized()
 			return
@@ -183,7 +208,13 @@ func (ra *RepositoryAPI) Delete() {
 		return
 	}
 
-	if !ra.SecurityCtx.HasAllPerm(projectName) {
+	// deleting a tag and deleting the whole repository require different
+	// permissions
+	perm := models.PermRepoDelete
+	if len(ra.GetString(":tag")) > 0 {
+		perm = models.PermTagDelete
+	}
+	if !ra.SecurityCtx.Can(perm, projectName) {
 		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
 		return
 	}
@@ -309,7 +340,8 @@ func (ra *RepositoryAPI) GetTag() {
 		return
 	}
 
-	if !ra.SecurityCtx.HasReadPerm(project) {
+	if !ra.SecurityCtx.Can(models.PermProjectRead, project) ||
+		!ra.canAccessRepository(repository) {
 		if !ra.SecurityCtx.IsAuthenticated() {
 			ra.HandleUnauthorized()
 			return
@@ -369,7 +401,8 @@ func (ra *RepositoryAPI) GetTags() {
 		return
 	}
 
-	if !ra.SecurityCtx.HasReadPerm(projectName) {
+	if !ra.SecurityCtx.Can(models.PermProjectRead, projectName) ||
+		!ra.canAccessRepository(repoName) {
 		if !ra.SecurityCtx.IsAuthenticated() {
 			ra.HandleUnauthorized()
 			return
@@ -430,8 +463,15 @@ func assemble(client *registry.Repository, repository string,
 		item := &tagResp{
 			tag: *tag,
 		}
-		if config.WithClair() {
-			item.ScanOverview = getScanOverview(item.Digest, item.Name)
+		if config.ScanEnabled() {
+			// the images referenced by a manifest list are scanned separately
+			if len(item.Platforms) > 0 {
+				for _, p := range item.Platforms {
+					p.ScanOverview = getScanOverview(p.Digest, item.Name)
+				}
+			} else {
+				item.ScanOverview = getScanOverview(item.Digest, item.Name)
+			}
 		}
 
 		// compare both digest and tag
@@ -452,15 +492,32 @@ func assemble(client *registry.Repository, repository string,
 func getDetailedTags(client *registry.Repository, tags []string) ([]*tag, error) {
 	list := []*tag{}
 	for _, t := range tags {
-		// the ignored manifest can be used to calculate the image size
-		digest, _, config, err := getV2Manifest(client, t)
+		digest, mediaType, payload, err := client.PullManifest(t,
+			[]string{schema2.MediaTypeManifest, registry.MediaTypeManifestList})
 		if err != nil {
 			return nil, err
 		}
 
 		tag := &tag{}
-		if err = json.Unmarshal(config, tag); err != nil {
-			return nil, err
+		if mediaType == registry.MediaTypeManifestList {
+			if tag.Platforms, err = getPlatforms(client, payload); err != nil {
+				return nil, err
+			}
+			// the tag is as new as the newest image it references
+			for _, p := range tag.Platforms {
+				if p.Created.After(tag.Created) {
+					tag.Created = p.Created
+				}
+			}
+		} else {
+			// the ignored manifest can be used to calculate the image size
+			_, config, err := getV2ManifestConfig(client, payload)
+			if err != nil {
+				return nil, err
+			}
+			if err = json.Unmarshal(config, tag); err != nil {
+				return nil, err
+			}
 		}
 
 		tag.Name = t
@@ -472,6 +529,42 @@ func getDetailedTags(client *registry.Repository, tags []string) ([]*tag, error)
 	return list, nil
 }
 
+// get the platform specific images referenced by the manifest list, the
+// manifests which are not schema2 are ignored
+func getPlatforms(client *registry.Repository, payload []byte) ([]*platform, error) {
+	manifestList := &registry.DeserializedManifestList{}
+	if err := manifestList.UnmarshalJSON(payload); err != nil {
+		return nil, err
+	}
+
+	platforms := []*platform{}
+	for _, m := range manifestList.Manifests {
+		if m.MediaType != schema2.MediaTypeManifest {
+			log.Warningf("manifest %s with media type %s in manifest list is ignored",
+				m.Digest, m.MediaType)
+			continue
+		}
+
+		digest, _, config, err := getV2Manifest(client, m.Digest.String())
+		if err != nil {
+			return nil, err
+		}
+
+		p := &platform{}
+		if err = json.Unmarshal(config, p); err != nil {
+			return nil, err
+		}
+		p.Digest = digest
+		p.Architecture = m.Platform.Architecture
+		p.OS = m.Platform.OS
+		p.Variant = m.Platform.Variant
+
+		platforms = append(platforms, p)
+	}
+
+	return platforms, nil
+}
+
 // get v2 manifest of tag, returns digest, manifest,
 // manifest config and error. The manifest config contains
 // architecture, os, author, etc.
@@ -482,21 +575,32 @@ func getV2Manifest(client *registry.Repository, tag string) (
 		return "", nil, nil, err
 	}
 
-	manifest := &schema2.DeserializedManifest{}
-	if err = manifest.UnmarshalJSON(payload); err != nil {
+	manifest, config, err := getV2ManifestConfig(client, payload)
+	if err != nil {
 		return "", nil, nil, err
 	}
+	return digest, manifest, config, nil
+}
+
+// parse the v2 manifest and pull its config
+func getV2ManifestConfig(client *registry.Repository, payload []byte) (
+	*schema2.DeserializedManifest, []byte, error) {
+	manifest := &schema2.DeserializedManifest{}
+	if err := manifest.UnmarshalJSON(payload); err != nil {
+		return nil, nil, err
+	}
 
 	_, reader, err := client.PullBlob(manifest.Target().Digest.String())
 	if err != nil {
-		return "", nil, nil, err
+		return nil, nil, err
 	}
+	defer reader.Close()
 
 	config, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return "", nil, nil, err
+		return nil, nil, err
 	}
-	return digest, manifest, config, nil
+	return manifest, config, nil
 }
 
 // return tag name list for the repository
@@ -551,7 +655,8 @@ func (ra *RepositoryAPI) GetManifests() {
 		return
 	}
 
-	if !ra.SecurityCtx.HasReadPerm(projectName) {
+	if !ra.SecurityCtx.Can(models.PermProjectRead, projectName) ||
+		!ra.canAccessRepository(repoName) {
 		if !ra.SecurityCtx.IsAuthenticated() {
 			ra.HandleUnauthorized()
 			return
@@ -590,7 +695,8 @@ func getManifest(client *registry.Repository,
 	case "v1":
 		mediaTypes = append(mediaTypes, schema1.MediaTypeManifest)
 	case "v2":
-		mediaTypes = append(mediaTypes, schema2.MediaTypeManifest)
+		mediaTypes = append(mediaTypes, schema2.MediaTypeManifest,
+			registry.MediaTypeManifestList)
 	}
 
 	_, mediaType, payload, err := client.PullManifest(tag, mediaTypes)
@@ -623,6 +729,14 @@ func getManifest(client *registry.Repository,
 	return result, nil
 }
 
+// canAccessRepository returns false if the permissions of the security
+// context to the project don't apply to the repository, e.g. the repository
+// isn't in the repository list of a robot account
+func (ra *RepositoryAPI) canAccessRepository(repository string) bool {
+	sc, ok := ra.SecurityCtx.(security.RepositoryScopedContext)
+	return !ok || sc.CanAccessRepository(repository)
+}
+
 func (ra *RepositoryAPI) initRepositoryClient(repoName string) (r *registry.Repository, err error) {
 	endpoint, err := config.RegistryURL()
 	if err != nil {
@@ -693,7 +807,8 @@ func (ra *RepositoryAPI) GetSignatures() {
 		return
 	}
 
-	if !ra.SecurityCtx.HasReadPerm(projectName) {
+	if !ra.SecurityCtx.Can(models.PermProjectRead, projectName) ||
+		!ra.canAccessRepository(repoName) {
 		if !ra.SecurityCtx.IsAuthenticated() {
 			ra.HandleUnauthorized()
 			return
@@ -714,8 +829,8 @@ func (ra *RepositoryAPI) GetSignatures() {
 
 //ScanImage handles request POST /api/repository/$repository/tags/$tag/scan to trigger image scan manually.
 func (ra *RepositoryAPI) ScanImage() {
-	if !config.WithClair() {
-		log.Warningf("Harbor is not deployed with Clair, scan is disabled.")
+	if !config.ScanEnabled() {
+		log.Warningf("No scanner is configured, scan is disabled.")
 		ra.RenderError(http.StatusServiceUnavailable, "")
 		return
 	}
@@ -736,7 +851,7 @@ func (ra *RepositoryAPI) ScanImage() {
 		ra.HandleUnauthorized()
 		return
 	}
-	if !ra.SecurityCtx.HasAllPerm(projectName) {
+	if !ra.SecurityCtx.Can(models.PermScan, projectName) {
 		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
 		return
 	}
@@ -749,6 +864,184 @@ func (ra *RepositoryAPI) ScanImage() {
 	}
 }
 
+// VulnerabilityDetails handles request GET /api/repository/$repository/tags/$tag/vulnerability/details
+// and returns the vulnerabilities found by the last scan of the image, the list is empty if the image
+// hasn't been scanned successfully or was scanned by another kind of scanner. The CVEs allowlisted by
+// the system or the project are marked. The tag which references a manifest list is rejected, the
+// images for the platforms can be queried by their digests instead
+func (ra *RepositoryAPI) VulnerabilityDetails() {
+	if !config.ScanEnabled() {
+		log.Warningf("No scanner is configured, vulnerability details are not available.")
+		ra.RenderError(http.StatusServiceUnavailable, "")
+		return
+	}
+	repository := ra.GetString(":splat")
+	tag := ra.GetString(":tag")
+	project, _ := utils.ParseRepository(repository)
+	pro, err := ra.ProjectMgr.Get(project)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get project %s: %v",
+			project, err))
+		return
+	}
+	if pro == nil {
+		ra.HandleNotFound(fmt.Sprintf("project %s not found", project))
+		return
+	}
+	if !ra.SecurityCtx.Can(models.PermProjectRead, project) ||
+		!ra.canAccessRepository(repository) {
+		if !ra.SecurityCtx.IsAuthenticated() {
+			ra.HandleUnauthorized()
+			return
+		}
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return
+	}
+
+	client, err := ra.initRepositoryClient(repository)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to initialize the client for %s: %v",
+			repository, err))
+		return
+	}
+	digest, mediaType, _, err := client.PullManifest(tag,
+		[]string{schema2.MediaTypeManifest, registry.MediaTypeManifestList})
+	if err != nil {
+		if regErr, ok := err.(*registry_error.Error); ok &&
+			regErr.StatusCode == http.StatusNotFound {
+			ra.HandleNotFound(fmt.Sprintf("%s not found", tag))
+			return
+		}
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get the manifest of %s:%s: %v", repository, tag, err))
+		return
+	}
+	// the list itself is never scanned, the images it references are
+	if mediaType == registry.MediaTypeManifestList {
+		ra.HandleBadRequest(fmt.Sprintf("%s:%s references a manifest list, get the vulnerability details of the image for each platform by its digest",
+			repository, tag))
+		return
+	}
+
+	vulns := []*models.VulnerabilityItem{}
+	overview := getScanOverview(digest, tag)
+	if overview != nil && len(overview.DetailsKey) > 0 {
+		kind, endpoint, err := config.Scanner()
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to get the configuration of scanner: %v", err))
+			return
+		}
+		// the key is meaningless to the scanner if the image was scanned by
+		// another kind of scanner, take the image as not scanned
+		scannedBy := overview.Scanner
+		if len(scannedBy) == 0 {
+			scannedBy = common.ScannerClair
+		}
+		if scannedBy != kind {
+			log.Infof("%s:%s was scanned by %s rather than %s, the details are not available",
+				repository, tag, scannedBy, kind)
+			ra.Data["json"] = vulns
+			ra.ServeJSON()
+			return
+		}
+		s, err := scanner.New(kind, endpoint, nil)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to create the scanner: %v", err))
+			return
+		}
+		report, err := s.GetReport(overview.DetailsKey)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to get the scan result of %s:%s from the scanner: %v",
+				repository, tag, err))
+			return
+		}
+		vulns = report.Vulnerabilities
+
+		allowlisted, err := dao.GetAllowlistedCVEs(pro.ProjectID)
+		if err != nil {
+			ra.HandleInternalServerError(fmt.Sprintf("failed to get the CVE allowlist of project %s: %v",
+				project, err))
+			return
+		}
+		for _, v := range vulns {
+			v.Allowlisted = allowlisted[v.ID]
+		}
+	}
+	ra.Data["json"] = vulns
+	ra.ServeJSON()
+}
+
+// ScanAll handles request POST /api/repositories/scanAll to scan all images, only system admins
+// can call it
+func (ra *RepositoryAPI) ScanAll() {
+	if !ra.checkScanAll() {
+		return
+	}
+	job, err := dao.GetLatestScanAllJob(models.JobRunning)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get the running scan all job: %v", err))
+		return
+	}
+	if job != nil {
+		ra.RenderError(http.StatusConflict, "all images are being scanned")
+		return
+	}
+	if err = TriggerScanAll(); err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to trigger scanning all images: %v", err))
+		return
+	}
+}
+
+// ScanAllStatus handles request GET /api/repositories/scanAll and returns the policy, the latest
+// scan all job and the time when all images were scanned successfully last time
+func (ra *RepositoryAPI) ScanAllStatus() {
+	if !ra.checkScanAll() {
+		return
+	}
+	policy, err := config.ScanAllPolicy()
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get scan all policy: %v", err))
+		return
+	}
+	latest, err := dao.GetLatestScanAllJob("")
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get the latest scan all job: %v", err))
+		return
+	}
+	status := &scanAllStatus{
+		Policy: policy,
+		Latest: latest,
+	}
+	finished, err := dao.GetLatestScanAllJob(models.JobFinished)
+	if err != nil {
+		ra.HandleInternalServerError(fmt.Sprintf("failed to get the last finished scan all job: %v", err))
+		return
+	}
+	if finished != nil {
+		status.LastCompleted = &finished.EndTime
+	}
+	ra.Data["json"] = status
+	ra.ServeJSON()
+}
+
+// checkScanAll returns true if a scanner is configured and the user is a system admin,
+// otherwise it renders the error and returns false
+func (ra *RepositoryAPI) checkScanAll() bool {
+	if !config.ScanEnabled() {
+		log.Warningf("No scanner is configured, scan is disabled.")
+		ra.RenderError(http.StatusServiceUnavailable, "")
+		return false
+	}
+	if !ra.SecurityCtx.IsAuthenticated() {
+		ra.HandleUnauthorized()
+		return false
+	}
+	if !ra.SecurityCtx.IsSysAdmin() {
+		ra.HandleForbidden(ra.SecurityCtx.GetUsername())
+		return false
+	}
+	return true
+}
+
 func getSignatures(repository, username string) (map[string]*notary.Target, error) {
 	targets, err := notary.GetInternalTargets(config.InternalNotaryEndpoint(),
 		username, repository)
@@ -199,3 +199,27 @@ func TestGetReposTop(t *testing.T) {
 
 	fmt.Printf("\n")
 }
+
+func TestGetVulnerabilityDetails(t *testing.T) {
+	assert := assert.New(t)
+	apiTest := newHarborAPI()
+
+	// Harbor is not deployed with Clair in the tests
+	code, _, err := apiTest.GetVulnerabilityDetails(*admin, "library/hello-world", "latest")
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, code)
+}
+
+func TestScanAll(t *testing.T) {
+	assert := assert.New(t)
+	apiTest := newHarborAPI()
+
+	// Harbor is not deployed with Clair in the tests
+	code, err := apiTest.ScanAll(*admin)
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, code)
+
+	code, _, err = apiTest.GetScanAllStatus(*admin)
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, code)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+)
+
+// RobotAPI handles request to /api/projects/{}/robots/{}
+type RobotAPI struct {
+	BaseController
+	project *models.Project
+	robot   *models.Robot
+}
+
+type robotReq struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Actions      []string `json:"actions"`
+	Repositories []string `json:"repositories"`
+	ExpiresAt    int64    `json:"expires_at"`
+	Disabled     bool     `json:"disabled"`
+}
+
+// robotResp contains the token of the robot which is only returned once
+// when the robot is created
+type robotResp struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+// Prepare validates the URL and the user, only the project admins can
+// manage the robots of the project
+func (r *RobotAPI) Prepare() {
+	r.BaseController.Prepare()
+
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	pid, err := r.GetInt64FromPath(":pid")
+	if err != nil || pid <= 0 {
+		text := "invalid project ID: "
+		if err != nil {
+			text += err.Error()
+		} else {
+			text += fmt.Sprintf("%d", pid)
+		}
+		r.HandleBadRequest(text)
+		return
+	}
+	project, err := r.ProjectMgr.Get(pid)
+	if err != nil {
+		r.HandleInternalServerError(
+			fmt.Sprintf("failed to get project %d: %v", pid, err))
+		return
+	}
+	if project == nil {
+		r.HandleNotFound(fmt.Sprintf("project %d not found", pid))
+		return
+	}
+	r.project = project
+
+	if !r.SecurityCtx.Can(models.PermMemberManage, pid) {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(r.GetStringFromPath(":id")) != 0 {
+		id, err := r.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			r.HandleBadRequest(fmt.Sprintf("invalid robot ID: %s", r.GetStringFromPath(":id")))
+			return
+		}
+
+		robot, err := dao.GetRobot(id)
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get robot %d: %v", id, err))
+			return
+		}
+		if robot == nil || robot.ProjectID != pid {
+			r.HandleNotFound(fmt.Sprintf("robot %d not found", id))
+			return
+		}
+		r.robot = robot
+	}
+}
+
+// Get returns the robot specified by ID or all robots of the project
+func (r *RobotAPI) Get() {
+	if r.robot != nil {
+		r.Data["json"] = r.robot
+		r.ServeJSON()
+		return
+	}
+
+	robots, err := dao.GetRobots(r.project.ProjectID)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robots of project %d: %v",
+			r.project.ProjectID, err))
+		return
+	}
+	r.Data["json"] = robots
+	r.ServeJSON()
+}
+
+// Post creates a robot and returns the generated token, the token
+// can not be got again after this
+func (r *RobotAPI) Post() {
+	req := &robotReq{}
+	r.DecodeJSONReq(req)
+
+	robot := &models.Robot{
+		Name:           req.Name,
+		Description:    req.Description,
+		ProjectID:      r.project.ProjectID,
+		ActionList:     req.Actions,
+		RepositoryList: req.Repositories,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if err := robot.Valid(); err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+
+	rb, err := dao.GetRobotByName(robot.ProjectID, robot.Name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get robot %s: %v", robot.Name, err))
+		return
+	}
+	if rb != nil {
+		r.RenderError(http.StatusConflict, fmt.Sprintf("robot %s already exists", robot.Name))
+		return
+	}
+
+	token := utils.GenerateRandomString()
+	robot.Salt = utils.GenerateRandomString()
+	robot.Secret = utils.Encrypt(token, robot.Salt)
+
+	id, err := dao.AddRobot(robot)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to add robot %s: %v", robot.Name, err))
+		return
+	}
+
+	r.Ctx.Output.Header("Location", r.Ctx.Request.RequestURI+"/"+strconv.FormatInt(id, 10))
+	r.Ctx.Output.SetStatus(http.StatusCreated)
+	r.Data["json"] = &robotResp{
+		ID:    id,
+		Name:  models.RobotLoginName(r.project.Name, robot.Name),
+		Token: token,
+	}
+	r.ServeJSON()
+}
+
+// Put updates the description, permissions, expiration and status of
+// the robot, the name can not be changed
+func (r *RobotAPI) Put() {
+	if r.robot == nil {
+		r.HandleBadRequest("robot ID is required")
+		return
+	}
+
+	req := &robotReq{}
+	r.DecodeJSONReq(req)
+
+	r.robot.Description = req.Description
+	r.robot.ActionList = req.Actions
+	r.robot.RepositoryList = req.Repositories
+	r.robot.ExpiresAt = req.ExpiresAt
+	r.robot.Disabled = req.Disabled
+	if err := r.robot.Valid(); err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+
+	if err := dao.UpdateRobot(r.robot); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to update robot %d: %v", r.robot.ID, err))
+		return
+	}
+}
+
+// Delete deletes the robot, its token is revoked immediately
+func (r *RobotAPI) Delete() {
+	if r.robot == nil {
+		r.HandleBadRequest("robot ID is required")
+		return
+	}
+
+	if err := dao.DeleteRobot(r.robot.ID); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete robot %d: %v", r.robot.ID, err))
+		return
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/dghubble/sling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/tests/apitests/apilib"
+)
+
+func TestRobotAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+	robot := apilib.RobotPost{
+		Name:    "robot_for_test_api",
+		Actions: []string{"pull"},
+	}
+
+	// 401
+	code, _, err := apiTest.AddRobot(*unknownUsr, "1", robot)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 404
+	code, _, err = apiTest.AddRobot(*admin, "1000000", robot)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// 400
+	code, _, err = apiTest.AddRobot(*admin, "1", apilib.RobotPost{
+		Name:    "robot_for_test_api",
+		Actions: []string{"delete"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 201
+	code, body, err := apiTest.AddRobot(*admin, "1", robot)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	resp := &robotResp{}
+	require.Nil(t, json.Unmarshal(body, resp))
+	assert.Equal(t, "robot$library+robot_for_test_api", resp.Name)
+	assert.NotEmpty(t, resp.Token)
+	id := strconv.FormatInt(resp.ID, 10)
+	defer func() {
+		code, err := apiTest.DeleteRobot(*admin, "1", id)
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+	}()
+
+	// 409
+	code, _, err = apiTest.AddRobot(*admin, "1", robot)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// list
+	code, robots, err := apiTest.ListRobots(*admin, "1")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, len(robots))
+	assert.Equal(t, []string{"pull"}, robots[0].Actions)
+
+	// update
+	code, err = apiTest.PutRobot(*admin, "1", id, apilib.Robot{
+		Actions:  []string{"pull", "push"},
+		Disabled: true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	code, robots, err = apiTest.ListRobots(*admin, "1")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, len(robots))
+	assert.Equal(t, []string{"pull", "push"}, robots[0].Actions)
+	assert.True(t, robots[0].Disabled)
+}
+
+func TestRobotRepositoryScope(t *testing.T) {
+	apiTest := newHarborAPI()
+	code, body, err := apiTest.AddRobot(*admin, "1", apilib.RobotPost{
+		Name:         "robot_for_test_repository_scope",
+		Actions:      []string{"pull"},
+		Repositories: []string{"library/not-hello-world"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	resp := &robotResp{}
+	require.Nil(t, json.Unmarshal(body, resp))
+	defer func() {
+		code, err := apiTest.DeleteRobot(*admin, "1", strconv.FormatInt(resp.ID, 10))
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusOK, code)
+	}()
+
+	robot := &usrInfo{
+		Name:   resp.Name,
+		Passwd: resp.Token,
+	}
+	repository := "library/hello-world"
+
+	code, _, err = apiTest.GetReposTags(*robot, repository)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, _, err = apiTest.GetTag(*robot, repository, "latest")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// robots have no user resources
+	code, _, err = request(sling.New().Get(apiTest.basePath).Path("/api/users/current"),
+		jsonAcceptHeader, *robot)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+// RoleAPI handles request to /api/roles/{}
+type RoleAPI struct {
+	BaseController
+	role *models.Role
+}
+
+type roleReq struct {
+	Name        string   `json:"role_name"`
+	Permissions []string `json:"permissions"`
+}
+
+// Prepare validates the URL and the user, all the authenticated users can
+// list the roles while only the system admins can manage them
+func (r *RoleAPI) Prepare() {
+	r.BaseController.Prepare()
+
+	if !r.SecurityCtx.IsAuthenticated() {
+		r.HandleUnauthorized()
+		return
+	}
+
+	if r.Ctx.Request.Method != http.MethodGet && !r.SecurityCtx.IsSysAdmin() {
+		r.HandleForbidden(r.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(r.GetStringFromPath(":id")) != 0 {
+		id, err := r.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			r.HandleBadRequest(fmt.Sprintf("invalid role ID: %s", r.GetStringFromPath(":id")))
+			return
+		}
+
+		role, err := dao.GetRoleByID(int(id))
+		if err != nil {
+			r.HandleInternalServerError(fmt.Sprintf("failed to get role %d: %v", id, err))
+			return
+		}
+		if role == nil {
+			r.HandleNotFound(fmt.Sprintf("role %d not found", id))
+			return
+		}
+		r.role = role
+	}
+}
+
+// Get returns the role specified by ID or all roles
+func (r *RoleAPI) Get() {
+	if r.role != nil {
+		r.Data["json"] = r.role
+		r.ServeJSON()
+		return
+	}
+
+	roles, err := dao.ListRoles()
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to list roles: %v", err))
+		return
+	}
+	r.Data["json"] = roles
+	r.ServeJSON()
+}
+
+// Post creates a custom role
+func (r *RoleAPI) Post() {
+	req := &roleReq{}
+	r.DecodeJSONReq(req)
+
+	role := &models.Role{
+		Name:           req.Name,
+		PermissionList: req.Permissions,
+	}
+	if err := role.Valid(); err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+
+	if !r.checkNameAvailable(role.Name) {
+		return
+	}
+
+	id, err := dao.AddRole(role)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to add role %s: %v", role.Name, err))
+		return
+	}
+
+	r.Redirect(http.StatusCreated, strconv.FormatInt(id, 10))
+}
+
+// Put updates the name and permissions of the custom role, the builtin
+// roles can not be modified
+func (r *RoleAPI) Put() {
+	if r.role == nil {
+		r.HandleBadRequest("role ID is required")
+		return
+	}
+
+	if r.role.IsBuiltin() {
+		r.RenderError(http.StatusForbidden,
+			fmt.Sprintf("builtin role %s can not be modified", r.role.Name))
+		return
+	}
+
+	req := &roleReq{}
+	r.DecodeJSONReq(req)
+
+	role := &models.Role{
+		RoleID:         r.role.RoleID,
+		Name:           req.Name,
+		PermissionList: req.Permissions,
+	}
+	if err := role.Valid(); err != nil {
+		r.HandleBadRequest(err.Error())
+		return
+	}
+
+	if role.Name != r.role.Name && !r.checkNameAvailable(role.Name) {
+		return
+	}
+
+	if err := dao.UpdateRole(role); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to update role %d: %v", r.role.RoleID, err))
+		return
+	}
+}
+
+// Delete deletes the custom role, the builtin roles and the roles which
+// are assigned to members can not be deleted
+func (r *RoleAPI) Delete() {
+	if r.role == nil {
+		r.HandleBadRequest("role ID is required")
+		return
+	}
+
+	if r.role.IsBuiltin() {
+		r.RenderError(http.StatusForbidden,
+			fmt.Sprintf("builtin role %s can not be deleted", r.role.Name))
+		return
+	}
+
+	inUse, err := dao.IsRoleInUse(r.role.RoleID)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to check whether role %d is in use: %v",
+			r.role.RoleID, err))
+		return
+	}
+	if inUse {
+		r.RenderError(http.StatusPreconditionFailed,
+			fmt.Sprintf("role %s is assigned to members, can not be deleted", r.role.Name))
+		return
+	}
+
+	if err := dao.DeleteRole(r.role.RoleID); err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to delete role %d: %v", r.role.RoleID, err))
+		return
+	}
+}
+
+// checkNameAvailable renders a conflict error and returns false if the
+// name is used by another role
+func (r *RoleAPI) checkNameAvailable(name string) bool {
+	role, err := dao.GetRoleByName(name)
+	if err != nil {
+		r.HandleInternalServerError(fmt.Sprintf("failed to get role %s: %v", name, err))
+		return false
+	}
+	if role != nil {
+		r.RenderError(http.StatusConflict, fmt.Sprintf("role %s already exists", name))
+		return false
+	}
+	return true
+}
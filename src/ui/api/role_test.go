@@ -0,0 +1,100 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestRoleAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+	role := &roleReq{
+		Name:        "role_for_test_api",
+		Permissions: []string{models.PermProjectRead, models.PermScan},
+	}
+
+	// 401
+	code, _, err := apiTest.AddRole(*unknownUsr, role)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 403
+	code, _, err = apiTest.AddRole(*testUser, role)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// 400
+	code, _, err = apiTest.AddRole(*admin, &roleReq{
+		Name:        "role_for_test_api",
+		Permissions: []string{"repository.copy"},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 201
+	code, _, err = apiTest.AddRole(*admin, role)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, code)
+
+	// 409
+	code, _, err = apiTest.AddRole(*admin, role)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// list, all the authenticated users can list the roles
+	code, roles, err := apiTest.ListRoles(*testUser)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	var id string
+	for _, r := range roles {
+		if r.Name == role.Name {
+			id = strconv.Itoa(r.RoleID)
+			assert.Equal(t, role.Permissions, r.PermissionList)
+		}
+	}
+	require.True(t, len(id) > 0)
+
+	// update
+	role.Permissions = []string{models.PermRepoPull}
+	code, err = apiTest.PutRole(*admin, id, role)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	// the builtin roles can not be modified or deleted
+	guest := strconv.Itoa(common.RoleGuest)
+	code, err = apiTest.PutRole(*admin, guest, role)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, err = apiTest.DeleteRole(*admin, guest)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// 404
+	code, err = apiTest.DeleteRole(*admin, "1000000")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// delete
+	code, err = apiTest.DeleteRole(*admin, id)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -20,7 +20,6 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/utils"
@@ -81,7 +80,7 @@ func (s *SearchAPI) Get() {
 				p.Role = roles[0]
 			}
 
-			if p.Role == common.RoleProjectAdmin || isSysAdmin {
+			if s.SecurityCtx.Can(models.PermPolicyManage, p.ProjectID) {
 				p.Togglable = true
 			}
 		}
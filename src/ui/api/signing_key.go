@@ -0,0 +1,170 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/ui/config"
+	"github.com/vmware/harbor/src/ui/service/token"
+)
+
+// SigningKeyAPI handles request to /api/signing_keys/{}
+type SigningKeyAPI struct {
+	BaseController
+	key *models.SigningKey
+}
+
+type signingKeyReq struct {
+	// Activate is refused when generating a key, the key can only be
+	// activated after it's distributed, see token.KeyDistributionWindow
+	Activate bool `json:"activate"`
+	// Overlap is the minutes during which the previous active key is still
+	// published, it defaults to the token expiration
+	Overlap int `json:"overlap"`
+}
+
+// Prepare validates the URL and the user, only system admins can manage the
+// signing keys
+func (s *SigningKeyAPI) Prepare() {
+	s.BaseController.Prepare()
+
+	if !s.SecurityCtx.IsAuthenticated() {
+		s.HandleUnauthorized()
+		return
+	}
+
+	if !s.SecurityCtx.IsSysAdmin() {
+		s.HandleForbidden(s.SecurityCtx.GetUsername())
+		return
+	}
+
+	if len(s.GetStringFromPath(":id")) != 0 {
+		id, err := s.GetInt64FromPath(":id")
+		if err != nil || id <= 0 {
+			s.HandleBadRequest(fmt.Sprintf("invalid signing key ID: %s", s.GetStringFromPath(":id")))
+			return
+		}
+
+		key, err := dao.GetSigningKey(id)
+		if err != nil {
+			s.HandleInternalServerError(fmt.Sprintf("failed to get signing key %d: %v", id, err))
+			return
+		}
+		if key == nil {
+			s.HandleNotFound(fmt.Sprintf("signing key %d not found", id))
+			return
+		}
+		s.key = key
+	}
+}
+
+// Get returns the signing key specified by ID or all signing keys, the
+// private keys are never returned
+func (s *SigningKeyAPI) Get() {
+	if s.key != nil {
+		s.Data["json"] = s.key
+		s.ServeJSON()
+		return
+	}
+
+	keys, err := dao.ListSigningKeys()
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to list signing keys: %v", err))
+		return
+	}
+	s.Data["json"] = keys
+	s.ServeJSON()
+}
+
+// Post generates a signing key, it is only published so that it can be
+// distributed to the services verifying the tokens before being activated
+func (s *SigningKeyAPI) Post() {
+	req := &signingKeyReq{}
+	s.DecodeJSONReq(req)
+	if req.Activate {
+		s.HandleBadRequest("the signing key generated can not be activated at once, activate it after its certificate is appended to the root certificate bundles")
+		return
+	}
+
+	key, err := token.GenerateSigningKey()
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to generate signing key: %v", err))
+		return
+	}
+
+	s.Ctx.Output.Header("Location", s.Ctx.Request.RequestURI+"/"+strconv.FormatInt(key.ID, 10))
+	s.Ctx.Output.SetStatus(http.StatusCreated)
+	s.Data["json"] = key
+	s.ServeJSON()
+}
+
+// Put activates the signing key, the previous active key is retired after
+// the overlap. The key can't be activated within the distribution window
+// after it's generated
+func (s *SigningKeyAPI) Put() {
+	if s.key == nil {
+		s.HandleBadRequest("signing key ID is required")
+		return
+	}
+	if s.key.IsExpired() {
+		s.RenderError(http.StatusPreconditionFailed,
+			fmt.Sprintf("signing key %d is expired, can not be activated", s.key.ID))
+		return
+	}
+
+	req := &signingKeyReq{}
+	s.DecodeJSONReq(req)
+
+	overlap, ok := s.overlap(req.Overlap)
+	if !ok {
+		return
+	}
+	err := token.ActivateSigningKey(s.key.ID, overlap)
+	if err == token.ErrKeyNotDistributed {
+		s.RenderError(http.StatusPreconditionFailed,
+			fmt.Sprintf("signing key %d can not be activated within %v after it is generated, append its certificate to the root certificate bundles before activating it",
+				s.key.ID, token.KeyDistributionWindow))
+		return
+	}
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to activate signing key %d: %v", s.key.ID, err))
+		return
+	}
+}
+
+// overlap returns the overlap in the request or the default one, it renders
+// a bad request error and returns false if the overlap is shorter than the
+// expiration of tokens
+func (s *SigningKeyAPI) overlap(minutes int) (time.Duration, bool) {
+	expiration, err := config.TokenExpiration()
+	if err != nil {
+		s.HandleInternalServerError(fmt.Sprintf("failed to get token expiration: %v", err))
+		return 0, false
+	}
+	if minutes == 0 {
+		minutes = expiration
+	}
+	if minutes < expiration {
+		s.HandleBadRequest(fmt.Sprintf("overlap %d is shorter than the token expiration %d", minutes, expiration))
+		return 0, false
+	}
+	return time.Duration(minutes) * time.Minute, true
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego/orm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/ui/service/token"
+)
+
+func TestSigningKeyAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+
+	// 401
+	code, _, err := apiTest.AddSigningKey(*unknownUsr, &signingKeyReq{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 403
+	code, _, err = apiTest.AddSigningKey(*testUser, &signingKeyReq{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// 400, the key generated can't be activated at once
+	code, _, err = apiTest.AddSigningKey(*admin, &signingKeyReq{
+		Activate: true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 201, the key is not activated
+	code, body, err := apiTest.AddSigningKey(*admin, &signingKeyReq{})
+	require.Nil(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	key := &models.SigningKey{}
+	require.Nil(t, json.Unmarshal(body, key))
+	assert.False(t, key.Active)
+	assert.True(t, len(key.KeyID) > 0)
+	assert.True(t, len(key.Certificate) > 0)
+	id := strconv.FormatInt(key.ID, 10)
+
+	// 400, the overlap is shorter than the token expiration
+	code, err = apiTest.ActivateSigningKey(*admin, id, &signingKeyReq{
+		Overlap: 1,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 404
+	code, err = apiTest.ActivateSigningKey(*admin, "1000000", &signingKeyReq{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// 412, the key is younger than the distribution window
+	code, err = apiTest.ActivateSigningKey(*admin, id, &signingKeyReq{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, code)
+
+	// activate after the distribution window
+	_, err = dao.GetOrmer().QueryTable(models.SigningKeyTable).
+		Filter("id", key.ID).
+		Update(orm.Params{
+			"creation_time": time.Now().Add(-token.KeyDistributionWindow),
+		})
+	require.Nil(t, err)
+	code, err = apiTest.ActivateSigningKey(*admin, id, &signingKeyReq{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, k, err := apiTest.GetSigningKey(*admin, id)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, k.Active)
+	assert.Equal(t, key.KeyID, k.KeyID)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	totputils "github.com/vmware/harbor/src/common/utils/totp"
+)
+
+func TestTOTPAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+
+	user, err := dao.GetUser(models.User{Username: testUser.Name})
+	require.Nil(t, err)
+	require.NotNil(t, user)
+	defer dao.DeleteUserTOTP(user.UserID)
+
+	// 401
+	code, _, err := apiTest.GetTOTP(*unknownUsr)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// not enabled
+	code, body, err := apiTest.GetTOTP(*testUser)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	status := map[string]bool{}
+	require.Nil(t, json.Unmarshal(body, &status))
+	assert.False(t, status["enabled"])
+
+	// verify before enrolling
+	code, _, err = apiTest.ActivateTOTP(*testUser, "123456")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, code)
+
+	// enroll
+	code, body, err = apiTest.EnrollTOTP(*testUser)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	enrollment := &models.TOTPEnrollment{}
+	require.Nil(t, json.Unmarshal(body, enrollment))
+	assert.NotEmpty(t, enrollment.ProvisioningURI)
+
+	// invalid code
+	code, _, err = apiTest.ActivateTOTP(*testUser, "abcdef")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// activate
+	otp, err := totputils.Code(enrollment.Secret, totputils.StepOf(time.Now()))
+	require.Nil(t, err)
+	code, body, err = apiTest.ActivateTOTP(*testUser, otp)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	activation := &models.TOTPActivation{}
+	require.Nil(t, json.Unmarshal(body, activation))
+	assert.Equal(t, 10, len(activation.RecoveryCodes))
+	require.True(t, len(activation.CLISecret) > 0)
+
+	// the password alone is not accepted by basic auth any more
+	code, _, err = apiTest.GetTOTP(*testUser)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// the CLI secret is accepted
+	cliUser := &usrInfo{testUser.Name, activation.CLISecret}
+	code, body, err = apiTest.GetTOTP(*cliUser)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Nil(t, json.Unmarshal(body, &status))
+	assert.True(t, status["enabled"])
+
+	// enroll again
+	code, _, err = apiTest.EnrollTOTP(*cliUser)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, code)
+
+	// it can't be disabled with the CLI secret even if the code is valid
+	code, err = apiTest.DisableTOTP(*cliUser, activation.RecoveryCodes[0])
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	require.Nil(t, dao.DeleteUserTOTP(user.UserID))
+	code, _, err = apiTest.GetTOTP(*testUser)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+}
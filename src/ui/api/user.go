@@ -23,7 +23,12 @@ import (
 
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/security/accesstoken"
+	"github.com/vmware/harbor/src/common/security/robot"
 	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/ui/auth"
+	"github.com/vmware/harbor/src/ui/auth/oidc"
+	"github.com/vmware/harbor/src/ui/auth/totp"
 	"github.com/vmware/harbor/src/ui/config"
 )
 
@@ -69,6 +74,11 @@ func (ua *UserAPI) Prepare() {
 		return
 	}
 
+	// only users have the user resources, robot accounts don't
+	if _, ok := ua.SecurityCtx.(*robot.SecurityContext); ok {
+		ua.CustomAbort(http.StatusForbidden, "robot accounts can not access the user API")
+	}
+
 	user, err := dao.GetUser(models.User{
 		Username: ua.SecurityCtx.GetUsername(),
 	})
@@ -77,6 +87,10 @@ func (ua *UserAPI) Prepare() {
 			ua.SecurityCtx.GetUsername(), err))
 		return
 	}
+	if user == nil {
+		log.Errorf("user %s of the security context not found", ua.SecurityCtx.GetUsername())
+		ua.CustomAbort(http.StatusUnauthorized, "")
+	}
 
 	ua.currentUserID = user.UserID
 	id := ua.Ctx.Input.Param(":id")
@@ -160,7 +174,8 @@ func (ua *UserAPI) List() {
 
 // Put ...
 func (ua *UserAPI) Put() {
-	ldapAdminUser := (ua.AuthMode == "ldap_auth" && ua.userID == 1 && ua.userID == ua.currentUserID)
+	ldapAdminUser := ((ua.AuthMode == "ldap_auth" || ua.AuthMode == "oidc_auth") &&
+		ua.userID == 1 && ua.userID == ua.currentUserID)
 
 	if !(ua.AuthMode == "db_auth" || ldapAdminUser) {
 		ua.CustomAbort(http.StatusForbidden, "")
@@ -268,6 +283,10 @@ func (ua *UserAPI) Delete() {
 		ua.CustomAbort(http.StatusForbidden, "user can not be deleted in LDAP authentication mode")
 	}
 
+	if ua.AuthMode == "oidc_auth" {
+		ua.CustomAbort(http.StatusForbidden, "user can not be deleted in OIDC authentication mode")
+	}
+
 	if ua.currentUserID == ua.userID {
 		ua.CustomAbort(http.StatusForbidden, "can not delete yourself")
 	}
@@ -284,11 +303,15 @@ func (ua *UserAPI) Delete() {
 		ua.RenderError(http.StatusInternalServerError, "Failed to delete User")
 		return
 	}
+	if err = auth.RevokeSessions(ua.userID, ""); err != nil {
+		log.Errorf("failed to revoke the sessions of user %d: %v", ua.userID, err)
+	}
 }
 
 // ChangePassword handles PUT to /api/users/{}/password
 func (ua *UserAPI) ChangePassword() {
-	ldapAdminUser := (ua.AuthMode == "ldap_auth" && ua.userID == 1 && ua.userID == ua.currentUserID)
+	ldapAdminUser := ((ua.AuthMode == "ldap_auth" || ua.AuthMode == "oidc_auth") &&
+		ua.userID == 1 && ua.userID == ua.currentUserID)
 
 	if !(ua.AuthMode == "db_auth" || ldapAdminUser) {
 		ua.CustomAbort(http.StatusForbidden, "")
@@ -322,12 +345,21 @@ func (ua *UserAPI) ChangePassword() {
 	if req.NewPassword == "" {
 		ua.CustomAbort(http.StatusBadRequest, "please_input_new_password")
 	}
+	if err := validatePassword(req.NewPassword); err != nil {
+		log.Warningf("Bad request in ChangePassword: %v", err)
+		ua.RenderError(http.StatusBadRequest, err.Error())
+		return
+	}
 	updateUser := models.User{UserID: ua.userID, Password: req.NewPassword, Salt: user.Salt}
 	err = dao.ChangeUserPassword(updateUser, req.OldPassword)
 	if err != nil {
 		log.Errorf("Error occurred in ChangeUserPassword: %v", err)
 		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
 	}
+	// the session which is changing the password is kept
+	if err = auth.RevokeSessions(ua.userID, ua.sessionID()); err != nil {
+		log.Errorf("failed to revoke the sessions of user %d: %v", ua.userID, err)
+	}
 }
 
 // ToggleUserAdminRole handles PUT api/users/{}/sysadmin
@@ -354,8 +386,8 @@ func validate(user models.User) error {
 	if isContainIllegalChar(user.Username, []string{",", "~", "#", "$", "%"}) {
 		return fmt.Errorf("username contains illegal characters")
 	}
-	if isIllegalLength(user.Password, 8, 20) {
-		return fmt.Errorf("password with illegal length")
+	if err := validatePassword(user.Password); err != nil {
+		return err
 	}
 	if err := commonValidate(user); err != nil {
 		return err
@@ -363,6 +395,15 @@ func validate(user models.User) error {
 	return nil
 }
 
+// validatePassword checks the password against the configured password policy
+func validatePassword(password string) error {
+	policy, err := config.PasswordPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to get password policy: %v", err)
+	}
+	return policy.Validate(password)
+}
+
 //commonValidate validates email, realname, comment information when user register or change their profile
 func commonValidate(user models.User) error {
 
@@ -406,3 +447,235 @@ func isContainIllegalChar(s string, illegalChar []string) bool {
 	}
 	return false
 }
+
+// GetCLISecret handles GET to /api/users/{}/cli_secret, it returns the
+// secret which the user logging in via OIDC provider or enabling two-factor
+// authentication uses for docker login
+func (ua *UserAPI) GetCLISecret() {
+	ua.refuseAccessToken()
+	if ua.userID != ua.currentUserID {
+		ua.CustomAbort(http.StatusForbidden, "users can only get their own CLI secrets")
+	}
+
+	var secret string
+	var err error
+	if ua.viaOIDC() {
+		secret, err = oidc.GetCLISecret(ua.userID)
+	} else {
+		secret, err = totp.GetCLISecret(ua.userID)
+	}
+	if err != nil {
+		log.Errorf("failed to get CLI secret of user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+	if len(secret) == 0 {
+		ua.CustomAbort(http.StatusNotFound, "the user neither logs in via OIDC provider nor enables two-factor authentication")
+	}
+
+	ua.Data["json"] = map[string]string{
+		"secret": secret,
+	}
+	ua.ServeJSON()
+}
+
+// RegenerateCLISecret handles PUT to /api/users/{}/cli_secret, it generates
+// a new CLI secret for the user and returns it
+func (ua *UserAPI) RegenerateCLISecret() {
+	ua.refuseAccessToken()
+	if ua.userID != ua.currentUserID {
+		ua.CustomAbort(http.StatusForbidden, "users can only regenerate their own CLI secrets")
+	}
+
+	var secret string
+	var err error
+	if ua.viaOIDC() {
+		secret, err = oidc.RegenerateCLISecret(ua.userID)
+	} else {
+		var enabled bool
+		enabled, err = totp.Enabled(ua.userID)
+		if err != nil {
+			log.Errorf("failed to check two-factor authentication of user %d: %v", ua.userID, err)
+			ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+		}
+		if !enabled {
+			ua.CustomAbort(http.StatusPreconditionFailed, "two-factor authentication is not enabled")
+		}
+		secret, err = totp.RegenerateCLISecret(ua.userID)
+	}
+	if err != nil {
+		log.Errorf("failed to regenerate CLI secret of user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+
+	ua.Data["json"] = map[string]string{
+		"secret": secret,
+	}
+	ua.ServeJSON()
+}
+
+// refuseAccessToken aborts the request if it is authenticated by a personal
+// access token, the CLI secret and the second factor grant more than any
+// access token does, so they can't be managed with access tokens
+func (ua *UserAPI) refuseAccessToken() {
+	if _, ok := ua.SecurityCtx.(*accesstoken.SecurityContext); ok {
+		ua.CustomAbort(http.StatusForbidden, "access tokens can not be used to manage the CLI secret and two-factor authentication")
+	}
+}
+
+// viaSession returns whether the request is authenticated by the login
+// session rather than the credential in the request, e.g. the CLI secret
+func (ua *UserAPI) viaSession() bool {
+	if _, _, ok := ua.Ctx.Request.BasicAuth(); ok {
+		return false
+	}
+	username, ok := ua.GetSession("username").(string)
+	return ok && username == ua.SecurityCtx.GetUsername()
+}
+
+// viaOIDC returns whether the user logs in via OIDC provider, the admin
+// always logs in with the password
+func (ua *UserAPI) viaOIDC() bool {
+	return ua.AuthMode == "oidc_auth" && ua.userID != 1
+}
+
+type totpReq struct {
+	Code string `json:"code"`
+}
+
+// GetTOTP handles GET to /api/users/{}/totp, it returns whether the user
+// enables two-factor authentication
+func (ua *UserAPI) GetTOTP() {
+	ua.refuseAccessToken()
+	if !(ua.userID == ua.currentUserID || ua.IsAdmin) {
+		ua.CustomAbort(http.StatusForbidden, "")
+	}
+
+	enabled, err := totp.Enabled(ua.userID)
+	if err != nil {
+		log.Errorf("failed to check two-factor authentication of user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+	ua.Data["json"] = map[string]bool{
+		"enabled": enabled,
+	}
+	ua.ServeJSON()
+}
+
+// EnrollTOTP handles POST to /api/users/{}/totp, it generates the secret
+// of one-time passwords for the user and returns the provisioning URI
+func (ua *UserAPI) EnrollTOTP() {
+	if !ua.SecurityCtx.IsAuthenticated() {
+		ua.HandleUnauthorized()
+		return
+	}
+	ua.refuseAccessToken()
+	if ua.userID != ua.currentUserID {
+		ua.CustomAbort(http.StatusForbidden, "users can only enroll themselves")
+	}
+	if ua.viaOIDC() {
+		ua.CustomAbort(http.StatusPreconditionFailed, "the users logging in via OIDC provider use the second factor of the provider")
+	}
+
+	user, err := dao.GetUser(models.User{UserID: ua.userID})
+	if err != nil {
+		log.Errorf("failed to get user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+	enrollment, err := totp.Enroll(user)
+	if err == totp.ErrAlreadyEnabled {
+		ua.CustomAbort(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		log.Errorf("failed to enroll user %d in two-factor authentication: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+
+	ua.Data["json"] = enrollment
+	ua.ServeJSON()
+}
+
+// ActivateTOTP handles PUT to /api/users/{}/totp, it verifies the enrollment
+// with a one-time password and returns the recovery codes and CLI secret
+func (ua *UserAPI) ActivateTOTP() {
+	ua.refuseAccessToken()
+	if ua.userID != ua.currentUserID {
+		ua.CustomAbort(http.StatusForbidden, "users can only verify their own enrollments")
+	}
+
+	req := &totpReq{}
+	ua.DecodeJSONReq(req)
+	if len(req.Code) == 0 {
+		ua.CustomAbort(http.StatusBadRequest, "code is required")
+	}
+
+	activation, err := totp.Activate(ua.userID, req.Code)
+	if err == totp.ErrNotEnrolled || err == totp.ErrAlreadyEnabled {
+		ua.CustomAbort(http.StatusPreconditionFailed, err.Error())
+	}
+	if err != nil {
+		log.Errorf("failed to activate two-factor authentication of user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+	if activation == nil {
+		ua.CustomAbort(http.StatusBadRequest, "invalid code")
+	}
+
+	// grant the admin privileges which are held back until the enrollment
+	// when two-factor authentication is required for admins
+	user, err := dao.GetUser(models.User{UserID: ua.userID})
+	if err != nil {
+		log.Errorf("failed to get user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+	if user.HasAdminRole == 1 && ua.GetSession("username") != nil {
+		ua.SetSession("isSysAdmin", true)
+	}
+
+	ua.Data["json"] = activation
+	ua.ServeJSON()
+}
+
+// DisableTOTP handles DELETE to /api/users/{}/totp, users must confirm it
+// with a one-time password or a recovery code, admins can disable it for
+// users who lose their devices and recovery codes. It can only be called
+// in a login session so that a leaked CLI secret can't remove the second
+// factor
+func (ua *UserAPI) DisableTOTP() {
+	ua.refuseAccessToken()
+	if !ua.viaSession() {
+		ua.CustomAbort(http.StatusForbidden, "two-factor authentication can only be disabled in a login session")
+	}
+	if !(ua.userID == ua.currentUserID || ua.IsAdmin) {
+		ua.CustomAbort(http.StatusForbidden, "")
+	}
+
+	if ua.userID == ua.currentUserID {
+		enabled, err := totp.Enabled(ua.userID)
+		if err != nil {
+			log.Errorf("failed to check two-factor authentication of user %d: %v", ua.userID, err)
+			ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+		}
+		if !enabled {
+			return
+		}
+
+		req := &totpReq{}
+		ua.DecodeJSONReq(req)
+		if len(req.Code) == 0 {
+			ua.CustomAbort(http.StatusBadRequest, "code is required")
+		}
+		valid, err := totp.Verify(ua.userID, req.Code)
+		if err != nil {
+			log.Errorf("failed to verify the code of user %d: %v", ua.userID, err)
+			ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+		}
+		if !valid {
+			ua.CustomAbort(http.StatusBadRequest, "invalid code")
+		}
+	}
+
+	if err := totp.Disable(ua.userID); err != nil {
+		log.Errorf("failed to disable two-factor authentication of user %d: %v", ua.userID, err)
+		ua.CustomAbort(http.StatusInternalServerError, "Internal error.")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/security/accesstoken"
+	"github.com/vmware/harbor/src/ui/auth"
+)
+
+// UserSessionAPI handles request to /api/users/{}/sessions/{}
+type UserSessionAPI struct {
+	BaseController
+	userID  int
+	session *models.UserSession
+}
+
+// Prepare validates the URL and the user, users can list and revoke their
+// own sessions and system admins can do it for others. The sessions can not
+// be managed by the requests authenticated by access tokens
+func (u *UserSessionAPI) Prepare() {
+	u.BaseController.Prepare()
+
+	if !u.SecurityCtx.IsAuthenticated() {
+		u.HandleUnauthorized()
+		return
+	}
+	if _, ok := u.SecurityCtx.(*accesstoken.SecurityContext); ok {
+		u.RenderError(http.StatusForbidden, "access tokens can not be used to manage sessions")
+		return
+	}
+
+	user, err := dao.GetUser(models.User{
+		Username: u.SecurityCtx.GetUsername(),
+	})
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to get user %s: %v",
+			u.SecurityCtx.GetUsername(), err))
+		return
+	}
+	if user == nil {
+		u.HandleUnauthorized()
+		return
+	}
+
+	id := u.GetStringFromPath(":id")
+	if id == "current" {
+		u.userID = user.UserID
+	} else {
+		u.userID, err = strconv.Atoi(id)
+		if err != nil || u.userID <= 0 {
+			u.HandleBadRequest(fmt.Sprintf("invalid user ID: %s", id))
+			return
+		}
+	}
+
+	if u.userID != user.UserID {
+		if !u.SecurityCtx.IsSysAdmin() {
+			u.HandleForbidden(u.SecurityCtx.GetUsername())
+			return
+		}
+		usr, err := dao.GetUser(models.User{
+			UserID: u.userID,
+		})
+		if err != nil {
+			u.HandleInternalServerError(fmt.Sprintf("failed to get user %d: %v", u.userID, err))
+			return
+		}
+		if usr == nil {
+			u.HandleNotFound(fmt.Sprintf("user %d not found", u.userID))
+			return
+		}
+	}
+
+	if len(u.GetStringFromPath(":sid")) != 0 {
+		sid, err := u.GetInt64FromPath(":sid")
+		if err != nil || sid <= 0 {
+			u.HandleBadRequest(fmt.Sprintf("invalid session ID: %s", u.GetStringFromPath(":sid")))
+			return
+		}
+
+		session, err := dao.GetUserSession(sid)
+		if err != nil {
+			u.HandleInternalServerError(fmt.Sprintf("failed to get session %d: %v", sid, err))
+			return
+		}
+		if session == nil || session.UserID != u.userID {
+			u.HandleNotFound(fmt.Sprintf("session %d not found", sid))
+			return
+		}
+		u.session = session
+	}
+}
+
+// Get returns the active sessions of the user, the one the request is sent
+// with is marked as current
+func (u *UserSessionAPI) Get() {
+	sessions, err := auth.ListSessions(u.userID)
+	if err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to list sessions of user %d: %v",
+			u.userID, err))
+		return
+	}
+
+	current := ""
+	if sid := u.sessionID(); len(sid) > 0 {
+		current = auth.HashSessionID(sid)
+	}
+	for _, session := range sessions {
+		session.Current = session.SessionID == current
+	}
+	u.Data["json"] = sessions
+	u.ServeJSON()
+}
+
+// Delete revokes the session specified by ID, or all the sessions of the
+// user except the one the request is sent with if no ID is specified
+func (u *UserSessionAPI) Delete() {
+	if u.session != nil {
+		if err := dao.DeleteUserSession(u.session.ID); err != nil {
+			u.HandleInternalServerError(fmt.Sprintf("failed to delete session %d: %v", u.session.ID, err))
+		}
+		return
+	}
+
+	if err := auth.RevokeSessions(u.userID, u.sessionID()); err != nil {
+		u.HandleInternalServerError(fmt.Sprintf("failed to revoke sessions of user %d: %v", u.userID, err))
+	}
+}
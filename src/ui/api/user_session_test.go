@@ -0,0 +1,98 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/ui/auth"
+)
+
+func TestUserSessionAPI(t *testing.T) {
+	apiTest := newHarborAPI()
+	userID := strconv.Itoa(CommonGetUserID())
+	require.Nil(t, auth.RegisterSession("session_for_test_api_1", CommonGetUserID(), "127.0.0.1", "firefox"))
+	require.Nil(t, auth.RegisterSession("session_for_test_api_2", CommonGetUserID(), "127.0.0.1", "chrome"))
+	defer auth.RevokeSessions(CommonGetUserID(), "")
+
+	// 401
+	code, _, err := apiTest.ListUserSessions(*unknownUsr, "current")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// 403, the sessions of others can only be managed by admins
+	code, _, err = apiTest.ListUserSessions(*testUser, "1")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// list
+	code, sessions, err := apiTest.ListUserSessions(*testUser, "current")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 2, len(sessions))
+	assert.False(t, sessions[0].Current)
+
+	// the admin can list the sessions of others
+	code, sessions, err = apiTest.ListUserSessions(*admin, userID)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 2, len(sessions))
+	sessionID := strconv.FormatInt(sessions[0].ID, 10)
+
+	// 404, the session belongs to another user
+	code, err = apiTest.DeleteUserSession(*admin, "current", sessionID)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// revoke one session
+	code, err = apiTest.DeleteUserSession(*testUser, "current", sessionID)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, sessions, err = apiTest.ListUserSessions(*testUser, "current")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(sessions))
+
+	// the admin revokes all sessions of the user
+	code, err = apiTest.DeleteUserSession(*admin, userID, "")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, sessions, err = apiTest.ListUserSessions(*testUser, "current")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, len(sessions))
+
+	// the expired sessions are not listed and are pruned
+	require.Nil(t, auth.RegisterSession("session_for_test_api_3", CommonGetUserID(), "127.0.0.1", "safari"))
+	session, err := dao.GetUserSessionBySessionID(auth.HashSessionID("session_for_test_api_3"))
+	require.Nil(t, err)
+	require.NotNil(t, session)
+	require.Nil(t, dao.UpdateUserSessionLastActive(session.ID, time.Now().Add(-24*time.Hour)))
+	code, sessions, err = apiTest.ListUserSessions(*testUser, "current")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, len(sessions))
+	session, err = dao.GetUserSession(session.ID)
+	require.Nil(t, err)
+	assert.Nil(t, session)
+}
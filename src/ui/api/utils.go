@@ -126,10 +126,20 @@ func TriggerReplicationByRepository(repository string, tags []string, operation
 	}
 
 	for _, policy := range policies {
-		if policy.Enabled == 0 {
+		// the pull policies are not triggered by the changes of local repositories
+		if policy.Enabled == 0 || policy.IsPull() {
 			continue
 		}
-		if err := TriggerReplication(policy.ID, repository, tags, operation); err != nil {
+		if !policy.MatchRepository(repository) {
+			log.Debugf("repository %s is filtered out by policy %d", repository, policy.ID)
+			continue
+		}
+		filtered := policy.FilterTags(tags)
+		if len(tags) > 0 && len(filtered) == 0 {
+			log.Debugf("tags %v of %s are filtered out by policy %d", tags, repository, policy.ID)
+			continue
+		}
+		if err := TriggerReplication(policy.ID, repository, filtered, operation); err != nil {
 			log.Errorf("failed to trigger replication of policy %d for %s: %v", policy.ID, repository, err)
 		} else {
 			log.Infof("replication of policy %d for %s triggered", policy.ID, repository)
@@ -434,6 +444,11 @@ func buildScanJobURL() string {
 	return fmt.Sprintf("%s/api/jobs/scan", url)
 }
 
+func buildScanAllJobURL() string {
+	url := config.InternalJobServiceURL()
+	return fmt.Sprintf("%s/api/jobs/scan/all", url)
+}
+
 func buildReplicationURL() string {
 	url := config.InternalJobServiceURL()
 	return fmt.Sprintf("%s/api/jobs/replication", url)
@@ -533,6 +548,11 @@ func TriggerImageScan(repository string, tag string) error {
 	return requestAsUI("POST", url, bytes.NewBuffer(b), http.StatusOK)
 }
 
+// TriggerScanAll triggers the job service to scan all images in background
+func TriggerScanAll() error {
+	return requestAsUI("POST", buildScanAllJobURL(), nil, http.StatusOK)
+}
+
 // Do not use this when you want to handle the response
 // TODO: add a response handler to replace expectSC *when needed*
 func requestAsUI(method, url string, body io.Reader, expectSC int) error {
@@ -0,0 +1,88 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/ui/auth/totp"
+)
+
+// the last used time of a token is updated at most once within the interval
+// to avoid writing the database on every request
+const lastUsedInterval = time.Minute
+
+// LoginByAccessToken authenticates the personal access token and returns the
+// owner of the token along with it, the user is nil if the token is invalid,
+// expired or the owner has been deleted. The groups of the owner are not
+// included as they are only resolved when the owner logs in
+func LoginByAccessToken(token string) (*models.User, *models.AccessToken, error) {
+	id, secret, err := models.ParseAccessToken(token)
+	if err != nil {
+		log.Debugf("invalid access token: %v", err)
+		return nil, nil, nil
+	}
+	accessToken, err := dao.GetAccessToken(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if accessToken == nil ||
+		subtle.ConstantTimeCompare([]byte(utils.Encrypt(secret, accessToken.Salt)),
+			[]byte(accessToken.Secret)) != 1 {
+		log.Debugf("invalid access token %d", id)
+		return nil, nil, nil
+	}
+	if accessToken.IsExpired() {
+		log.Debugf("access token %d is expired", id)
+		return nil, nil, nil
+	}
+
+	user, err := dao.GetUser(models.User{
+		UserID: accessToken.UserID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, nil
+	}
+	// the token is created after the user logged in with the second factor,
+	// only the admins who haven't enrolled need to be checked
+	enabled, err := totp.Enabled(user.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !enabled {
+		if user, err = checkAdminEnrollment(user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	now := time.Now()
+	if now.Sub(time.Unix(accessToken.LastUsedAt, 0)) >= lastUsedInterval {
+		if err := dao.UpdateAccessTokenLastUsed(accessToken.ID, now); err != nil {
+			log.Errorf("failed to update the last used time of access token %d: %v",
+				accessToken.ID, err)
+		} else {
+			accessToken.LastUsedAt = now.Unix()
+		}
+	}
+	return user, accessToken, nil
+}
@@ -14,26 +14,48 @@
 package auth
 
 import (
+	"net/http"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
 )
 
-var l = NewUserLock(2 * time.Second)
+func TestLockDuration(t *testing.T) {
+	base := 5 * time.Minute
+	assert.Equal(t, base, lockDuration(base, 0))
+	assert.Equal(t, 10*time.Minute, lockDuration(base, 1))
+	assert.Equal(t, 40*time.Minute, lockDuration(base, 3))
+	assert.Equal(t, maxLockDuration, lockDuration(base, 100))
+	assert.Equal(t, maxLockDuration, lockDuration(48*time.Hour, 0))
+}
+
+func TestClientIP(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://ui/login", nil)
+	require.Nil(t, err)
+	req.RemoteAddr = "10.0.0.2:45678"
+	req.Header.Set("X-Forwarded-For", "10.0.0.3")
+	assert.Equal(t, "10.0.0.2", ClientIP(req))
+
+	req.Header.Set("X-Real-IP", "10.0.0.1")
+	assert.Equal(t, "10.0.0.1", ClientIP(req))
+}
+
+func TestFailureKeys(t *testing.T) {
+	keys := failureKeys(models.AuthModel{
+		Principal: "john",
+	})
+	assert.Equal(t, 1, len(keys))
+	assert.Equal(t, models.LoginFailureTypeUser, keys[0].Type)
+	assert.Equal(t, "john", keys[0].Name)
 
-func TestLock(t *testing.T) {
-	t.Log("Locking john")
-	l.Lock("john")
-	if !l.IsLocked("john") {
-		t.Errorf("John should be locked")
-	}
-	t.Log("Locking jack")
-	l.Lock("jack")
-	t.Log("Sleep for 2 seconds and check...")
-	time.Sleep(2 * time.Second)
-	if l.IsLocked("jack") {
-		t.Errorf("After 2 seconds, jack shouldn't be locked")
-	}
-	if l.IsLocked("daniel") {
-		t.Errorf("daniel has never been locked, he should not be locked")
-	}
+	keys = failureKeys(models.AuthModel{
+		Principal:  "john",
+		RemoteAddr: "10.0.0.1",
+	})
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, models.LoginFailureTypeIP, keys[1].Type)
+	assert.Equal(t, "10.0.0.1", keys[1].Name)
 }
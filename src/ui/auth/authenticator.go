@@ -15,6 +15,7 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,11 +24,6 @@ import (
 	"github.com/vmware/harbor/src/ui/config"
 )
 
-// 1.5 seconds
-const frozenTime time.Duration = 1500 * time.Millisecond
-
-var lock = NewUserLock(frozenTime)
-
 // Authenticator provides interface to authenticate user credentials.
 type Authenticator interface {
 
@@ -46,8 +42,24 @@ func Register(name string, authenticator Authenticator) {
 	registry[name] = authenticator
 }
 
-// Login authenticates user credentials based on setting.
+// ErrOTPRequired is returned by Login when the password is correct but the
+// user enables two-factor authentication and no one-time password is provided
+var ErrOTPRequired = errors.New("one-time password is required")
+
+// Login authenticates user credentials based on setting, the users who enable
+// two-factor authentication must provide the one-time passwords as well.
 func Login(m models.AuthModel) (*models.User, error) {
+	return login(m, false)
+}
+
+// LoginCLI authenticates the credentials of non-interactive clients, e.g.
+// docker login and API calls with basic auth, the users who enable two-factor
+// authentication use their CLI secrets instead of the passwords.
+func LoginCLI(m models.AuthModel) (*models.User, error) {
+	return login(m, true)
+}
+
+func login(m models.AuthModel, cli bool) (*models.User, error) {
 
 	authMode, err := config.AuthMode()
 	if err != nil {
@@ -62,15 +74,29 @@ func Login(m models.AuthModel) (*models.User, error) {
 	if !ok {
 		return nil, fmt.Errorf("Unrecognized auth_mode: %s", authMode)
 	}
-	if lock.IsLocked(m.Principal) {
-		log.Debugf("%s is locked due to login failure, login failed", m.Principal)
+	now := time.Now()
+	locked, err := isLocked(m, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the lockout of %s: %v", m.Principal, err)
+	}
+	if locked {
+		log.Debugf("%s is locked due to login failures, login failed", m.Principal)
 		return nil, nil
 	}
 	user, err := authenticator.Authenticate(m)
+	if err == nil {
+		user, err = secondFactor(m, user, cli)
+	}
 	if user == nil && err == nil {
-		log.Debugf("Login failed, locking %s, and sleep for %v", m.Principal, frozenTime)
-		lock.Lock(m.Principal)
-		time.Sleep(frozenTime)
+		log.Debugf("Login failed, recording the failure of %s", m.Principal)
+		if e := recordFailure(m, now); e != nil {
+			log.Errorf("failed to record the login failure of %s: %v", m.Principal, e)
+		}
+	}
+	if user != nil {
+		if e := clearFailures(m); e != nil {
+			log.Errorf("failed to clear the login failures of %s: %v", m.Principal, e)
+		}
 	}
 	return user, err
 }
@@ -107,6 +107,12 @@ func (l *Auth) Authenticate(m models.AuthModel) (*models.User, error) {
 		u.UserID = int(userID)
 	}
 
+	// only the groups which have been imported into Harbor are resolved
+	u.GroupList, err = dao.GetLdapGroupIDs(ldapUsers[0].GroupDNList)
+	if err != nil {
+		return nil, err
+	}
+
 	return &u, nil
 
 }
@@ -38,14 +38,19 @@ var adminServerLdapTestConfig = map[string]interface{}{
 	common.MySQLDatabase: "registry",
 	common.SQLiteFile:    "/tmp/registry.db",
 	//config.SelfRegistration: true,
-	common.LDAPURL:       "ldap://127.0.0.1",
-	common.LDAPSearchDN:  "cn=admin,dc=example,dc=com",
-	common.LDAPSearchPwd: "admin",
-	common.LDAPBaseDN:    "dc=example,dc=com",
-	common.LDAPUID:       "uid",
-	common.LDAPFilter:    "",
-	common.LDAPScope:     3,
-	common.LDAPTimeout:   30,
+	common.LDAPURL:                 "ldap://127.0.0.1",
+	common.LDAPSearchDN:            "cn=admin,dc=example,dc=com",
+	common.LDAPSearchPwd:           "admin",
+	common.LDAPBaseDN:              "dc=example,dc=com",
+	common.LDAPUID:                 "uid",
+	common.LDAPFilter:              "",
+	common.LDAPScope:               3,
+	common.LDAPTimeout:             30,
+	common.LDAPGroupBaseDN:         "ou=groups,dc=example,dc=com",
+	common.LDAPGroupFilter:         "objectclass=groupOfUniqueNames",
+	common.LDAPGroupNameAttribute:  "cn",
+	common.LDAPGroupScope:          2,
+	common.LDAPGroupMembershipAttr: "memberof",
 	//	config.TokenServiceURL:            "",
 	//	config.RegistryURL:                "",
 	//	config.EmailHost:                  "",
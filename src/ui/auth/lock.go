@@ -15,37 +15,154 @@
 package auth
 
 import (
-	"sync"
+	"net"
+	"net/http"
+	"strings"
 	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/ui/config"
 )
 
-// UserLock maintains a lock to block user from logging in within a short period of time.
-type UserLock struct {
-	failures map[string]time.Time
-	d        time.Duration
-	rw       *sync.RWMutex
+// the duration of a lockout doesn't exceed one day however many times
+// the username or IP has been locked
+const maxLockDuration = 24 * time.Hour
+
+// the lockouts are forgotten if the username or IP has neither failed to
+// login nor been locked for this period, so that the next lockout starts
+// from the base duration again
+const lockoutQuietPeriod = 24 * time.Hour
+
+// isLocked returns whether the username or the source IP of the login
+// request is locked. The failures are persisted in DB so that all UI
+// instances share the lockouts
+func isLocked(m models.AuthModel, now time.Time) (bool, error) {
+	for _, lf := range failureKeys(m) {
+		failure, err := dao.GetLoginFailure(lf.Type, lf.Name)
+		if err != nil {
+			return false, err
+		}
+		if failure != nil && failure.IsLocked(now) {
+			log.Debugf("%s %s is locked until %s", lf.Type, lf.Name,
+				time.Unix(failure.LockedUntil, 0))
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-// NewUserLock ...
-func NewUserLock(freeze time.Duration) *UserLock {
-	return &UserLock{
-		make(map[string]time.Time),
-		freeze,
-		&sync.RWMutex{},
+// recordFailure counts the failed login per username and per source IP,
+// the username or IP is locked when the count reaches the threshold
+func recordFailure(m models.AuthModel, now time.Time) error {
+	setting, err := config.LoginLockSetting()
+	if err != nil {
+		return err
+	}
+	base := time.Duration(setting.Duration) * time.Second
+
+	for _, lf := range failureKeys(m) {
+		threshold := setting.MaxFailures
+		if lf.Type == models.LoginFailureTypeIP {
+			threshold = setting.MaxFailuresPerIP
+		}
+		if threshold == 0 {
+			continue
+		}
+
+		// the failures happened before the last lockout duration are
+		// not counted
+		failure, err := dao.IncreaseLoginFailure(lf.Type, lf.Name, now, now.Add(-base),
+			now.Add(-lockoutQuietPeriod))
+		if err != nil {
+			return err
+		}
+		if failure == nil || failure.Failures < threshold {
+			continue
+		}
+
+		until := now.Add(lockDuration(base, failure.Lockouts))
+		locked, err := dao.LockLoginFailure(failure, until)
+		if err != nil {
+			return err
+		}
+		// locked by other requests
+		if !locked {
+			continue
+		}
+		log.Warningf("%s %s is locked until %s after %d failed logins",
+			lf.Type, lf.Name, until, failure.Failures)
+		addLockLog(m, lf, now)
 	}
+	return nil
+}
+
+// clearFailures resets the failures of the username after a successful
+// login, the failures of the source IP are kept as a password spray may
+// succeed sometimes
+func clearFailures(m models.AuthModel) error {
+	return dao.DeleteLoginFailure(models.LoginFailureTypeUser, m.Principal)
 }
 
-// Lock marks a new login failure with the time it happens
-func (ul *UserLock) Lock(username string) {
-	ul.rw.Lock()
-	defer ul.rw.Unlock()
-	ul.failures[username] = time.Now()
+// ClientIP returns the IP of the client which sends the request. Harbor's
+// proxy sets X-Real-IP to the address of the connection, unlike
+// X-Forwarded-For it can't be forged by the client. The address of the
+// connection is used if the header is absent
+func ClientIP(req *http.Request) string {
+	if ip := strings.TrimSpace(req.Header.Get("X-Real-IP")); len(ip) > 0 {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
 }
 
-// IsLocked checks whether a login request is happened within a period of time or not
-// if it is, the authenticator should ignore the login request and return a failure immediately
-func (ul *UserLock) IsLocked(username string) bool {
-	ul.rw.RLock()
-	defer ul.rw.RUnlock()
-	return time.Now().Sub(ul.failures[username]) <= ul.d
+// lockDuration doubles the base duration for every previous lockout
+func lockDuration(base time.Duration, lockouts int) time.Duration {
+	d := base
+	for i := 0; i < lockouts && d < maxLockDuration; i++ {
+		d *= 2
+	}
+	if d > maxLockDuration {
+		return maxLockDuration
+	}
+	return d
+}
+
+func failureKeys(m models.AuthModel) []*models.LoginFailure {
+	keys := []*models.LoginFailure{
+		{
+			Type: models.LoginFailureTypeUser,
+			Name: m.Principal,
+		},
+	}
+	if len(m.RemoteAddr) > 0 {
+		keys = append(keys, &models.LoginFailure{
+			Type: models.LoginFailureTypeIP,
+			Name: m.RemoteAddr,
+		})
+	}
+	return keys
+}
+
+// addLockLog records the lockout in the access log, the source IP is
+// recorded as the resource for IP lockouts
+func addLockLog(m models.AuthModel, lf *models.LoginFailure, now time.Time) {
+	accessLog := models.AccessLog{
+		Username:  m.Principal,
+		RepoName:  "N/A",
+		RepoTag:   "N/A",
+		Operation: "lock",
+		OpTime:    now,
+	}
+	if lf.Type == models.LoginFailureTypeIP {
+		accessLog.RepoName = lf.Name
+		accessLog.Operation = "lock_ip"
+	}
+	if err := dao.AddAccessLog(accessLog); err != nil {
+		log.Errorf("failed to add access log: %v", err)
+	}
 }
@@ -0,0 +1,296 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/common/utils/log"
+	oidcutils "github.com/vmware/harbor/src/common/utils/oidc"
+	"github.com/vmware/harbor/src/ui/auth"
+	"github.com/vmware/harbor/src/ui/config"
+)
+
+// the same restrictions as the ones on the usernames of the users who sign
+// up, 11 characters of the column are reserved for marking deleted users
+const maxUsernameLength = 20
+
+var illegalUsernameChars = []string{",", "~", "#", "$", "%"}
+
+// InvalidUsernameError is returned by OnboardUser if the username in the
+// claims can't be used by the new user
+type InvalidUsernameError struct {
+	Username string
+	Reason   string
+}
+
+func (e *InvalidUsernameError) Error() string {
+	return fmt.Sprintf("the username %q provided by the OIDC provider %s", e.Username, e.Reason)
+}
+
+// Auth implements Authenticator interface to authenticate the users who
+// login via the OIDC provider with their CLI secrets, e.g. docker login
+type Auth struct{}
+
+// Authenticate checks the CLI secret of the user
+func (o *Auth) Authenticate(m models.AuthModel) (*models.User, error) {
+	user, err := dao.GetUser(models.User{
+		Username: m.Principal,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		log.Debugf("user %s not found", m.Principal)
+		return nil, nil
+	}
+
+	ou, err := dao.GetOIDCUserByUserID(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if ou == nil {
+		log.Debugf("user %s does not login via OIDC provider", m.Principal)
+		return nil, nil
+	}
+	secret, err := decryptSecret(ou.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 || subtle.ConstantTimeCompare([]byte(secret), []byte(m.Password)) != 1 {
+		log.Debugf("invalid CLI secret of user %s", m.Principal)
+		return nil, nil
+	}
+	// the groups in the last ID token apply as the CLI secret carries none
+	if user.GroupList, err = groupIDsOf(ou.GroupNames); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// OnboardUser returns the user linked with the subject in the claims, the
+// user is created when he logs in for the first time. The group list of the
+// user is filled with the groups in the claims which have been added to
+// Harbor. An InvalidUsernameError is returned if the username of the new
+// user is not allowed
+func OnboardUser(claims *oidcutils.Claims) (*models.User, error) {
+	groupNames, err := groupNamesOf(claims)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs, err := groupIDsOf(groupNames)
+	if err != nil {
+		return nil, err
+	}
+
+	ou, err := dao.GetOIDCUserBySubIss(claims.SubIss())
+	if err != nil {
+		return nil, err
+	}
+	if ou != nil {
+		user, err := dao.GetUser(models.User{
+			UserID: ou.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("user %d linked with %s not found", ou.UserID, claims.Subject)
+		}
+		if ou.GroupNames != groupNames {
+			ou.GroupNames = groupNames
+			if err = dao.UpdateOIDCUserGroups(ou); err != nil {
+				return nil, err
+			}
+		}
+		user.GroupList = groupIDs
+		return user, nil
+	}
+
+	username := usernameOf(claims)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	user := models.User{
+		Username: username,
+		Email:    claims.Email,
+		Realname: claims.Name,
+		// the user can not login with password
+		Password: utils.GenerateRandomString(),
+		Comment:  "from OIDC provider.",
+	}
+	if len(user.Realname) == 0 {
+		user.Realname = user.Username
+	}
+	if len(user.Email) == 0 {
+		user.Email = user.Username + "@placeholder.com"
+	}
+
+	exist, err := dao.UserExists(user, "username")
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, fmt.Errorf("the username %s of %s has been used by another user", user.Username, claims.Subject)
+	}
+	exist, err = dao.UserExists(user, "email")
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, fmt.Errorf("the email %s of %s has been used by another user", user.Email, claims.Subject)
+	}
+
+	userID, err := dao.Register(user)
+	if err != nil {
+		return nil, err
+	}
+	user.UserID = int(userID)
+
+	secret, err := encryptSecret(utils.GenerateRandomString())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dao.AddOIDCUser(&models.OIDCUser{
+		UserID:     user.UserID,
+		SubIss:     claims.SubIss(),
+		Secret:     secret,
+		GroupNames: groupNames,
+	}); err != nil {
+		return nil, err
+	}
+	log.Infof("user %s onboarded from OIDC provider, subject: %s", user.Username, claims.Subject)
+
+	user.GroupList = groupIDs
+	return &user, nil
+}
+
+// validateUsername returns an InvalidUsernameError if the username is too
+// long or contains illegal characters
+func validateUsername(username string) error {
+	if len(username) > maxUsernameLength {
+		return &InvalidUsernameError{
+			Username: username,
+			Reason:   fmt.Sprintf("is longer than %d characters", maxUsernameLength),
+		}
+	}
+	for _, c := range illegalUsernameChars {
+		if strings.Contains(username, c) {
+			return &InvalidUsernameError{
+				Username: username,
+				Reason:   fmt.Sprintf("contains illegal characters %s", strings.Join(illegalUsernameChars, "")),
+			}
+		}
+	}
+	return nil
+}
+
+// groupNamesOf returns the groups in the claims as a JSON array, an empty
+// string is returned if there is no group
+func groupNamesOf(claims *oidcutils.Claims) (string, error) {
+	if len(claims.Groups) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(claims.Groups)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// groupIDsOf returns the IDs of the groups in the JSON array which have
+// been added to Harbor
+func groupIDsOf(groupNames string) ([]int, error) {
+	if len(groupNames) == 0 {
+		return []int{}, nil
+	}
+	names := []string{}
+	if err := json.Unmarshal([]byte(groupNames), &names); err != nil {
+		return nil, err
+	}
+	return dao.GetOIDCGroupIDs(names)
+}
+
+// the username is the preferred username in the claims or the local
+// part of the email if the former is not provided
+func usernameOf(claims *oidcutils.Claims) string {
+	if len(claims.Username) > 0 {
+		return claims.Username
+	}
+	if i := strings.Index(claims.Email, "@"); i > 0 {
+		return claims.Email[:i]
+	}
+	return claims.Subject
+}
+
+// GetCLISecret returns the CLI secret of the user, an empty string is
+// returned if the user doesn't login via the OIDC provider
+func GetCLISecret(userID int) (string, error) {
+	ou, err := dao.GetOIDCUserByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if ou == nil {
+		return "", nil
+	}
+	return decryptSecret(ou.Secret)
+}
+
+// RegenerateCLISecret generates a new CLI secret for the user, the old one
+// can not be used any more
+func RegenerateCLISecret(userID int) (string, error) {
+	ou, err := dao.GetOIDCUserByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if ou == nil {
+		return "", fmt.Errorf("user %d does not login via OIDC provider", userID)
+	}
+
+	secret := utils.GenerateRandomString()
+	if ou.Secret, err = encryptSecret(secret); err != nil {
+		return "", err
+	}
+	if err = dao.UpdateOIDCUserSecret(ou); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func encryptSecret(secret string) (string, error) {
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return utils.ReversibleEncrypt(secret, key)
+}
+
+func decryptSecret(secret string) (string, error) {
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return utils.ReversibleDecrypt(secret, key)
+}
+
+func init() {
+	auth.Register("oidc_auth", &Auth{})
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	oidcutils "github.com/vmware/harbor/src/common/utils/oidc"
+)
+
+func TestUsernameOf(t *testing.T) {
+	cases := []struct {
+		claims   *oidcutils.Claims
+		expected string
+	}{
+		{&oidcutils.Claims{Subject: "sub", Username: "alice", Email: "bob@example.com"}, "alice"},
+		{&oidcutils.Claims{Subject: "sub", Email: "bob@example.com"}, "bob"},
+		{&oidcutils.Claims{Subject: "sub", Email: "@example.com"}, "sub"},
+		{&oidcutils.Claims{Subject: "sub"}, "sub"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, usernameOf(c.claims))
+	}
+}
+
+func TestGroupNamesOf(t *testing.T) {
+	names, err := groupNamesOf(&oidcutils.Claims{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", names)
+	ids, err := groupIDsOf(names)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, ids)
+
+	names, err = groupNamesOf(&oidcutils.Claims{Groups: []string{"developers", "admins"}})
+	assert.Nil(t, err)
+	assert.Equal(t, `["developers","admins"]`, names)
+}
+
+func TestValidateUsername(t *testing.T) {
+	cases := []struct {
+		username string
+		valid    bool
+	}{
+		{"alice", true},
+		{"alice.smith-01", true},
+		{"a_username_of_20_chr", true},
+		{"a_username_of_21_char", false},
+		{"alice,bob", false},
+		{"alice~", false},
+		{"#alice", false},
+		{"al$ice", false},
+		{"100%", false},
+	}
+	for _, c := range cases {
+		err := validateUsername(c.username)
+		if c.valid {
+			assert.Nil(t, err, c.username)
+			continue
+		}
+		if assert.NotNil(t, err, c.username) {
+			_, ok := err.(*InvalidUsernameError)
+			assert.True(t, ok)
+		}
+	}
+}
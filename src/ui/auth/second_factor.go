@@ -0,0 +1,106 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/subtle"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/ui/auth/totp"
+	"github.com/vmware/harbor/src/ui/config"
+)
+
+// secondFactor completes the authentication of the user after the password
+// is checked by the authenticator, the user is nil if the password is wrong
+func secondFactor(m models.AuthModel, user *models.User, cli bool) (*models.User, error) {
+	if cli {
+		if user == nil {
+			// the password may be the CLI secret of a user who enables
+			// two-factor authentication
+			return authenticateCLISecret(m)
+		}
+		enabled, err := totp.Enabled(user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			log.Debugf("user %s enables two-factor authentication, the password is not accepted", m.Principal)
+			return nil, nil
+		}
+		return checkAdminEnrollment(user)
+	}
+
+	if user == nil {
+		return nil, nil
+	}
+	enabled, err := totp.Enabled(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return checkAdminEnrollment(user)
+	}
+	if len(m.OTP) == 0 {
+		return nil, ErrOTPRequired
+	}
+	ok, err := totp.Verify(user.UserID, m.OTP)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		log.Debugf("invalid one-time password of user %s", m.Principal)
+		return nil, nil
+	}
+	return user, nil
+}
+
+func authenticateCLISecret(m models.AuthModel) (*models.User, error) {
+	user, err := dao.GetUser(models.User{
+		Username: m.Principal,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+	secret, err := totp.GetCLISecret(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 || subtle.ConstantTimeCompare([]byte(secret), []byte(m.Password)) != 1 {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// checkAdminEnrollment revokes the admin privileges of the system admin who
+// has not enrolled in two-factor authentication when it is required
+func checkAdminEnrollment(user *models.User) (*models.User, error) {
+	if user.HasAdminRole != 1 {
+		return user, nil
+	}
+	required, err := config.TOTPRequiredForAdmin()
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		log.Warningf("system admin %s has not enrolled in two-factor authentication, the admin privileges are not granted", user.Username)
+		user.HasAdminRole = 0
+	}
+	return user, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// the length of the column user_agent of table user_session
+const userAgentMaxLength = 255
+
+// activeSince returns the time since when the sessions must have been active
+// to be alive, the sessions inactive for longer than the max lifetime have
+// been removed from the session store. The last active time is only updated
+// every lastUsedInterval, so a session may be active later than recorded
+func activeSince(now time.Time) time.Time {
+	lifetime := time.Duration(beego.BConfig.WebConfig.Session.SessionGCMaxLifetime) * time.Second
+	return now.Add(-lifetime - lastUsedInterval)
+}
+
+// pruneSessions deletes the records of the expired sessions, the records
+// are not deleted when the sessions expire in the session store
+func pruneSessions(now time.Time) {
+	n, err := dao.DeleteInactiveUserSessions(activeSince(now))
+	if err != nil {
+		log.Errorf("failed to prune the expired sessions: %v", err)
+		return
+	}
+	log.Debugf("%d expired sessions pruned", n)
+}
+
+// HashSessionID returns the digest of the session ID which is stored in
+// database, so that the records can not be used to hijack the sessions
+func HashSessionID(sessionID string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(sessionID)))
+}
+
+// RegisterSession records the session created when the user logs in, the
+// session is not accepted by the security filter if it is not registered
+func RegisterSession(sessionID string, userID int, ip, userAgent string) error {
+	if len(userAgent) > userAgentMaxLength {
+		userAgent = userAgent[:userAgentMaxLength]
+	}
+	pruneSessions(time.Now())
+	hashed := HashSessionID(sessionID)
+	// the user may log in again without logging out
+	if err := dao.DeleteUserSessionBySessionID(hashed); err != nil {
+		return err
+	}
+	_, err := dao.AddUserSession(&models.UserSession{
+		SessionID: hashed,
+		UserID:    userID,
+		IP:        ip,
+		UserAgent: userAgent,
+	})
+	return err
+}
+
+// CheckSession returns the record of the session, nil is returned if the
+// session is not registered, has been revoked or has expired
+func CheckSession(sessionID string) (*models.UserSession, error) {
+	session, err := dao.GetUserSessionBySessionID(HashSessionID(sessionID))
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.LastActiveAt < activeSince(now).Unix() {
+		if err := dao.DeleteUserSession(session.ID); err != nil {
+			log.Errorf("failed to delete the expired session %d: %v", session.ID, err)
+		}
+		return nil, nil
+	}
+	if now.Sub(time.Unix(session.LastActiveAt, 0)) >= lastUsedInterval {
+		if err := dao.UpdateUserSessionLastActive(session.ID, now); err != nil {
+			log.Errorf("failed to update the last active time of session %d: %v",
+				session.ID, err)
+		} else {
+			session.LastActiveAt = now.Unix()
+		}
+	}
+	return session, nil
+}
+
+// ListSessions returns the sessions of the user which have not expired, the
+// expired sessions of all users are pruned
+func ListSessions(userID int) ([]*models.UserSession, error) {
+	now := time.Now()
+	pruneSessions(now)
+	return dao.ListUserSessions(userID, activeSince(now))
+}
+
+// UnregisterSession deletes the record of the session when the user logs out
+func UnregisterSession(sessionID string) error {
+	return dao.DeleteUserSessionBySessionID(HashSessionID(sessionID))
+}
+
+// RevokeSessions revokes all the sessions of the user except the one whose
+// ID is exceptSessionID, which can be empty
+func RevokeSessions(userID int, exceptSessionID string) error {
+	except := ""
+	if len(exceptSessionID) > 0 {
+		except = HashSessionID(exceptSessionID)
+	}
+	n, err := dao.DeleteUserSessions(userID, except)
+	if err != nil {
+		return err
+	}
+	log.Debugf("%d sessions of user %d revoked", n, userID)
+	return nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package totp manages the enrollment of users in two-factor authentication
+// based on time-based one-time passwords and verifies the second factor
+// when they login.
+package totp
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+	totputils "github.com/vmware/harbor/src/common/utils/totp"
+	"github.com/vmware/harbor/src/ui/config"
+)
+
+const (
+	issuer            = "Harbor"
+	recoveryCodeCount = 10
+	recoveryCodeLen   = 10
+)
+
+var (
+	// ErrAlreadyEnabled is returned when a user who has enabled two-factor
+	// authentication enrolls again
+	ErrAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrNotEnrolled is returned when a user who has not enrolled verifies
+	// the enrollment
+	ErrNotEnrolled = errors.New("the user has not enrolled in two-factor authentication")
+)
+
+// Enabled returns whether the user has enabled two-factor authentication
+func Enabled(userID int) (bool, error) {
+	t, err := dao.GetUserTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+	return t != nil && t.Enabled, nil
+}
+
+// Enroll generates a new secret for the user, it replaces the secret of
+// the previous enrollment which has not been verified
+func Enroll(user *models.User) (*models.TOTPEnrollment, error) {
+	t, err := dao.GetUserTOTP(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil && t.Enabled {
+		return nil, ErrAlreadyEnabled
+	}
+
+	secret, err := totputils.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		t.Secret = encrypted
+		err = dao.UpdateUserTOTP(t, "Secret")
+	} else {
+		_, err = dao.AddUserTOTP(&models.UserTOTP{
+			UserID: user.UserID,
+			Secret: encrypted,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totputils.ProvisioningURI(issuer, user.Username, secret),
+	}, nil
+}
+
+// Activate verifies the enrollment with the one-time password and enables
+// two-factor authentication for the user, nil is returned if the password
+// is invalid
+func Activate(userID int, code string) (*models.TOTPActivation, error) {
+	t, err := dao.GetUserTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrNotEnrolled
+	}
+	if t.Enabled {
+		return nil, ErrAlreadyEnabled
+	}
+
+	secret, err := decrypt(t.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, err := totputils.Validate(secret, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if step == 0 {
+		return nil, nil
+	}
+
+	activation := &models.TOTPActivation{
+		CLISecret: utils.GenerateRandomString(),
+	}
+	hashes := []string{}
+	for i := 0; i < recoveryCodeCount; i++ {
+		code := utils.GenerateRandomString()[:recoveryCodeLen]
+		activation.RecoveryCodes = append(activation.RecoveryCodes, code)
+		hashes = append(hashes, hashCode(code))
+	}
+
+	t.Enabled = true
+	t.LastStep = step
+	t.RecoveryCodes = strings.Join(hashes, ",")
+	if t.CLISecret, err = encrypt(activation.CLISecret); err != nil {
+		return nil, err
+	}
+	if err = dao.UpdateUserTOTP(t, "Enabled", "LastStep", "RecoveryCodes", "CLISecret"); err != nil {
+		return nil, err
+	}
+	return activation, nil
+}
+
+// Disable disables two-factor authentication for the user
+func Disable(userID int) error {
+	return dao.DeleteUserTOTP(userID)
+}
+
+// Verify checks the one-time password or one of the recovery codes of the
+// user, every password and recovery code can only be used once
+func Verify(userID int, code string) (bool, error) {
+	t, err := dao.GetUserTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+	if t == nil || !t.Enabled {
+		return false, nil
+	}
+
+	secret, err := decrypt(t.Secret)
+	if err != nil {
+		return false, err
+	}
+	step, err := totputils.Validate(secret, code, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if step > 0 {
+		return dao.UpdateTOTPLastStep(userID, step)
+	}
+
+	hash := hashCode(strings.ToLower(strings.TrimSpace(code)))
+	hashes := strings.Split(t.RecoveryCodes, ",")
+	for i, h := range hashes {
+		if len(h) == 0 || h != hash {
+			continue
+		}
+		rest := append(hashes[:i], hashes[i+1:]...)
+		return dao.UpdateTOTPRecoveryCodes(userID, t.RecoveryCodes, strings.Join(rest, ","))
+	}
+	return false, nil
+}
+
+// GetCLISecret returns the CLI secret of the user, an empty string is
+// returned if the user has not enabled two-factor authentication
+func GetCLISecret(userID int) (string, error) {
+	t, err := dao.GetUserTOTP(userID)
+	if err != nil {
+		return "", err
+	}
+	if t == nil || !t.Enabled {
+		return "", nil
+	}
+	return decrypt(t.CLISecret)
+}
+
+// RegenerateCLISecret generates a new CLI secret for the user, the old one
+// can not be used any more
+func RegenerateCLISecret(userID int) (string, error) {
+	t, err := dao.GetUserTOTP(userID)
+	if err != nil {
+		return "", err
+	}
+	if t == nil || !t.Enabled {
+		return "", fmt.Errorf("user %d has not enabled two-factor authentication", userID)
+	}
+
+	secret := utils.GenerateRandomString()
+	if t.CLISecret, err = encrypt(secret); err != nil {
+		return "", err
+	}
+	if err = dao.UpdateUserTOTP(t, "CLISecret"); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// the recovery codes are random enough to be stored as plain hashes
+func hashCode(code string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(code)))
+}
+
+func encrypt(str string) (string, error) {
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return utils.ReversibleEncrypt(str, key)
+}
+
+func decrypt(str string) (string, error) {
+	key, err := config.SecretKey()
+	if err != nil {
+		return "", err
+	}
+	return utils.ReversibleDecrypt(str, key)
+}
@@ -33,6 +33,11 @@ import (
 const (
 	defaultKeyPath   string = "/etc/ui/key"
 	secretCookieName string = "secret"
+
+	defaultLoginMaxFailures      = 5
+	defaultLoginMaxFailuresPerIP = 50
+	defaultLoginLockDuration     = 300
+	defaultPasswordMinLength     = 8
 )
 
 var (
@@ -148,6 +153,11 @@ func LDAP() (*models.LDAP, error) {
 	ldap.Filter = cfg[common.LDAPFilter].(string)
 	ldap.Scope = int(cfg[common.LDAPScope].(float64))
 	ldap.Timeout = int(cfg[common.LDAPTimeout].(float64))
+	ldap.GroupBaseDN = cfg[common.LDAPGroupBaseDN].(string)
+	ldap.GroupFilter = cfg[common.LDAPGroupFilter].(string)
+	ldap.GroupNameAttr = cfg[common.LDAPGroupNameAttribute].(string)
+	ldap.GroupScope = int(cfg[common.LDAPGroupScope].(float64))
+	ldap.GroupMemberAttr = cfg[common.LDAPGroupMembershipAttr].(string)
 
 	return ldap, nil
 }
@@ -183,6 +193,36 @@ func ExtURL() (string, error) {
 	return endpoint, nil
 }
 
+// OIDCSetting returns the setting of OpenID Connect provider
+func OIDCSetting() (*models.OIDCSetting, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	extEndpoint, err := ExtEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	scope := []string{}
+	for _, s := range strings.Split(cfg[common.OIDCScope].(string), ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			scope = append(scope, s)
+		}
+	}
+
+	return &models.OIDCSetting{
+		Endpoint:     strings.TrimRight(cfg[common.OIDCEndpoint].(string), "/"),
+		ClientID:     cfg[common.OIDCClientID].(string),
+		ClientSecret: cfg[common.OIDCClientSecret].(string),
+		Scope:        scope,
+		GroupsClaim:  cfg[common.OIDCGroupsClaim].(string),
+		VerifyCert:   cfg[common.OIDCVerifyCert].(bool),
+		RedirectURL:  strings.TrimRight(extEndpoint, "/") + "/c/oidc/callback",
+	}, nil
+}
+
 // SecretKey returns the secret key to encrypt the password of target
 func SecretKey() (string, error) {
 	return keyProvider.Get(nil)
@@ -323,6 +363,52 @@ func WithClair() bool {
 	return cfg[common.WithClair].(bool)
 }
 
+// ClairEndpoint returns the end point of clair instance, by default it's the one deployed within Harbor.
+func ClairEndpoint() string {
+	return "http://clair:6060"
+}
+
+// Scanner returns the kind and the endpoint of the scanner used to scan images,
+// the kind is one of clair and http. Clair deployed within Harbor is used if
+// the scanner is not configured
+func Scanner() (string, string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", "", err
+	}
+	// the configurations are missing if it is upgraded from an older version
+	kind, _ := cfg[common.Scanner].(string)
+	endpoint, _ := cfg[common.ScannerEndpoint].(string)
+	if len(kind) == 0 {
+		kind = common.ScannerClair
+	}
+	if kind == common.ScannerClair && len(endpoint) == 0 {
+		endpoint = ClairEndpoint()
+	}
+	return kind, endpoint, nil
+}
+
+// ScanEnabled returns whether images can be scanned, that is Harbor is
+// deployed with Clair or the endpoint of an external scanner is configured
+func ScanEnabled() bool {
+	cfg, err := mg.Get()
+	if err != nil {
+		log.Errorf("Failed to get configuration, will return ScanEnabled == false")
+		return false
+	}
+	return scanEnabled(cfg)
+}
+
+func scanEnabled(cfg map[string]interface{}) bool {
+	kind, _ := cfg[common.Scanner].(string)
+	endpoint, _ := cfg[common.ScannerEndpoint].(string)
+	if kind == common.ScannerHTTP {
+		return len(endpoint) > 0
+	}
+	withClair, _ := cfg[common.WithClair].(bool)
+	return withClair || len(endpoint) > 0
+}
+
 // AdmiralEndpoint returns the URL of admiral, if Harbor is not deployed with admiral it should return an empty string.
 func AdmiralEndpoint() string {
 	cfg, err := mg.Get()
@@ -341,3 +427,84 @@ func AdmiralEndpoint() string {
 func WithAdmiral() bool {
 	return len(AdmiralEndpoint()) > 0
 }
+
+// LoginLockSetting returns the settings of account lockout, the defaults are
+// used for the settings which are not configured
+func LoginLockSetting() (*models.LoginLockSetting, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	setting := &models.LoginLockSetting{
+		MaxFailures:      defaultLoginMaxFailures,
+		MaxFailuresPerIP: defaultLoginMaxFailuresPerIP,
+		Duration:         defaultLoginLockDuration,
+	}
+	if v, ok := cfg[common.LoginMaxFailures].(float64); ok && v >= 0 {
+		setting.MaxFailures = int(v)
+	}
+	if v, ok := cfg[common.LoginMaxFailuresPerIP].(float64); ok && v >= 0 {
+		setting.MaxFailuresPerIP = int(v)
+	}
+	if v, ok := cfg[common.LoginLockDuration].(float64); ok && v > 0 {
+		setting.Duration = int(v)
+	}
+	return setting, nil
+}
+
+// PasswordPolicy returns the password policy, the defaults are used for the
+// settings which are not configured
+func PasswordPolicy() (*models.PasswordPolicy, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.PasswordPolicy{
+		MinLength:        defaultPasswordMinLength,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireNumber:    true,
+	}
+	if v, ok := cfg[common.PasswordMinLength].(float64); ok && v > 0 {
+		policy.MinLength = int(v)
+	}
+	if v, ok := cfg[common.PasswordRequireUppercase].(bool); ok {
+		policy.RequireUppercase = v
+	}
+	if v, ok := cfg[common.PasswordRequireLowercase].(bool); ok {
+		policy.RequireLowercase = v
+	}
+	if v, ok := cfg[common.PasswordRequireNumber].(bool); ok {
+		policy.RequireNumber = v
+	}
+	if v, ok := cfg[common.PasswordRequireSpecial].(bool); ok {
+		policy.RequireSpecial = v
+	}
+	return policy, nil
+}
+
+// TOTPRequiredForAdmin returns whether the system admins must enroll in
+// two-factor authentication
+func TOTPRequiredForAdmin() (bool, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return false, err
+	}
+	required, _ := cfg[common.TOTPRequiredForAdmin].(bool)
+	return required, nil
+}
+
+// ScanAllPolicy returns the policy of scanning all images periodically
+func ScanAllPolicy() (string, error) {
+	cfg, err := mg.Get()
+	if err != nil {
+		return "", err
+	}
+	policy, _ := cfg[common.ScanAllPolicy].(string)
+	if len(policy) == 0 {
+		policy = common.ScanAllPolicyNone
+	}
+	return policy, nil
+}
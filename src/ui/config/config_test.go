@@ -17,6 +17,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/utils/test"
 )
 
@@ -143,6 +144,26 @@ func TestConfig(t *testing.T) {
 		t.Errorf(`extURL should be "host01.com".`)
 	}
 
+	policy, err := PasswordPolicy()
+	if err != nil {
+		t.Fatalf("failed to get password policy: %v", err)
+	}
+	if policy.MinLength != 8 || !policy.RequireUppercase || policy.RequireSpecial {
+		t.Errorf("unexpected password policy: %+v", policy)
+	}
+
+	kind, endpoint, err := Scanner()
+	if err != nil {
+		t.Fatalf("failed to get scanner: %v", err)
+	}
+	if kind != "clair" || endpoint != ClairEndpoint() {
+		t.Errorf("unexpected scanner: %s, %s", kind, endpoint)
+	}
+
+	if ScanEnabled() {
+		t.Errorf("scan should be disabled when neither Clair nor external scanner is configured")
+	}
+
 	// reset configurations
 	if err = Reset(); err != nil {
 		t.Errorf("failed to reset configurations: %v", err)
@@ -156,3 +177,23 @@ func TestConfig(t *testing.T) {
 		t.Errorf("unexpected mode: %s != %s", mode, "db_auth")
 	}
 }
+
+func TestScanEnabled(t *testing.T) {
+	cases := []struct {
+		cfg     map[string]interface{}
+		enabled bool
+	}{
+		{map[string]interface{}{}, false},
+		{map[string]interface{}{common.WithClair: true}, true},
+		{map[string]interface{}{common.Scanner: common.ScannerClair,
+			common.ScannerEndpoint: "http://clair.example.com:6060"}, true},
+		{map[string]interface{}{common.WithClair: true, common.Scanner: common.ScannerHTTP}, false},
+		{map[string]interface{}{common.Scanner: common.ScannerHTTP,
+			common.ScannerEndpoint: "http://scanner.example.com"}, true},
+	}
+	for _, c := range cases {
+		if enabled := scanEnabled(c.cfg); enabled != c.enabled {
+			t.Errorf("unexpected result of %v: %t != %t", c.cfg, enabled, c.enabled)
+		}
+	}
+}
@@ -55,11 +55,17 @@ type messageDetail struct {
 func (cc *CommonController) Login() {
 	principal := cc.GetString("principal")
 	password := cc.GetString("password")
+	otp := cc.GetString("otp")
 
 	user, err := auth.Login(models.AuthModel{
-		Principal: principal,
-		Password:  password,
+		Principal:  principal,
+		Password:   password,
+		RemoteAddr: auth.ClientIP(cc.Ctx.Request),
+		OTP:        otp,
 	})
+	if err == auth.ErrOTPRequired {
+		cc.CustomAbort(http.StatusUnauthorized, "otp_required")
+	}
 	if err != nil {
 		log.Errorf("Error occurred in UserLogin: %v", err)
 		cc.CustomAbort(http.StatusUnauthorized, "")
@@ -69,13 +75,28 @@ func (cc *CommonController) Login() {
 		cc.CustomAbort(http.StatusUnauthorized, "")
 	}
 
+	cc.registerSession(user.UserID)
 	cc.SetSession("userId", user.UserID)
 	cc.SetSession("username", user.Username)
 	cc.SetSession("isSysAdmin", user.HasAdminRole == 1)
+	cc.SetSession("groupIds", user.GroupList)
+}
+
+// registerSession records the session of the user who has just logged in,
+// the request is aborted if it fails
+func (cc *CommonController) registerSession(userID int) {
+	if err := auth.RegisterSession(cc.StartSession().SessionID(), userID,
+		auth.ClientIP(cc.Ctx.Request), cc.Ctx.Request.UserAgent()); err != nil {
+		log.Errorf("failed to register the session of user %d: %v", userID, err)
+		cc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
 }
 
 // LogOut Habor UI
 func (cc *CommonController) LogOut() {
+	if err := auth.UnregisterSession(cc.StartSession().SessionID()); err != nil {
+		log.Errorf("failed to unregister the session: %v", err)
+	}
 	cc.DestroySession()
 }
 
@@ -203,12 +224,23 @@ func (cc *CommonController) ResetPassword() {
 	password := cc.GetString("password")
 
 	if password != "" {
+		policy, err := config.PasswordPolicy()
+		if err != nil {
+			log.Errorf("Error occurred in getting password policy: %v", err)
+			cc.CustomAbort(http.StatusInternalServerError, "Internal error.")
+		}
+		if err = policy.Validate(password); err != nil {
+			cc.CustomAbort(http.StatusBadRequest, err.Error())
+		}
 		user.Password = password
 		err = dao.ResetUserPassword(*user)
 		if err != nil {
 			log.Errorf("Error occurred in ResetUserPassword: %v", err)
 			cc.CustomAbort(http.StatusInternalServerError, "Internal error.")
 		}
+		if err = auth.RevokeSessions(user.UserID, ""); err != nil {
+			log.Errorf("failed to revoke the sessions of user %d: %v", user.UserID, err)
+		}
 	} else {
 		cc.CustomAbort(http.StatusBadRequest, "password_is_required")
 	}
@@ -0,0 +1,125 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/common/utils/oidc"
+	oidcauth "github.com/vmware/harbor/src/ui/auth/oidc"
+	"github.com/vmware/harbor/src/ui/config"
+)
+
+const (
+	oidcStateKey = "oidc_state"
+	oidcNonceKey = "oidc_nonce"
+	// the page users are redirected to after they login via OIDC provider
+	oidcLandingPage = "/harbor/projects"
+)
+
+// OIDCLogin redirects the user to the OIDC provider to login
+func (cc *CommonController) OIDCLogin() {
+	setting := cc.oidcSetting()
+
+	state := utils.GenerateRandomString()
+	nonce := utils.GenerateRandomString()
+	u, err := oidc.AuthCodeURL(setting, state, nonce)
+	if err != nil {
+		log.Errorf("failed to get the auth code URL: %v", err)
+		cc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	cc.SetSession(oidcStateKey, state)
+	cc.SetSession(oidcNonceKey, nonce)
+	cc.Redirect(u, http.StatusFound)
+}
+
+// OIDCCallback handles the redirection from the OIDC provider, it exchanges
+// the code for ID token, onboards the user if they login for the first time
+// and creates the session
+func (cc *CommonController) OIDCCallback() {
+	setting := cc.oidcSetting()
+
+	state, ok := cc.GetSession(oidcStateKey).(string)
+	if !ok || len(state) == 0 || state != cc.GetString("state") {
+		log.Errorf("the state %s in the callback does not match the one in session", cc.GetString("state"))
+		cc.CustomAbort(http.StatusBadRequest, "invalid state")
+	}
+	nonce, _ := cc.GetSession(oidcNonceKey).(string)
+	cc.DelSession(oidcStateKey)
+	cc.DelSession(oidcNonceKey)
+
+	if e := cc.GetString("error"); len(e) > 0 {
+		log.Errorf("the OIDC provider returned error: %s, %s", e, cc.GetString("error_description"))
+		cc.CustomAbort(http.StatusUnauthorized, e)
+	}
+
+	token, err := oidc.ExchangeToken(setting, cc.GetString("code"))
+	if err != nil {
+		log.Errorf("failed to exchange token: %v", err)
+		cc.CustomAbort(http.StatusUnauthorized, "")
+	}
+
+	claims, err := oidc.VerifyIDToken(setting, token.IDToken)
+	if err != nil {
+		log.Errorf("failed to verify ID token: %v", err)
+		cc.CustomAbort(http.StatusUnauthorized, "")
+	}
+	if claims.Nonce != nonce {
+		log.Errorf("the nonce in ID token does not match the one in session")
+		cc.CustomAbort(http.StatusUnauthorized, "")
+	}
+
+	user, err := oidcauth.OnboardUser(claims)
+	if err != nil {
+		log.Errorf("failed to onboard user %s: %v", claims.Subject, err)
+		if e, ok := err.(*oidcauth.InvalidUsernameError); ok {
+			cc.CustomAbort(http.StatusBadRequest, e.Error())
+		}
+		cc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	log.Debugf("user %s logged in via OIDC provider, groups: %v", user.Username, claims.Groups)
+
+	cc.registerSession(user.UserID)
+	cc.SetSession("userId", user.UserID)
+	cc.SetSession("username", user.Username)
+	cc.SetSession("isSysAdmin", user.HasAdminRole == 1)
+	cc.SetSession("groupIds", user.GroupList)
+	cc.Redirect(oidcLandingPage, http.StatusFound)
+}
+
+// oidcSetting returns the setting of OIDC provider, the request is aborted
+// if the auth mode is not OIDC
+func (cc *CommonController) oidcSetting() *models.OIDCSetting {
+	mode, err := config.AuthMode()
+	if err != nil {
+		log.Errorf("failed to get auth mode: %v", err)
+		cc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	if mode != common.OIDCAuth {
+		cc.CustomAbort(http.StatusPreconditionFailed, "the auth mode is not "+common.OIDCAuth)
+	}
+
+	setting, err := config.OIDCSetting()
+	if err != nil {
+		log.Errorf("failed to get OIDC setting: %v", err)
+		cc.CustomAbort(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	return setting
+}
@@ -16,15 +16,20 @@ package filter
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strings"
 
 	beegoctx "github.com/astaxie/beego/context"
+	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/security"
+	"github.com/vmware/harbor/src/common/security/accesstoken"
 	"github.com/vmware/harbor/src/common/security/rbac"
+	robotctx "github.com/vmware/harbor/src/common/security/robot"
 	"github.com/vmware/harbor/src/common/security/secret"
+	"github.com/vmware/harbor/src/common/utils"
 	"github.com/vmware/harbor/src/common/utils/log"
 	"github.com/vmware/harbor/src/ui/auth"
 	"github.com/vmware/harbor/src/ui/config"
@@ -82,14 +87,46 @@ func fillContext(ctx *beegoctx.Context) {
 	var user *models.User
 	var err error
 
+	// personal access token
+	if token := bearerToken(ctx.Request); len(token) != 0 {
+		ct, err := accessTokenContext(ctx, token)
+		if err != nil {
+			log.Errorf("failed to authenticate access token: %v", err)
+		}
+		if ct != nil {
+			ctx.Request = ctx.Request.WithContext(ct)
+			return
+		}
+	}
+
 	// basic auth
 	username, password, ok := ctx.Request.BasicAuth()
+	if ok && strings.HasPrefix(username, models.RobotNamePrefix) {
+		pm := getProjectManager(ctx)
+		robot, err := authenticateRobot(pm, username, password)
+		if err != nil {
+			log.Errorf("failed to authenticate robot %s: %v", username, err)
+		}
+		if robot != nil {
+			ct := context.WithValue(ctx.Request.Context(), HarborProjectManager, pm)
+
+			log.Info("creating a robot security context...")
+			ct = context.WithValue(ct, HarborSecurityContext,
+				robotctx.NewSecurityContext(robot, pm))
+			ctx.Request = ctx.Request.WithContext(ct)
+
+			return
+		}
+		// the credential of robots can not be used to login
+		ok = false
+	}
 	if ok {
 		// TODO the return data contains other params when integrated
 		// with vic
-		user, err = auth.Login(models.AuthModel{
-			Principal: username,
-			Password:  password,
+		user, err = auth.LoginCLI(models.AuthModel{
+			Principal:  username,
+			Password:   password,
+			RemoteAddr: auth.ClientIP(ctx.Request),
 		})
 		if err != nil {
 			log.Errorf("failed to authenticate %s: %v", username, err)
@@ -104,13 +141,18 @@ func fillContext(ctx *beegoctx.Context) {
 		username := ctx.Input.Session("username")
 		isSysAdmin := ctx.Input.Session("isSysAdmin")
 		if username != nil {
-			user = &models.User{
-				Username: username.(string),
+			user, err = sessionUser(ctx, username.(string))
+			if err != nil {
+				log.Errorf("failed to check the session of %s: %v", username, err)
 			}
-
+		}
+		if user != nil {
 			if isSysAdmin != nil && isSysAdmin.(bool) {
 				user.HasAdminRole = 1
 			}
+			if groupIDs, ok := ctx.Input.Session("groupIds").([]int); ok {
+				user.GroupList = groupIDs
+			}
 			log.Info("got user information from session")
 		}
 
@@ -132,6 +174,88 @@ func fillContext(ctx *beegoctx.Context) {
 	return
 }
 
+// sessionUser returns the user the session belongs to, nil is returned if
+// the session has been revoked, the data of the revoked session is cleared
+func sessionUser(ctx *beegoctx.Context, username string) (*models.User, error) {
+	session, err := auth.CheckSession(ctx.Input.CruSession.SessionID())
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		log.Infof("the session of %s has been revoked", username)
+		if err := ctx.Input.CruSession.Flush(); err != nil {
+			log.Errorf("failed to clear the revoked session: %v", err)
+		}
+		return nil, nil
+	}
+	return &models.User{
+		UserID:   session.UserID,
+		Username: username,
+	}, nil
+}
+
+// bearerToken returns the token in the "Authorization: Bearer" header
+func bearerToken(req *http.Request) string {
+	parts := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
+// accessTokenContext returns the context with a security context of the
+// owner of the personal access token, nil is returned if the token is
+// invalid or the token is read-only but the request is not
+func accessTokenContext(ctx *beegoctx.Context, token string) (context.Context, error) {
+	user, accessToken, err := auth.LoginByAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("invalid credential")
+	}
+	method := ctx.Request.Method
+	if accessToken.ReadOnly && method != http.MethodGet && method != http.MethodHead {
+		return nil, fmt.Errorf("access token %d is read-only, %s is not allowed",
+			accessToken.ID, method)
+	}
+
+	pm := getProjectManager(ctx)
+	ct := context.WithValue(ctx.Request.Context(), HarborProjectManager, pm)
+
+	log.Info("creating an access token security context...")
+	return context.WithValue(ct, HarborSecurityContext,
+		accesstoken.NewSecurityContext(rbac.NewSecurityContext(user, pm), accessToken)), nil
+}
+
+// authenticateRobot returns the robot if the secret matches and the robot is
+// enabled and not expired, otherwise returns nil
+func authenticateRobot(pm projectmanager.ProjectManager, username, secret string) (*models.Robot, error) {
+	projectName, name, ok := models.ParseRobotLoginName(username)
+	if !ok {
+		return nil, fmt.Errorf("invalid robot name")
+	}
+	project, err := pm.Get(projectName)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("invalid credential")
+	}
+	robot, err := dao.GetRobotByName(project.ProjectID, name)
+	if err != nil {
+		return nil, err
+	}
+	if robot == nil || subtle.ConstantTimeCompare([]byte(robot.Secret),
+		[]byte(utils.Encrypt(secret, robot.Salt))) != 1 {
+		return nil, fmt.Errorf("invalid credential")
+	}
+	if robot.Disabled || robot.IsExpired() {
+		return nil, fmt.Errorf("robot is disabled or expired")
+	}
+	return robot, nil
+}
+
 func getProjectManager(ctx *beegoctx.Context) projectmanager.ProjectManager {
 	if !config.WithAdmiral() {
 		log.Info("filling a project manager based on database...")
@@ -31,9 +31,13 @@ import (
 	"github.com/astaxie/beego/session"
 	"github.com/stretchr/testify/assert"
 	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/security"
+	"github.com/vmware/harbor/src/common/security/accesstoken"
 	"github.com/vmware/harbor/src/common/security/rbac"
 	"github.com/vmware/harbor/src/common/security/secret"
+	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/ui/auth"
 	_ "github.com/vmware/harbor/src/ui/auth/db"
 	_ "github.com/vmware/harbor/src/ui/auth/ldap"
 	"github.com/vmware/harbor/src/ui/config"
@@ -154,6 +158,9 @@ func TestFillContext(t *testing.T) {
 	if err = store.Set("isSysAdmin", true); err != nil {
 		t.Fatalf("failed to set session: %v", err)
 	}
+	if err = auth.RegisterSession(store.SessionID(), 1, "127.0.0.1", ""); err != nil {
+		t.Fatalf("failed to register session: %v", err)
+	}
 
 	req, err = http.NewRequest(http.MethodGet,
 		"http://127.0.0.1/api/projects/", nil)
@@ -174,6 +181,21 @@ func TestFillContext(t *testing.T) {
 	assert.True(t, s.IsSysAdmin())
 	assert.NotNil(t, projectManager(ctx))
 
+	// revoked session
+	if err = auth.UnregisterSession(store.SessionID()); err != nil {
+		t.Fatalf("failed to unregister session: %v", err)
+	}
+	ctx, err = newContext(req)
+	if err != nil {
+		t.Fatalf("failed to crate context: %v", err)
+	}
+	fillContext(ctx)
+	sc = securityContext(ctx)
+	assert.IsType(t, &rbac.SecurityContext{}, sc)
+	s = sc.(security.Context)
+	assert.False(t, s.IsAuthenticated())
+	assert.Nil(t, store.Get("username"))
+
 	// basic auth
 	req, err = http.NewRequest(http.MethodGet,
 		"http://127.0.0.1/api/projects/", nil)
@@ -193,6 +215,56 @@ func TestFillContext(t *testing.T) {
 	assert.Equal(t, "admin", s.GetUsername())
 	assert.NotNil(t, projectManager(ctx))
 
+	// personal access token
+	salt := utils.GenerateRandomString()
+	id, err := dao.AddAccessToken(&models.AccessToken{
+		UserID:   1,
+		Name:     "token_for_test_filter",
+		Secret:   utils.Encrypt("secret", salt),
+		Salt:     salt,
+		ReadOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to add access token: %v", err)
+	}
+	defer dao.DeleteAccessToken(id)
+
+	req, err = http.NewRequest(http.MethodGet,
+		"http://127.0.0.1/api/projects/", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", req)
+	}
+	req.Header.Set("Authorization", "Bearer "+models.FormatAccessToken(id, "secret"))
+
+	ctx, err = newContext(req)
+	if err != nil {
+		t.Fatalf("failed to crate context: %v", err)
+	}
+	fillContext(ctx)
+	sc = securityContext(ctx)
+	assert.IsType(t, &accesstoken.SecurityContext{}, sc)
+	s = sc.(security.Context)
+	assert.Equal(t, "admin", s.GetUsername())
+	assert.NotNil(t, projectManager(ctx))
+
+	// read-only personal access token can not be used to write
+	req, err = http.NewRequest(http.MethodPost,
+		"http://127.0.0.1/api/projects/", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", req)
+	}
+	req.Header.Set("Authorization", "Bearer "+models.FormatAccessToken(id, "secret"))
+
+	ctx, err = newContext(req)
+	if err != nil {
+		t.Fatalf("failed to crate context: %v", err)
+	}
+	fillContext(ctx)
+	sc = securityContext(ctx)
+	assert.IsType(t, &rbac.SecurityContext{}, sc)
+	s = sc.(security.Context)
+	assert.False(t, s.IsAuthenticated())
+
 	// no credential
 	req, err = http.NewRequest(http.MethodGet,
 		"http://127.0.0.1/api/projects/", nil)
@@ -312,3 +384,18 @@ func TestGetProjectManager(t *testing.T) {
 	_, ok := pm.(projectmanager.ProjectManager)
 	assert.True(t, ok)
 }
+
+func TestBearerToken(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet,
+		"http://127.0.0.1/api/projects/", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", req)
+	}
+	assert.Equal(t, "", bearerToken(req))
+
+	req.SetBasicAuth("admin", "Harbor12345")
+	assert.Equal(t, "", bearerToken(req))
+
+	req.Header.Set("Authorization", "Bearer 1.token")
+	assert.Equal(t, "1.token", bearerToken(req))
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/vmware/harbor/src/ui/api"
 	_ "github.com/vmware/harbor/src/ui/auth/db"
 	_ "github.com/vmware/harbor/src/ui/auth/ldap"
+	_ "github.com/vmware/harbor/src/ui/auth/oidc"
 	"github.com/vmware/harbor/src/ui/config"
 	"github.com/vmware/harbor/src/ui/filter"
 	"github.com/vmware/harbor/src/ui/proxy"
@@ -67,7 +68,8 @@ func updateInitPassword(userID int, password string) error {
 
 func main() {
 	beego.BConfig.WebConfig.Session.SessionOn = true
-	//TODO
+	// the sessions are stored in Redis if it is configured, so that they
+	// can be shared by all the UI instances
 	redisURL := os.Getenv("_REDIS_URL")
 	if len(redisURL) > 0 {
 		beego.BConfig.WebConfig.Session.SessionProvider = "redis"
@@ -16,9 +16,9 @@ package db
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
-	"github.com/vmware/harbor/src/common"
 	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 )
@@ -29,14 +29,94 @@ type ProjectManager struct{}
 // Get ...
 func (p *ProjectManager) Get(projectIDOrName interface{}) (
 	*models.Project, error) {
+	var project *models.Project
+	var err error
 	switch projectIDOrName.(type) {
 	case string:
-		return dao.GetProjectByName(projectIDOrName.(string))
+		project, err = dao.GetProjectByName(projectIDOrName.(string))
 	case int64:
-		return dao.GetProjectByID(projectIDOrName.(int64))
+		project, err = dao.GetProjectByID(projectIDOrName.(int64))
 	default:
 		return nil, fmt.Errorf("unsupported type of %v, must be string or int64", projectIDOrName)
 	}
+	if err != nil || project == nil {
+		return project, err
+	}
+
+	metas, err := dao.GetProjectMetadata(project.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata of project %d: %v", project.ProjectID, err)
+	}
+	if err = populateMetadata(project, metas); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// populate the fields of project according to its metadata
+func populateMetadata(project *models.Project, metas []*models.ProjectMetadata) error {
+	for _, meta := range metas {
+		switch meta.Name {
+		case models.ProMetaEnableContentTrust:
+			enable, err := strconv.ParseBool(meta.Value)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s %s to bool: %v", meta.Name, meta.Value, err)
+			}
+			project.EnableContentTrust = enable
+		case models.ProMetaPreventVulnerable:
+			prevent, err := strconv.ParseBool(meta.Value)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s %s to bool: %v", meta.Name, meta.Value, err)
+			}
+			project.PreventVulnerableImagesFromRunning = prevent
+		case models.ProMetaSeverity:
+			project.PreventVulnerableImagesFromRunningSeverity = meta.Value
+		case models.ProMetaAutoScan:
+			scan, err := strconv.ParseBool(meta.Value)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s %s to bool: %v", meta.Name, meta.Value, err)
+			}
+			project.AutomaticallyScanImagesOnPush = scan
+		}
+	}
+	return nil
+}
+
+// save the metadata of project into database, the existing ones
+// will be updated and the others will be added
+func saveMetadata(projectID int64, project *models.Project) error {
+	metas, err := dao.GetProjectMetadata(projectID)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, meta := range metas {
+		existing[meta.Name] = true
+	}
+
+	values := map[string]string{
+		models.ProMetaEnableContentTrust: strconv.FormatBool(project.EnableContentTrust),
+		models.ProMetaPreventVulnerable:  strconv.FormatBool(project.PreventVulnerableImagesFromRunning),
+		models.ProMetaSeverity:           project.PreventVulnerableImagesFromRunningSeverity,
+		models.ProMetaAutoScan:           strconv.FormatBool(project.AutomaticallyScanImagesOnPush),
+	}
+	for name, value := range values {
+		meta := &models.ProjectMetadata{
+			ProjectID: projectID,
+			Name:      name,
+			Value:     value,
+		}
+		if existing[name] {
+			err = dao.UpdateProjectMetadata(meta)
+		} else {
+			err = dao.AddProjectMetadata(meta)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save metadata %s of project %d: %v", name, projectID, err)
+		}
+	}
+	return nil
 }
 
 // Exist ...
@@ -90,18 +170,41 @@ func (p *ProjectManager) GetRoles(username string, projectIDOrName interface{})
 		return nil, err
 	}
 
-	for _, role := range roleList {
-		switch role.RoleCode {
-		case "MDRWS":
-			roles = append(roles, common.RoleProjectAdmin)
-		case "RWS":
-			roles = append(roles, common.RoleDeveloper)
-		case "RS":
-			roles = append(roles, common.RoleGuest)
-		}
+	return convertRoles(roleList), nil
+}
+
+// GetGroupRoles return a role list which contains the roles that the groups
+// have to the project
+func (p *ProjectManager) GetGroupRoles(groupIDs []int, projectIDOrName interface{}) ([]int, error) {
+	if len(groupIDs) == 0 {
+		return []int{}, nil
 	}
 
-	return roles, nil
+	project, err := p.Get(projectIDOrName)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return []int{}, nil
+	}
+
+	roleList, err := dao.GetGroupProjectRoles(groupIDs, project.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertRoles(roleList), nil
+}
+
+// convertRoles returns the IDs of the roles, the IDs of the builtin roles
+// are the same as common.RoleProjectAdmin, common.RoleDeveloper and
+// common.RoleGuest
+func convertRoles(roleList []models.Role) []int {
+	roles := []int{}
+	for _, role := range roleList {
+		roles = append(roles, role.RoleID)
+	}
+	return roles
 }
 
 // GetPublic returns all public projects
@@ -158,7 +261,12 @@ func (p *ProjectManager) Create(project *models.Project) (int64, error) {
 		UpdateTime:   t,
 	}
 
-	return dao.AddProject(*pro)
+	id, err := dao.AddProject(*pro)
+	if err != nil {
+		return id, err
+	}
+
+	return id, saveMetadata(id, project)
 }
 
 // Delete ...
@@ -172,6 +280,18 @@ func (p *ProjectManager) Delete(projectIDOrName interface{}) error {
 		id = project.ProjectID
 	}
 
+	if err := dao.DeleteProjectMetadata(id); err != nil {
+		return err
+	}
+
+	if err := dao.DeleteRobots(id); err != nil {
+		return err
+	}
+
+	if err := dao.DeleteProjectGroupMembers(id); err != nil {
+		return err
+	}
+
 	return dao.DeleteProject(id)
 }
 
@@ -186,7 +306,10 @@ func (p *ProjectManager) Update(projectIDOrName interface{},
 		}
 		id = pro.ProjectID
 	}
-	return dao.ToggleProjectPublicity(id, project.Public)
+	if err := dao.ToggleProjectPublicity(id, project.Public); err != nil {
+		return err
+	}
+	return saveMetadata(id, project)
 }
 
 // GetAll returns a project list according to the query parameters
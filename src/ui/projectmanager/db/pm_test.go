@@ -193,6 +193,21 @@ func TestCreateAndDelete(t *testing.T) {
 	})
 	assert.Nil(t, err)
 	assert.Nil(t, pm.Delete(id))
+
+	// valid project with policies
+	id, err = pm.Create(&models.Project{
+		Name:                          "test",
+		OwnerID:                       1,
+		AutomaticallyScanImagesOnPush: true,
+	})
+	assert.Nil(t, err)
+	project, err := pm.Get(id)
+	assert.Nil(t, err)
+	assert.True(t, project.AutomaticallyScanImagesOnPush)
+	assert.Nil(t, pm.Delete(id))
+	metas, err := dao.GetProjectMetadata(id)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(metas))
 }
 
 func TestUpdate(t *testing.T) {
@@ -209,12 +224,21 @@ func TestUpdate(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0, project.Public)
 
+	assert.False(t, project.EnableContentTrust)
+
 	project.Public = 1
+	project.EnableContentTrust = true
+	project.PreventVulnerableImagesFromRunning = true
+	project.PreventVulnerableImagesFromRunningSeverity = "medium"
 	assert.Nil(t, pm.Update(id, project))
 
 	project, err = pm.Get(id)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, project.Public)
+	assert.True(t, project.EnableContentTrust)
+	assert.True(t, project.PreventVulnerableImagesFromRunning)
+	assert.Equal(t, "medium", project.PreventVulnerableImagesFromRunningSeverity)
+	assert.False(t, project.AutomaticallyScanImagesOnPush)
 }
 
 func TestGetTotal(t *testing.T) {
@@ -25,6 +25,8 @@ type ProjectManager interface {
 	IsPublic(projectIDOrName interface{}) (bool, error)
 	Exist(projectIDOrName interface{}) (bool, error)
 	GetRoles(username string, projectIDOrName interface{}) ([]int, error)
+	// GetGroupRoles returns the roles which the user groups have to the project
+	GetGroupRoles(groupIDs []int, projectIDOrName interface{}) ([]int, error)
 	// get all public project
 	GetPublic() ([]*models.Project, error)
 	// get projects which the user is a member of
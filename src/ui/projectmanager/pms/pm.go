@@ -290,6 +290,12 @@ func (p *ProjectManager) getIDbyHarborIDOrName(projectIDOrName interface{}) (str
 	return pro.ID, nil
 }
 
+// GetGroupRoles returns an empty list as user groups are not supported
+// by Admiral
+func (p *ProjectManager) GetGroupRoles(groupIDs []int, projectIDOrName interface{}) ([]int, error) {
+	return []int{}, nil
+}
+
 // GetPublic ...
 func (p *ProjectManager) GetPublic() ([]*models.Project, error) {
 	m := map[string]string{
@@ -1,18 +1,25 @@
 package proxy
 
 import (
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vmware/harbor/src/common"
+	"github.com/vmware/harbor/src/common/dao"
 	"github.com/vmware/harbor/src/common/models"
 	notarytest "github.com/vmware/harbor/src/common/utils/notary/test"
+	"github.com/vmware/harbor/src/common/utils/registry"
 	utilstest "github.com/vmware/harbor/src/common/utils/test"
 	"github.com/vmware/harbor/src/ui/config"
 	"github.com/vmware/harbor/src/ui/projectmanager/pms"
 
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -27,10 +34,27 @@ func TestMain(m *testing.M) {
 	notaryServer = notarytest.NewNotaryServer(endpoint)
 	defer notaryServer.Close()
 	NotaryEndpoint = notaryServer.URL
-	var defaultConfig = map[string]interface{}{
-		common.ExtEndpoint:   "https://" + endpoint,
-		common.WithNotary:    true,
-		common.CfgExpiration: 5,
+	defaultConfig := utilstest.GetDefaultConfigMap()
+	defaultConfig[common.ExtEndpoint] = "https://" + endpoint
+	defaultConfig[common.WithNotary] = true
+	defaultConfig[common.WithClair] = true
+	defaultConfig[common.CfgExpiration] = 5
+	defaultConfig[common.AdmiralEndpoint] = ""
+	if len(os.Getenv("MYSQL_HOST")) > 0 {
+		defaultConfig[common.MySQLHost] = os.Getenv("MYSQL_HOST")
+	}
+	if len(os.Getenv("MYSQL_PORT")) > 0 {
+		p, err := strconv.Atoi(os.Getenv("MYSQL_PORT"))
+		if err != nil {
+			panic(err)
+		}
+		defaultConfig[common.MySQLPort] = p
+	}
+	if len(os.Getenv("MYSQL_USR")) > 0 {
+		defaultConfig[common.MySQLUsername] = os.Getenv("MYSQL_USR")
+	}
+	if len(os.Getenv("MYSQL_PWD")) > 0 {
+		defaultConfig[common.MySQLPassword] = os.Getenv("MYSQL_PWD")
 	}
 	adminServer, err := utilstest.NewAdminserver(defaultConfig)
 	if err != nil {
@@ -43,6 +67,13 @@ func TestMain(m *testing.M) {
 	if err := config.Init(); err != nil {
 		panic(err)
 	}
+	database, err := config.Database()
+	if err != nil {
+		panic(err)
+	}
+	if err := dao.InitDatabase(database); err != nil {
+		panic(err)
+	}
 	result := m.Run()
 	if result != 0 {
 		os.Exit(result)
@@ -95,10 +126,33 @@ func TestEnvPolicyChecker(t *testing.T) {
 	if err := os.Setenv("PROJECT_CONTENT_TRUST", "1"); err != nil {
 		t.Fatalf("Failed to set env variable: %v", err)
 	}
-	contentTrustFlag := getPolicyChecker().contentTrustEnabled("whatever")
-	vulFlag := getPolicyChecker().vulnerableEnabled("whatever")
+	contentTrustFlag := EnvChecker.contentTrustEnabled("whatever")
+	vulFlag, _ := EnvChecker.vulnerablePolicy("whatever")
 	assert.True(contentTrustFlag)
 	assert.False(vulFlag)
+
+	if err := os.Setenv("PROJECT_VULNERABBLE", "1"); err != nil {
+		t.Fatalf("Failed to set env variable: %v", err)
+	}
+	if err := os.Setenv("PROJECT_SEVERITY", "medium"); err != nil {
+		t.Fatalf("Failed to set env variable: %v", err)
+	}
+	defer os.Unsetenv("PROJECT_VULNERABBLE")
+	defer os.Unsetenv("PROJECT_SEVERITY")
+	vulFlag, sev := EnvChecker.vulnerablePolicy("whatever")
+	assert.True(vulFlag)
+	assert.Equal(models.SevMedium, sev)
+
+	if err := os.Setenv("PROJECT_CVE_ALLOWLIST", "CVE-2016-2177,CVE-2016-2178"); err != nil {
+		t.Fatalf("Failed to set env variable: %v", err)
+	}
+	defer os.Unsetenv("PROJECT_CVE_ALLOWLIST")
+	cves, err := EnvChecker.allowlistedCVEs("whatever")
+	assert.Nil(err)
+	assert.Equal(map[string]bool{
+		"CVE-2016-2177": true,
+		"CVE-2016-2178": true,
+	}, cves)
 }
 
 func TestPMSPolicyChecker(t *testing.T) {
@@ -126,6 +180,163 @@ func TestPMSPolicyChecker(t *testing.T) {
 	assert.True(t, contentTrustFlag)
 }
 
+// addScanOverview records a finished scan of the image with the digest, the
+// severity of the image is the highest one of the vulns
+func addScanOverview(t *testing.T, digest string, vulns map[string]models.Severity) {
+	require.Nil(t, dao.SetScanJobForImg(digest, 1))
+	sev := models.SevNone
+	for _, s := range vulns {
+		if s > sev {
+			sev = s
+		}
+	}
+	require.Nil(t, dao.UpdateImgScanOverview(digest, "clair", "key", sev, &models.ComponentsOverview{}, vulns))
+}
+
+func TestCheckVulnerability(t *testing.T) {
+	defer dao.ClearTable(models.ScanOverviewTable)
+	// a job has been submitted but never finished
+	require.Nil(t, dao.SetScanJobForImg("sha256:unfinished", 1))
+	addScanOverview(t, "sha256:clean", nil)
+	addScanOverview(t, "sha256:medium", map[string]models.Severity{
+		"CVE-2016-2177": models.SevMedium,
+		"CVE-2016-2178": models.SevLow,
+	})
+
+	cases := []struct {
+		digests     []string
+		threshold   models.Severity
+		allowlisted map[string]bool
+		pass        bool
+	}{
+		{nil, models.SevHigh, nil, false},
+		{[]string{""}, models.SevHigh, nil, false},
+		{[]string{"sha256:notscanned"}, models.SevHigh, nil, false},
+		{[]string{"sha256:unfinished"}, models.SevHigh, nil, false},
+		{[]string{"sha256:clean"}, models.SevNone, nil, true},
+		{[]string{"sha256:medium"}, models.SevHigh, nil, true},
+		{[]string{"sha256:medium"}, models.SevMedium, nil, false},
+		{[]string{"sha256:medium"}, models.SevLow, nil, false},
+		{[]string{"sha256:medium"}, models.SevMedium, map[string]bool{"CVE-2016-2177": true}, true},
+		{[]string{"sha256:medium"}, models.SevNone, map[string]bool{"CVE-2016-2177": true, "CVE-2016-2178": true}, true},
+		// the images referenced by a manifest list
+		{[]string{"sha256:clean", "sha256:medium"}, models.SevHigh, nil, true},
+		{[]string{"sha256:clean", "sha256:medium"}, models.SevMedium, nil, false},
+		{[]string{"sha256:medium", "sha256:clean"}, models.SevMedium, nil, false},
+		{[]string{"sha256:clean", "sha256:notscanned"}, models.SevHigh, nil, false},
+	}
+	for _, c := range cases {
+		pass, msg, err := checkVulnerability(c.digests, c.threshold, c.allowlisted)
+		require.Nil(t, err)
+		assert.Equal(t, c.pass, pass, "digests: %v, threshold: %s, allowlisted: %v", c.digests, c.threshold, c.allowlisted)
+		assert.Equal(t, c.pass, len(msg) == 0)
+	}
+}
+
+// manifestHandler mocks the registry which returns the manifest with the digest
+type manifestHandler struct {
+	status    int
+	digest    string
+	mediaType string
+	payload   string
+	called    bool
+}
+
+func (mh *manifestHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	mh.called = true
+	rw.Header().Set("Docker-Content-Digest", mh.digest)
+	rw.Header().Set("Content-Type", mh.mediaType)
+	rw.WriteHeader(mh.status)
+	rw.Write([]byte(mh.payload))
+}
+
+// manifestList returns a manifest list which references the manifests with the digests
+func manifestList(digests ...string) string {
+	manifests := []string{}
+	for _, d := range digests {
+		manifests = append(manifests, fmt.Sprintf(`{"mediaType":"%s","size":528,"digest":"%s","platform":{"architecture":"amd64","os":"linux"}}`,
+			schema2.MediaTypeManifest, d))
+	}
+	return fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","manifests":[%s]}`,
+		registry.MediaTypeManifestList, strings.Join(manifests, ","))
+}
+
+func TestVulnerableHandler(t *testing.T) {
+	name := "project_for_test_vulnerable_handler"
+	id, err := config.GlobalProjectMgr.Create(&models.Project{
+		Name:                               name,
+		OwnerID:                            1,
+		PreventVulnerableImagesFromRunning: true,
+		PreventVulnerableImagesFromRunningSeverity: "medium",
+	})
+	require.Nil(t, err)
+	defer config.GlobalProjectMgr.Delete(id)
+	defer dao.ClearTable(models.ScanOverviewTable)
+	addScanOverview(t, "sha256:low", map[string]models.Severity{"CVE-2016-2178": models.SevLow})
+	addScanOverview(t, "sha256:medium", map[string]models.Severity{"CVE-2016-2177": models.SevMedium})
+
+	img := imageInfo{name + "/busybox", "latest", name}
+	cases := []struct {
+		img       *imageInfo
+		status    int
+		digest    string
+		mediaType string
+		payload   string
+		code      int
+	}{
+		// not a request to pull manifest
+		{nil, http.StatusOK, "sha256:medium", schema2.MediaTypeManifest, "manifest", http.StatusOK},
+		// the response of registry is passed through if it isn't 200
+		{&img, http.StatusNotFound, "", "application/json", "not found", http.StatusNotFound},
+		{&img, http.StatusUnauthorized, "", "application/json", "unauthorized", http.StatusUnauthorized},
+		{&img, http.StatusOK, "sha256:low", schema2.MediaTypeManifest, "manifest", http.StatusOK},
+		{&img, http.StatusOK, "sha256:medium", schema2.MediaTypeManifest, "manifest", http.StatusPreconditionFailed},
+		{&img, http.StatusOK, "sha256:notscanned", schema2.MediaTypeManifest, "manifest", http.StatusPreconditionFailed},
+		// the list itself is never scanned, the images it references are checked
+		{&img, http.StatusOK, "sha256:list", registry.MediaTypeManifestList, manifestList("sha256:low"), http.StatusOK},
+		{&img, http.StatusOK, "sha256:list", registry.MediaTypeManifestList, manifestList("sha256:low", "sha256:medium"),
+			http.StatusPreconditionFailed},
+		{&img, http.StatusOK, "sha256:list", registry.MediaTypeManifestList, manifestList("sha256:low", "sha256:notscanned"),
+			http.StatusPreconditionFailed},
+	}
+	for _, c := range cases {
+		next := &manifestHandler{status: c.status, digest: c.digest, mediaType: c.mediaType, payload: c.payload}
+		req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1/v2/"+img.repository+"/manifests/latest", nil)
+		require.Nil(t, err)
+		if c.img != nil {
+			req = req.WithContext(context.WithValue(req.Context(), imageInfoCtxKey, *c.img))
+		}
+		rec := httptest.NewRecorder()
+		vulnerableHandler{next}.ServeHTTP(rec, req)
+		assert.True(t, next.called)
+		assert.Equal(t, c.code, rec.Code, "status: %d, digest: %s", c.status, c.digest)
+		if c.code == c.status {
+			assert.Equal(t, c.payload, rec.Body.String())
+			assert.Equal(t, c.digest, rec.Header().Get("Docker-Content-Digest"))
+		}
+	}
+}
+
+func TestEffectiveSeverity(t *testing.T) {
+	assert := assert.New(t)
+	// scanned before the severity of each CVE is recorded
+	overview := &models.ImgScanOverview{
+		Sev: int(models.SevHigh),
+	}
+	assert.Equal(models.SevHigh, effectiveSeverity(overview, map[string]bool{"CVE-2016-2177": true}))
+
+	overview.Vulns = map[string]models.Severity{
+		"CVE-2016-2177": models.SevHigh,
+		"CVE-2016-2178": models.SevLow,
+	}
+	assert.Equal(models.SevHigh, effectiveSeverity(overview, nil))
+	assert.Equal(models.SevLow, effectiveSeverity(overview, map[string]bool{"CVE-2016-2177": true}))
+	assert.Equal(models.SevNone, effectiveSeverity(overview, map[string]bool{
+		"CVE-2016-2177": true,
+		"CVE-2016-2178": true,
+	}))
+}
+
 func TestMatchNotaryDigest(t *testing.T) {
 	assert := assert.New(t)
 	//The data from common/utils/notary/helper_test.go
@@ -2,8 +2,12 @@ package proxy
 
 import (
 	//	"github.com/vmware/harbor/src/ui/api"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils/clair"
 	"github.com/vmware/harbor/src/common/utils/log"
 	"github.com/vmware/harbor/src/common/utils/notary"
+	"github.com/vmware/harbor/src/common/utils/registry"
 	"github.com/vmware/harbor/src/ui/config"
 	"github.com/vmware/harbor/src/ui/projectmanager"
 	"github.com/vmware/harbor/src/ui/projectmanager/pms"
@@ -51,8 +55,12 @@ func MatchPullManifest(req *http.Request) (bool, string, string) {
 type policyChecker interface {
 	// contentTrustEnabled returns whether a project has enabled content trust.
 	contentTrustEnabled(name string) bool
-	// vulnerableEnabled  returns whether a project has enabled content trust.
-	vulnerableEnabled(name string) bool
+	// vulnerablePolicy returns whether a project has enabled the policy to prevent vulnerable images from running,
+	// and the severity threshold at or above which an image will be blocked.
+	vulnerablePolicy(name string) (bool, models.Severity)
+	// allowlistedCVEs returns the CVEs allowlisted by the system or the project, they are excluded
+	// when checking the vulnerability of an image.
+	allowlistedCVEs(name string) (map[string]bool, error)
 }
 
 //For testing
@@ -61,9 +69,17 @@ type envPolicyChecker struct{}
 func (ec envPolicyChecker) contentTrustEnabled(name string) bool {
 	return os.Getenv("PROJECT_CONTENT_TRUST") == "1"
 }
-func (ec envPolicyChecker) vulnerableEnabled(name string) bool {
-	// TODO: May need get more information in vulnerable policies.
-	return os.Getenv("PROJECT_VULNERABBLE") == "1"
+func (ec envPolicyChecker) vulnerablePolicy(name string) (bool, models.Severity) {
+	return os.Getenv("PROJECT_VULNERABBLE") == "1", clair.ParseClairSev(os.Getenv("PROJECT_SEVERITY"))
+}
+func (ec envPolicyChecker) allowlistedCVEs(name string) (map[string]bool, error) {
+	cves := map[string]bool{}
+	for _, cve := range strings.Split(os.Getenv("PROJECT_CVE_ALLOWLIST"), ",") {
+		if len(cve) > 0 {
+			cves[cve] = true
+		}
+	}
+	return cves, nil
 }
 
 type pmsPolicyChecker struct {
@@ -76,10 +92,33 @@ func (pc pmsPolicyChecker) contentTrustEnabled(name string) bool {
 		log.Errorf("Unexpected error when getting the project, error: %v", err)
 		return true
 	}
+	if project == nil {
+		log.Errorf("Project %s not found when checking content trust policy", name)
+		return true
+	}
 	return project.EnableContentTrust
 }
-func (pc pmsPolicyChecker) vulnerableEnabled(name string) bool {
-	return true
+func (pc pmsPolicyChecker) vulnerablePolicy(name string) (bool, models.Severity) {
+	project, err := pc.pm.Get(name)
+	if err != nil {
+		log.Errorf("Unexpected error when getting the project, error: %v", err)
+		return true, models.SevNone
+	}
+	if project == nil {
+		log.Errorf("Project %s not found when checking vulnerability policy", name)
+		return true, models.SevNone
+	}
+	return project.PreventVulnerableImagesFromRunning, clair.ParseClairSev(project.PreventVulnerableImagesFromRunningSeverity)
+}
+func (pc pmsPolicyChecker) allowlistedCVEs(name string) (map[string]bool, error) {
+	project, err := pc.pm.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project %s not found", name)
+	}
+	return dao.GetAllowlistedCVEs(project.ProjectID)
 }
 
 // newPMSPolicyChecker returns an instance of an pmsPolicyChecker
@@ -94,7 +133,7 @@ func getPolicyChecker() policyChecker {
 	if config.WithAdmiral() {
 		return newPMSPolicyChecker(pms.NewProjectManager(config.AdmiralEndpoint(), ""))
 	}
-	return EnvChecker
+	return newPMSPolicyChecker(config.GlobalProjectMgr)
 }
 
 type imageInfo struct {
@@ -195,6 +234,124 @@ func (cth contentTrustHandler) ServeHTTP(rw http.ResponseWriter, req *http.Reque
 	}
 }
 
+type vulnerableHandler struct {
+	next http.Handler
+}
+
+func (vh vulnerableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	imgRaw := req.Context().Value(imageInfoCtxKey)
+	if imgRaw == nil || !config.ScanEnabled() {
+		vh.next.ServeHTTP(rw, req)
+		return
+	}
+	img, _ := req.Context().Value(imageInfoCtxKey).(imageInfo)
+	checker := getPolicyChecker()
+	enabled, threshold := checker.vulnerablePolicy(img.projectName)
+	if !enabled {
+		vh.next.ServeHTTP(rw, req)
+		return
+	}
+	allowlisted, err := checker.allowlistedCVEs(img.projectName)
+	if err != nil {
+		log.Errorf("Failed to get the CVE allowlist of project %s, error: %v", img.projectName, err)
+		http.Error(rw, "Failed to get the CVE allowlist, please check the log", http.StatusInternalServerError)
+		return
+	}
+	//The digest is only known after the manifest is resolved by registry, let's use recorder
+	rec := httptest.NewRecorder()
+	vh.next.ServeHTTP(rec, req)
+	if rec.Result().StatusCode != http.StatusOK {
+		copyResp(rec, rw)
+		return
+	}
+	digest := rec.Header().Get(http.CanonicalHeaderKey("Docker-Content-Digest"))
+	log.Debugf("digest: %s", digest)
+	digests := []string{digest}
+	// The scan results are recorded for the images referenced by a manifest list rather than the list itself
+	if rec.Header().Get(http.CanonicalHeaderKey("Content-Type")) == registry.MediaTypeManifestList {
+		if digests, err = listedDigests(rec.Body.Bytes()); err != nil {
+			log.Errorf("Failed to parse the manifest list of image: %#v, digest: %s, error: %v", img, digest, err)
+			http.Error(rw, "Failed to parse the manifest list, please check the log", http.StatusInternalServerError)
+			return
+		}
+	}
+	pass, msg, err := checkVulnerability(digests, threshold, allowlisted)
+	if err != nil {
+		log.Errorf("Failed to check the vulnerability of image: %#v, digest: %s, error: %v", img, digest, err)
+		http.Error(rw, "Failed to get the scan result of the image, please check the log", http.StatusInternalServerError)
+		return
+	}
+	if pass {
+		copyResp(rec, rw)
+		return
+	}
+	log.Debugf("image: %#v, digest: %s, failed the vulnerability check: %s", img, digest, msg)
+	http.Error(rw, msg, http.StatusPreconditionFailed)
+}
+
+// listedDigests returns the digests of the manifests referenced by the manifest list
+func listedDigests(payload []byte) ([]string, error) {
+	list := &registry.DeserializedManifestList{}
+	if err := list.UnmarshalJSON(payload); err != nil {
+		return nil, err
+	}
+	digests := []string{}
+	for _, m := range list.Manifests {
+		digests = append(digests, m.Digest.String())
+	}
+	return digests, nil
+}
+
+// checkVulnerability checks the scan results of the images with the digests against the threshold,
+// the allowlisted CVEs are excluded. All the images referenced by a manifest list must have been
+// scanned and the most severe one decides. The returned string explains why the image is blocked
+// when the first returned value is false.
+func checkVulnerability(digests []string, threshold models.Severity, allowlisted map[string]bool) (bool, string, error) {
+	if len(digests) == 0 {
+		return false, "The digest of the image is unknown, unable to check its vulnerability.", nil
+	}
+	sev := models.SevNone
+	for _, digest := range digests {
+		if len(digest) == 0 {
+			return false, "The digest of the image is unknown, unable to check its vulnerability.", nil
+		}
+		overview, err := dao.GetImgScanOverview(digest)
+		if err != nil {
+			return false, "", err
+		}
+		// Sev is 0 when the scan job of the image has never finished.
+		if overview == nil || overview.Sev == 0 {
+			return false, "The image has not been scanned, it is not allowed to be pulled by the project policy.", nil
+		}
+		if s := effectiveSeverity(overview, allowlisted); s > sev {
+			sev = s
+		}
+	}
+	// An image without any vulnerability always passes, even if the threshold is "negligible"
+	// which is parsed as SevNone.
+	if sev > models.SevNone && sev >= threshold {
+		return false, fmt.Sprintf("The severity of vulnerability of the image: %s is equal or higher than the threshold in project setting: %s.",
+			sev, threshold), nil
+	}
+	return true, "", nil
+}
+
+// effectiveSeverity returns the highest severity of the CVEs which are not allowlisted, the
+// severity in the overview is returned as is if the image was scanned before the severity of
+// each CVE is recorded.
+func effectiveSeverity(overview *models.ImgScanOverview, allowlisted map[string]bool) models.Severity {
+	if overview.Vulns == nil || len(allowlisted) == 0 {
+		return models.Severity(overview.Sev)
+	}
+	sev := models.SevNone
+	for cve, s := range overview.Vulns {
+		if !allowlisted[cve] && s > sev {
+			sev = s
+		}
+	}
+	return sev
+}
+
 func matchNotaryDigest(img imageInfo, digest string) (bool, error) {
 	targets, err := notary.GetInternalTargets(NotaryEndpoint, tokenUsername, img.repository)
 	if err != nil {
@@ -202,7 +359,7 @@ func matchNotaryDigest(img imageInfo, digest string) (bool, error) {
 	}
 	for _, t := range targets {
 		if t.Tag == img.tag {
-			log.Debugf("found tag: %s in notary, try to match digest.")
+			log.Debugf("found tag: %s in notary, try to match digest.", img.tag)
 			d, err := notary.DigestFromTarget(t)
 			if err != nil {
 				return false, err
@@ -59,10 +59,18 @@ func initRouters() {
 	beego.Router("/reset", &controllers.CommonController{}, "post:ResetPassword")
 	beego.Router("/userExists", &controllers.CommonController{}, "post:UserExists")
 	beego.Router("/sendEmail", &controllers.CommonController{}, "get:SendEmail")
+	beego.Router("/c/oidc/login", &controllers.CommonController{}, "get:OIDCLogin")
+	beego.Router("/c/oidc/callback", &controllers.CommonController{}, "get:OIDCCallback")
 
 	//API:
 	beego.Router("/api/search", &api.SearchAPI{})
 	beego.Router("/api/projects/:pid([0-9]+)/members/?:mid", &api.ProjectMemberAPI{})
+	beego.Router("/api/projects/:pid([0-9]+)/group_members/?:gid", &api.ProjectGroupMemberAPI{})
+	beego.Router("/api/projects/:pid([0-9]+)/robots/?:id", &api.RobotAPI{})
+	beego.Router("/api/projects/:pid([0-9]+)/cve_allowlist", &api.CVEAllowlistAPI{})
+	beego.Router("/api/system/cve_allowlist", &api.CVEAllowlistAPI{})
+	beego.Router("/api/roles/?:id", &api.RoleAPI{})
+	beego.Router("/api/signing_keys/?:id", &api.SigningKeyAPI{})
 	beego.Router("/api/projects/", &api.ProjectAPI{}, "get:List;post:Post;head:Head")
 	beego.Router("/api/projects/:id([0-9]+)", &api.ProjectAPI{})
 	beego.Router("/api/projects/:id([0-9]+)/publicity", &api.ProjectAPI{}, "put:ToggleProjectPublic")
@@ -77,6 +85,7 @@ func initRouters() {
 	beego.Router("/api/repositories/*/tags/:tag", &api.RepositoryAPI{}, "delete:Delete;get:GetTag")
 	beego.Router("/api/repositories/*/tags", &api.RepositoryAPI{}, "get:GetTags")
 	beego.Router("/api/repositories/*/tags/:tag/scan", &api.RepositoryAPI{}, "post:ScanImage")
+	beego.Router("/api/repositories/*/tags/:tag/vulnerability/details", &api.RepositoryAPI{}, "get:VulnerabilityDetails")
 	beego.Router("/api/repositories/*/tags/:tag/manifest", &api.RepositoryAPI{}, "get:GetManifests")
 	beego.Router("/api/repositories/*/signatures", &api.RepositoryAPI{}, "get:GetSignatures")
 	beego.Router("/api/jobs/replication/", &api.RepJobAPI{}, "get:List")
@@ -93,7 +102,12 @@ func initRouters() {
 	beego.Router("/api/targets/ping", &api.TargetAPI{}, "post:Ping")
 	beego.Router("/api/targets/:id([0-9]+)/ping", &api.TargetAPI{}, "post:PingByID")
 	beego.Router("/api/users/:id/sysadmin", &api.UserAPI{}, "put:ToggleUserAdminRole")
+	beego.Router("/api/users/:id/cli_secret", &api.UserAPI{}, "get:GetCLISecret;put:RegenerateCLISecret")
+	beego.Router("/api/users/:id/totp", &api.UserAPI{}, "get:GetTOTP;post:EnrollTOTP;put:ActivateTOTP;delete:DisableTOTP")
+	beego.Router("/api/users/:id/tokens/?:tid", &api.AccessTokenAPI{})
+	beego.Router("/api/users/:id/sessions/?:sid", &api.UserSessionAPI{})
 	beego.Router("/api/repositories/top", &api.RepositoryAPI{}, "get:GetTopRepos")
+	beego.Router("/api/repositories/scanAll", &api.RepositoryAPI{}, "get:ScanAllStatus;post:ScanAll")
 	beego.Router("/api/logs", &api.LogAPI{})
 	beego.Router("/api/configurations", &api.ConfigAPI{})
 	beego.Router("/api/configurations/reset", &api.ConfigAPI{}, "post:Reset")
@@ -101,14 +115,19 @@ func initRouters() {
 	beego.Router("/api/systeminfo", &api.SystemInfoAPI{}, "get:GetGeneralInfo")
 	beego.Router("/api/systeminfo/volumes", &api.SystemInfoAPI{}, "get:GetVolumeInfo")
 	beego.Router("/api/systeminfo/getcert", &api.SystemInfoAPI{}, "get:GetCert")
+	beego.Router("/api/login_locks", &api.LoginLockAPI{}, "get:List")
+	beego.Router("/api/login_locks/:id([0-9]+)", &api.LoginLockAPI{}, "delete:Delete")
 	beego.Router("/api/ldap/ping", &api.LdapAPI{}, "post:Ping")
 	beego.Router("/api/ldap/users/search", &api.LdapAPI{}, "post:Search")
 	beego.Router("/api/ldap/users/import", &api.LdapAPI{}, "post:ImportUser")
+	beego.Router("/api/ldap/groups/search", &api.LdapAPI{}, "get:SearchGroup")
+	beego.Router("/api/ldap/groups/import", &api.LdapAPI{}, "post:ImportGroup")
 	beego.Router("/api/email/ping", &api.EmailAPI{}, "post:Ping")
 
 	//external service that hosted on harbor process:
 	beego.Router("/service/notifications", &service.NotificationHandler{})
 	beego.Router("/service/token", &token.Handler{})
+	beego.Router("/service/token/jwks", &token.JWKSHandler{})
 
 	beego.Router("/registryproxy/*", &controllers.RegistryProxy{}, "*:Handle")
 	//Error pages
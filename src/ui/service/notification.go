@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vmware/harbor/src/common/dao"
@@ -25,6 +26,7 @@ import (
 	"github.com/vmware/harbor/src/common/utils"
 	"github.com/vmware/harbor/src/common/utils/log"
 	"github.com/vmware/harbor/src/ui/api"
+	"github.com/vmware/harbor/src/ui/config"
 
 	"github.com/astaxie/beego"
 )
@@ -34,9 +36,18 @@ type NotificationHandler struct {
 	beego.Controller
 }
 
-const manifestPattern = `^application/vnd.docker.distribution.manifest.v\d\+(json|prettyjws)`
+const manifestPattern = `^application/vnd.docker.distribution.manifest.(v\d|list.v\d)\+(json|prettyjws)`
 const vicPrefix = "vic/"
 
+// the image pushed again within the interval is not scanned again, as the
+// scan triggered by the previous push covers it
+const scanOnPushInterval = 5 * time.Minute
+
+var pushScans = &scanDeduplicator{
+	interval: scanOnPushInterval,
+	digests:  map[string]time.Time{},
+}
+
 // Post handles POST request, and records audit log or refreshes cache based on event.
 func (n *NotificationHandler) Post() {
 	var notification models.Notification
@@ -102,7 +113,13 @@ func (n *NotificationHandler) Post() {
 					log.Errorf("Error happens when adding repository: %v", err)
 				}
 			}()
+			// the manifests referenced by a manifest list are pushed without
+			// tag, they are replicated and scanned with the list
+			if len(tag) == 0 {
+				continue
+			}
 			go api.TriggerReplicationByRepository(repository, []string{tag}, models.RepOpTransfer)
+			go triggerScanOnPush(project, repository, tag, event.Target.Digest)
 		}
 		if action == "pull" {
 			go func() {
@@ -115,6 +132,69 @@ func (n *NotificationHandler) Post() {
 	}
 }
 
+// triggerScanOnPush scans the pushed image if a scanner is configured and
+// the project enables scanning images on push
+func triggerScanOnPush(project, repository, tag, digest string) {
+	if !config.ScanEnabled() || config.GlobalProjectMgr == nil {
+		return
+	}
+	pro, err := config.GlobalProjectMgr.Get(project)
+	if err != nil {
+		log.Errorf("failed to get project %s: %v", project, err)
+		return
+	}
+	if pro == nil || !pro.AutomaticallyScanImagesOnPush {
+		return
+	}
+
+	key := digest
+	if len(key) == 0 {
+		key = repository + ":" + tag
+	}
+	if !pushScans.acquire(key, time.Now()) {
+		log.Debugf("%s:%s is not scanned as %s has been scanned recently", repository, tag, digest)
+		return
+	}
+	log.Debugf("Scan %s:%s on push.", repository, tag)
+	if err := api.TriggerImageScan(repository, tag); err != nil {
+		log.Errorf("failed to trigger the scan of %s:%s: %v", repository, tag, err)
+		// let the next push retry
+		pushScans.release(key)
+	}
+}
+
+// scanDeduplicator records the digests scanned within the interval, so that
+// the bursts of pushes of the same image don't flood the scan workers
+type scanDeduplicator struct {
+	sync.Mutex
+	interval time.Duration
+	digests  map[string]time.Time
+}
+
+// acquire returns true and records the digest if it hasn't been scanned
+// within the interval
+func (s *scanDeduplicator) acquire(digest string, now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+	for d, t := range s.digests {
+		if now.Sub(t) >= s.interval {
+			delete(s.digests, d)
+		}
+	}
+	if _, exist := s.digests[digest]; exist {
+		return false
+	}
+	s.digests[digest] = now
+	return true
+}
+
+// release removes the digest so that it can be scanned again
+func (s *scanDeduplicator) release(digest string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.digests, digest)
+}
+
 func filterEvents(notification *models.Notification) ([]*models.Event, error) {
 	events := []*models.Event{}
 
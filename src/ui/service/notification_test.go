@@ -0,0 +1,82 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/harbor/src/common/models"
+)
+
+func TestFilterEvents(t *testing.T) {
+	event := func(id, mediaType, tag, action, userAgent string) models.Event {
+		return models.Event{
+			ID:     id,
+			Action: action,
+			Target: &models.Target{
+				MediaType:  mediaType,
+				Repository: "library/busybox",
+				Tag:        tag,
+			},
+			Request: &models.Request{
+				UserAgent: userAgent,
+			},
+		}
+	}
+	notification := &models.Notification{
+		Events: []models.Event{
+			event("schema2", "application/vnd.docker.distribution.manifest.v2+json", "latest", "push", "docker/17.06.0-ce"),
+			event("schema1", "application/vnd.docker.distribution.manifest.v1+prettyjws", "latest", "push", "docker/1.9.0"),
+			event("list", "application/vnd.docker.distribution.manifest.list.v2+json", "latest", "push", "docker/18.02.0-ce"),
+			event("replicated", "application/vnd.docker.distribution.manifest.list.v2+json", "latest", "push", "harbor-registry-client"),
+			event("layer", "application/octet-stream", "", "push", "docker/17.06.0-ce"),
+			event("config", "application/vnd.docker.container.image.v1+json", "", "push", "docker/17.06.0-ce"),
+			event("other", "application/vnd.docker.distribution.manifest.v2+json", "latest", "pull", "curl/7.54.0"),
+		},
+	}
+	events, err := filterEvents(notification)
+	require.Nil(t, err)
+	ids := []string{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"schema2", "schema1", "list", "replicated"}, ids)
+}
+
+func TestScanDeduplicator(t *testing.T) {
+	s := &scanDeduplicator{
+		interval: time.Minute,
+		digests:  map[string]time.Time{},
+	}
+	now := time.Now()
+
+	assert.True(t, s.acquire("sha256:a", now))
+	assert.False(t, s.acquire("sha256:a", now.Add(30*time.Second)))
+	assert.True(t, s.acquire("sha256:b", now.Add(30*time.Second)))
+
+	// the interval passed
+	assert.True(t, s.acquire("sha256:a", now.Add(time.Minute)))
+	assert.Equal(t, 2, len(s.digests))
+
+	// the expired digests are removed
+	assert.True(t, s.acquire("sha256:c", now.Add(3*time.Minute)))
+	assert.Equal(t, 1, len(s.digests))
+
+	s.release("sha256:c")
+	assert.True(t, s.acquire("sha256:c", now.Add(3*time.Minute)))
+}
@@ -35,6 +35,8 @@ const (
 	issuer = "harbor-token-issuer"
 )
 
+// privateKey is the path of the key generated during installation, it is
+// imported as the first signing key
 var privateKey string
 
 func init() {
@@ -115,7 +117,7 @@ func genTokenForUI(username string, service string,
 
 // MakeRawToken makes a valid jwt token based on parms.
 func MakeRawToken(username, service string, access []*token.ResourceActions) (token string, expiresIn int, issuedAt *time.Time, err error) {
-	pk, err := libtrust.LoadKeyFile(privateKey)
+	pk, err := activeSigningKey()
 	if err != nil {
 		return "", 0, nil, err
 	}
@@ -156,12 +156,21 @@ func (rep repositoryFilter) filter(ctx security.Context, a *token.ResourceAction
 	}
 	project := img.namespace
 	permission := ""
-	if ctx.HasAllPerm(project) {
-		permission = "RWM"
-	} else if ctx.HasWritePerm(project) {
-		permission = "RW"
-	} else if ctx.HasReadPerm(project) {
-		permission = "R"
+	if ctx.Can(models.PermRepoPull, project) {
+		permission += "R"
+	}
+	if ctx.Can(models.PermRepoPush, project) {
+		permission += "W"
+	}
+	if ctx.Can(models.PermRepoDelete, project) {
+		permission += "M"
+	}
+
+	// the permissions of some contexts, e.g. robot accounts, are only
+	// granted on some repositories of the project
+	if sc, ok := ctx.(security.RepositoryScopedContext); ok &&
+		!sc.CanAccessRepository(project+"/"+img.repo) {
+		permission = ""
 	}
 
 	a.Actions = permToActions(permission)
@@ -0,0 +1,46 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"net/http"
+
+	"github.com/astaxie/beego"
+	"github.com/docker/libtrust"
+	"github.com/vmware/harbor/src/common/utils/log"
+)
+
+// JWKSHandler handles request on /service/token/jwks, it publishes the
+// public keys which can be used to verify the tokens
+type JWKSHandler struct {
+	beego.Controller
+}
+
+type jwks struct {
+	Keys []libtrust.PublicKey `json:"keys"`
+}
+
+// Get returns the public keys as a JSON Web Key Set
+func (j *JWKSHandler) Get() {
+	keys, err := PublicKeys()
+	if err != nil {
+		log.Errorf("failed to get the public keys: %v", err)
+		j.CustomAbort(http.StatusInternalServerError, "")
+	}
+	j.Data["json"] = &jwks{
+		Keys: keys,
+	}
+	j.ServeJSON()
+}
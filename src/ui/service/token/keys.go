@@ -0,0 +1,296 @@
+// Copyright (c) 2017 VMware, Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/docker/libtrust"
+	"github.com/vmware/harbor/src/common/dao"
+	"github.com/vmware/harbor/src/common/models"
+	"github.com/vmware/harbor/src/common/utils"
+	"github.com/vmware/harbor/src/common/utils/log"
+	"github.com/vmware/harbor/src/ui/config"
+)
+
+// the signing keys are reloaded from database after the interval, so the
+// keys rotated by other UI instances take effect within it
+const keyCacheTTL = time.Minute
+
+// KeyDistributionWindow is the minimum age of the signing keys to be
+// activated. The services which don't support JWKS, e.g. registry and notary,
+// only trust the certificates in their root certificate bundles, so a key must
+// be generated, then its certificate is appended to the bundles and the
+// services are restarted, and the key is activated at last. The window leaves
+// time for that and for the other UI instances to reload the published keys
+const KeyDistributionWindow = 10 * time.Minute
+
+// ErrKeyNotDistributed is returned when activating a signing key which is
+// younger than the distribution window
+var ErrKeyNotDistributed = errors.New("the signing key can not be activated before it is distributed")
+
+// keySet holds the key used to sign new tokens and the public keys of all
+// the keys which are not expired
+type keySet struct {
+	active   libtrust.PrivateKey
+	public   []libtrust.PublicKey
+	loadedAt time.Time
+}
+
+var (
+	keysLock   sync.Mutex
+	cachedKeys *keySet
+)
+
+// getKeySet returns the cached key set, it is reloaded if it's stale
+func getKeySet() (*keySet, error) {
+	keysLock.Lock()
+	defer keysLock.Unlock()
+	if cachedKeys != nil && time.Since(cachedKeys.loadedAt) < keyCacheTTL {
+		return cachedKeys, nil
+	}
+	ks, err := loadKeySet()
+	if err != nil {
+		return nil, err
+	}
+	cachedKeys = ks
+	return ks, nil
+}
+
+// invalidateKeySet makes the key set reloaded on next use
+func invalidateKeySet() {
+	keysLock.Lock()
+	defer keysLock.Unlock()
+	cachedKeys = nil
+}
+
+func loadKeySet() (*keySet, error) {
+	keys, err := dao.ListSigningKeys()
+	if err != nil {
+		return nil, err
+	}
+	activated, err := activateKeyFile(keys)
+	if err != nil {
+		return nil, err
+	}
+	if activated {
+		if keys, err = dao.ListSigningKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	ks := &keySet{
+		loadedAt: time.Now(),
+	}
+	for _, key := range keys {
+		if key.IsExpired() {
+			continue
+		}
+		pk, err := decryptKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if key.Active {
+			ks.active = pk
+		}
+		pub := pk.PublicKey()
+		pub.AddExtendedField("use", "sig")
+		pub.AddExtendedField("alg", "RS256")
+		ks.public = append(ks.public, pub)
+	}
+	if ks.active == nil {
+		return nil, fmt.Errorf("no active signing key")
+	}
+	return ks, nil
+}
+
+// activateKeyFile makes the key in the private key file the active signing
+// key if the file is newer than the active key, and returns whether the keys
+// are changed. The file is rewritten along with the root certificate bundle
+// of registry when prepare regenerates the certificates or they are replaced
+// manually, so the key in it is the one the services trust. The keys rotated
+// via API are newer than the file and are left alone
+func activateKeyFile(keys []*models.SigningKey) (bool, error) {
+	var active *models.SigningKey
+	for _, key := range keys {
+		if key.Active {
+			active = key
+		}
+	}
+	if active != nil {
+		info, err := os.Stat(privateKey)
+		if err != nil {
+			log.Warningf("failed to stat the private key file %s: %v", privateKey, err)
+			return false, nil
+		}
+		if !info.ModTime().After(active.CreationTime) {
+			return false, nil
+		}
+	}
+
+	pk, err := libtrust.LoadKeyFile(privateKey)
+	if err != nil {
+		return false, err
+	}
+	if active != nil && active.KeyID == pk.KeyID() {
+		return false, nil
+	}
+	var id int64
+	for _, key := range keys {
+		if key.KeyID == pk.KeyID() {
+			id = key.ID
+		}
+	}
+	if id == 0 {
+		if id, err = importKey(pk); err != nil {
+			return false, err
+		}
+	}
+	// the services don't trust the previous key any more, so it is
+	// retired without overlap
+	if err = dao.ActivateSigningKey(id, time.Now().Unix()); err != nil {
+		return false, err
+	}
+	log.Infof("the signing key %s in %s is activated", pk.KeyID(), privateKey)
+	return true, nil
+}
+
+// importKey stores the key loaded from the private key file and returns its ID
+func importKey(pk libtrust.PrivateKey) (int64, error) {
+	key, err := encryptKey(pk)
+	if err != nil {
+		return 0, err
+	}
+	id, err := dao.AddSigningKey(key)
+	if err != nil {
+		// the instances starting together may import the key at the same
+		// time, the conflict on the key ID means it has been imported
+		imported, e := dao.GetSigningKeyByKeyID(key.KeyID)
+		if e != nil || imported == nil {
+			return 0, err
+		}
+		log.Infof("the signing key %s has been imported by another instance", key.KeyID)
+		return imported.ID, nil
+	}
+	log.Infof("the signing key %s is imported from %s", key.KeyID, privateKey)
+	return id, nil
+}
+
+func encryptKey(pk libtrust.PrivateKey) (*models.SigningKey, error) {
+	block, err := pk.PEMBlock()
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := config.SecretKey()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.ReversibleEncrypt(string(pem.EncodeToMemory(block)), secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SigningKey{
+		KeyID:      pk.KeyID(),
+		PrivateKey: encrypted,
+	}, nil
+}
+
+func decryptKey(key *models.SigningKey) (libtrust.PrivateKey, error) {
+	secretKey, err := config.SecretKey()
+	if err != nil {
+		return nil, err
+	}
+	data, err := utils.ReversibleDecrypt(key.PrivateKey, secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing key %s: %v", key.KeyID, err)
+	}
+	return libtrust.UnmarshalPrivateKeyPEM([]byte(data))
+}
+
+// activeSigningKey returns the key used to sign new tokens
+func activeSigningKey() (libtrust.PrivateKey, error) {
+	ks, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+	return ks.active, nil
+}
+
+// PublicKeys returns the public keys of the signing keys which are not
+// expired, including the ones retired but still in the overlap window
+func PublicKeys() ([]libtrust.PublicKey, error) {
+	ks, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+	return ks.public, nil
+}
+
+// GenerateSigningKey generates a new signing key along with a self-signed
+// certificate of it. The key is published via JWKS at once but is not used
+// to sign tokens until it's activated after the distribution window
+func GenerateSigningKey() (*models.SigningKey, error) {
+	pk, err := libtrust.GenerateRSA2048PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := libtrust.GenerateSelfSignedClientCert(pk)
+	if err != nil {
+		return nil, err
+	}
+	key, err := encryptKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	key.Certificate = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	}))
+	if key.ID, err = dao.AddSigningKey(key); err != nil {
+		return nil, err
+	}
+	invalidateKeySet()
+	return key, nil
+}
+
+// ActivateSigningKey makes the key specified by ID sign new tokens, the
+// previous active key is retired and kept published within the overlap so
+// that the tokens signed by it stay valid until they expire. ErrKeyNotDistributed
+// is returned if the key is younger than the distribution window
+func ActivateSigningKey(id int64, overlap time.Duration) error {
+	key, err := dao.GetSigningKey(id)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("signing key %d not found", id)
+	}
+	if time.Since(key.CreationTime) < KeyDistributionWindow {
+		return ErrKeyNotDistributed
+	}
+	if err := dao.ActivateSigningKey(id, time.Now().Add(overlap).Unix()); err != nil {
+		return err
+	}
+	if err := dao.DeleteExpiredSigningKeys(); err != nil {
+		log.Errorf("failed to delete expired signing keys: %v", err)
+	}
+	invalidateKeySet()
+	return nil
+}
@@ -16,10 +16,12 @@ package token
 import (
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/docker/distribution/registry/auth/token"
+	"github.com/docker/libtrust"
 	"github.com/stretchr/testify/assert"
 
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
@@ -28,7 +30,9 @@ import (
 	"path"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/vmware/harbor/src/common/models"
 	"github.com/vmware/harbor/src/common/utils/test"
 	"github.com/vmware/harbor/src/ui/config"
 )
@@ -101,8 +105,8 @@ type harborClaims struct {
 
 func TestMakeToken(t *testing.T) {
 	pk, crt := getKeyAndCertPath()
-	//overwrite the config values for testing.
-	privateKey = pk
+	//overwrite the signing key for testing.
+	useKeyFile(t, pk)
 	ra := []*token.ResourceActions{&token.ResourceActions{
 		Type:    "repository",
 		Name:    "10.117.4.142/notary-test/hello-world-2",
@@ -135,6 +139,43 @@ func TestMakeToken(t *testing.T) {
 	assert.Equal(t, claims.Audience, svc, "Audience mismatch")
 }
 
+// useKeyFile caches the key loaded from the file as the active signing key
+// so that the database is not accessed
+func useKeyFile(t *testing.T, path string) libtrust.PrivateKey {
+	key, err := libtrust.LoadKeyFile(path)
+	if err != nil {
+		t.Fatalf("failed to load key file %s: %v", path, err)
+	}
+	keysLock.Lock()
+	defer keysLock.Unlock()
+	cachedKeys = &keySet{
+		active:   key,
+		public:   []libtrust.PublicKey{key.PublicKey()},
+		loadedAt: time.Now().Add(time.Hour),
+	}
+	return key
+}
+
+func TestPublicKeys(t *testing.T) {
+	pk, _ := getKeyAndCertPath()
+	key := useKeyFile(t, pk)
+
+	keys, err := PublicKeys()
+	if err != nil {
+		t.Fatalf("failed to get public keys: %v", err)
+	}
+	data, err := json.Marshal(&jwks{Keys: keys})
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+	set, err := libtrust.UnmarshalPublicKeyJWKSet(data)
+	if err != nil {
+		t.Fatalf("failed to unmarshal JWKS: %v", err)
+	}
+	assert.Equal(t, 1, len(set))
+	assert.Equal(t, key.KeyID(), set[0].KeyID())
+}
+
 func TestPermToActions(t *testing.T) {
 	perm1 := "RWM"
 	perm2 := "MRR"
@@ -216,13 +257,7 @@ func (f *fakeSecurityContext) GetUsername() string {
 func (f *fakeSecurityContext) IsSysAdmin() bool {
 	return f.isAdmin
 }
-func (f *fakeSecurityContext) HasReadPerm(projectIDOrName interface{}) bool {
-	return false
-}
-func (f *fakeSecurityContext) HasWritePerm(projectIDOrName interface{}) bool {
-	return false
-}
-func (f *fakeSecurityContext) HasAllPerm(projectIDOrName interface{}) bool {
+func (f *fakeSecurityContext) Can(permission string, projectIDOrName interface{}) bool {
 	return false
 }
 
@@ -263,6 +298,48 @@ func TestFilterAccess(t *testing.T) {
 	assert.Equal(t, ra2, *a3[0], "Mismatch after registry filter Map")
 }
 
+type fakeRepositoryScopedContext struct {
+	fakeSecurityContext
+	repositories []string
+}
+
+func (f *fakeRepositoryScopedContext) Can(permission string, projectIDOrName interface{}) bool {
+	return permission == models.PermRepoPull || permission == models.PermRepoPush
+}
+func (f *fakeRepositoryScopedContext) CanAccessRepository(repository string) bool {
+	for _, repo := range f.repositories {
+		if repo == repository {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRepositoryFilterWithScopedContext(t *testing.T) {
+	ctx := &fakeRepositoryScopedContext{
+		repositories: []string{"library/app"},
+	}
+	filter := &repositoryFilter{
+		parser: &basicParser{},
+	}
+
+	a := &token.ResourceActions{
+		Type:    "repository",
+		Name:    "library/app",
+		Actions: []string{"pull", "push"},
+	}
+	assert.Nil(t, filter.filter(ctx, a))
+	assert.Equal(t, []string{"push", "pull"}, a.Actions)
+
+	a = &token.ResourceActions{
+		Type:    "repository",
+		Name:    "library/db",
+		Actions: []string{"pull", "push"},
+	}
+	assert.Nil(t, filter.filter(ctx, a))
+	assert.Equal(t, 0, len(a.Actions))
+}
+
 func TestParseScopes(t *testing.T) {
 	assert := assert.New(t)
 	u1 := "/service/token?account=admin&scope=repository%3Alibrary%2Fregistry%3Apush%2Cpull&scope=repository%3Ahello-world%2Fregistry%3Apull&service=harbor-registry"
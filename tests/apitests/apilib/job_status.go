@@ -42,6 +42,12 @@ type JobStatus struct {
 	// The repository's used tag list.
 	Tags []Tags `json:"tags,omitempty"`
 
+	// The number of attempts that have been made.
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// The time when the job will be retried.
+	RetryTime string `json:"retry_time,omitempty"`
+
 	// The creation time of the job.
 	CreationTime string `json:"creation_time,omitempty"`
 
@@ -56,6 +56,18 @@ type Project struct {
 
 	// The number of the repositories under this project.
 	RepoCount int32 `json:"repo_count,omitempty"`
+
+	// Whether content trust is enabled or not.
+	EnableContentTrust bool `json:"enable_content_trust,omitempty"`
+
+	// Whether to prevent the vulnerable images from running.
+	PreventVulnerableImagesFromRunning bool `json:"prevent_vulnerable_images_from_running,omitempty"`
+
+	// The severity at or above which the images can't be pulled.
+	PreventVulnerableImagesFromRunningSeverity string `json:"prevent_vulnerable_images_from_running_severity,omitempty"`
+
+	// Whether to scan images automatically when pushing.
+	AutomaticallyScanImagesOnPush bool `json:"automatically_scan_images_on_push,omitempty"`
 }
 
 type ProjectQuery struct {
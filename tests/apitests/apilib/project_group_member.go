@@ -0,0 +1,47 @@
+/*
+ * Harbor API
+ *
+ * These APIs provide services for manipulating Harbor project.
+ *
+ * OpenAPI spec version: 0.3.0
+ *
+ * Generated by: https://github.com/swagger-api/swagger-codegen.git
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apilib
+
+type ProjectGroupMember struct {
+
+	// The ID of the project.
+	ProjectID int64 `json:"project_id,omitempty"`
+
+	// The ID of the group.
+	GroupID int32 `json:"group_id,omitempty"`
+
+	// The name of the group.
+	GroupName string `json:"group_name,omitempty"`
+
+	// The DN of the LDAP group.
+	LdapGroupDN string `json:"ldap_group_dn,omitempty"`
+
+	// The name of the OIDC group.
+	OIDCGroupName string `json:"oidc_group_name,omitempty"`
+
+	// The ID of the role of the group in the project.
+	RoleID int32 `json:"role_id,omitempty"`
+
+	// The name of the role of the group in the project.
+	RoleName string `json:"role_name,omitempty"`
+}
@@ -29,4 +29,16 @@ type ProjectReq struct {
 
 	// The public status of the project.
 	Public int32 `json:"public,omitempty"`
+
+	// Whether content trust is enabled or not.
+	EnableContentTrust bool `json:"enable_content_trust,omitempty"`
+
+	// Whether to prevent the vulnerable images from running.
+	PreventVulnerableImagesFromRunning bool `json:"prevent_vulnerable_images_from_running,omitempty"`
+
+	// The severity at or above which the images can't be pulled.
+	PreventVulnerableImagesFromRunningSeverity string `json:"prevent_vulnerable_images_from_running_severity,omitempty"`
+
+	// Whether to scan images automatically when pushing.
+	AutomaticallyScanImagesOnPush bool `json:"automatically_scan_images_on_push,omitempty"`
 }
@@ -0,0 +1,38 @@
+/*
+ * Harbor API
+ *
+ * These APIs provide services for manipulating Harbor project.
+ *
+ * OpenAPI spec version: 0.3.0
+ *
+ * Generated by: https://github.com/swagger-api/swagger-codegen.git
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apilib
+
+type RepFilter struct {
+
+	// The kind of the filter, "repository" or "tag".
+	Kind string `json:"kind,omitempty"`
+
+	// The type of the filter, "include" or "exclude".
+	Type string `json:"type,omitempty"`
+
+	// The syntax of the pattern, "glob" or "regex", "glob" is used if it is empty.
+	Syntax string `json:"syntax,omitempty"`
+
+	// The pattern matched against the whole repository name or tag.
+	Pattern string `json:"pattern,omitempty"`
+}
@@ -39,6 +39,9 @@ type RepPolicy struct {
 	// The policy name.
 	Name string `json:"name,omitempty"`
 
+	// The direction of the replication, "push" or "pull".
+	Direction string `json:"direction,omitempty"`
+
 	// The policy's enabled status.
 	Enabled int32 `json:"enabled,omitempty"`
 
@@ -51,6 +54,30 @@ type RepPolicy struct {
 	// The start time of the policy.
 	StartTime string `json:"start_time,omitempty"`
 
+	// The last time the policy was triggered by its schedule.
+	LastRunTime string `json:"last_run_time,omitempty"`
+
+	// The next time the policy will be triggered by its schedule.
+	NextRunTime string `json:"next_run_time,omitempty"`
+
+	// The error of the last scheduled replication.
+	ScheduleError string `json:"schedule_error,omitempty"`
+
+	// The maximum number of attempts of the jobs, 0 means using the global setting.
+	RetryMaxAttempts int32 `json:"retry_max_attempts,omitempty"`
+
+	// The delay in seconds before the first retry, 0 means using the global setting.
+	RetryInitialDelay int32 `json:"retry_initial_delay,omitempty"`
+
+	// The multiplier of the delay after every attempt, 0 means using the global setting.
+	RetryMultiplier float64 `json:"retry_multiplier,omitempty"`
+
+	// The fraction by which the delay is randomized, 0 means using the global setting.
+	RetryJitter float64 `json:"retry_jitter,omitempty"`
+
+	// The filters on repository name and tag.
+	Filters []RepFilter `json:"filters,omitempty"`
+
 	// The create time of the policy.
 	CreationTime string `json:"creation_time,omitempty"`
 
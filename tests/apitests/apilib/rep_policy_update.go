@@ -38,4 +38,19 @@ type RepPolicyUpdate struct {
 
 	// The cron string for schedule job.
 	CronStr string `json:"cron_str,omitempty"`
+
+	// The maximum number of attempts of the jobs, 0 means using the global setting.
+	RetryMaxAttempts int32 `json:"retry_max_attempts,omitempty"`
+
+	// The delay in seconds before the first retry, 0 means using the global setting.
+	RetryInitialDelay int32 `json:"retry_initial_delay,omitempty"`
+
+	// The multiplier of the delay after every attempt, 0 means using the global setting.
+	RetryMultiplier float64 `json:"retry_multiplier,omitempty"`
+
+	// The fraction by which the delay is randomized, 0 means using the global setting.
+	RetryJitter float64 `json:"retry_jitter,omitempty"`
+
+	// The filters on repository name and tag.
+	Filters []RepFilter `json:"filters,omitempty"`
 }
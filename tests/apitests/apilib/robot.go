@@ -0,0 +1,50 @@
+/*
+ * Harbor API
+ *
+ * These APIs provide services for manipulating Harbor project.
+ *
+ * OpenAPI spec version: 0.3.0
+ *
+ * Generated by: https://github.com/swagger-api/swagger-codegen.git
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apilib
+
+type Robot struct {
+
+	// The ID of the robot.
+	Id int64 `json:"id,omitempty"`
+
+	// The name of the robot.
+	Name string `json:"name,omitempty"`
+
+	// The description of the robot.
+	Description string `json:"description,omitempty"`
+
+	// The ID of the project the robot belongs to.
+	ProjectId int64 `json:"project_id,omitempty"`
+
+	// The actions the robot is allowed to do, "pull" and/or "push".
+	Actions []string `json:"actions,omitempty"`
+
+	// The repositories the robot can access, all repositories of the project if it is empty.
+	Repositories []string `json:"repositories,omitempty"`
+
+	// The unix timestamp when the robot expires, 0 means never.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+
+	// Whether the robot is disabled.
+	Disabled bool `json:"disabled,omitempty"`
+}